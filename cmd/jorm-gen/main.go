@@ -2,11 +2,14 @@ package main
 
 import (
 	"database/sql"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
 	"unicode"
@@ -24,27 +27,72 @@ var (
 	pkgName    = flag.String("pkg", "models", "生成的 Go 代码包名")
 	outDir     = flag.String("out", "./models", "代码输出目录")
 	overwrite  = flag.Bool("overwrite", false, "如果文件已存在，是否覆盖")
+	relations  = flag.Bool("relations", true, "根据外键推断并生成 belongs_to/has_many 关联字段")
+	hooks      = flag.Bool("hooks", false, "生成 BeforeInsert/AfterFind 钩子方法桩代码")
+	single     = flag.Bool("single", false, "将所有模型生成到同一个 models.go 文件中")
+	typeMap    = flag.String("typemap", "", "自定义类型映射: JSON 文件路径、JSON 对象或 dbtype=gotype 逗号分隔列表")
+	nullPtr    = flag.Bool("nullable-ptr", false, "可为 NULL 的列生成指针类型 (如 *string, *time.Time)")
+	timeFields = flag.String("time-fields", "created_at;updated_at", "自动时间字段规则: 创建时间列;更新时间列，列名以逗号分隔并支持 * 通配符")
 )
 
-// Model 模板定义
-const modelTemplate = `package {{.Package}}
+// 自动时间字段的列名匹配规则，由 -time-fields 加载
+var (
+	createTimePatterns []string // 生成 auto_time 标签的列
+	updateTimePatterns []string // 生成 auto_update 标签的列
+)
+
+// customTypes 自定义类型映射 (规范化的数据库类型 -> Go 类型)，由 -typemap 加载
+var customTypes map[string]string
+
+// typeImports 记录 Go 类型需要导入的包路径
+var typeImports = map[string]string{
+	"time.Time": "time",
+}
+
+// 文件模板定义，包含包声明、导入块以及一个或多个模型
+const fileTemplate = `package {{.Package}}
+{{- if .Imports}}
 
 import (
-	"time"
+{{- range .Imports}}
+	"{{.}}"
+{{- end}}
 )
+{{- end}}
+{{- range .Models}}
+{{template "model" .}}
+{{- end}}
+`
 
+// Model 模板定义
+const modelTemplate = `{{define "model"}}
 // {{.StructName}} 代表数据库表 {{.RawTableName}} 的模型
 type {{.StructName}} struct {
 {{- range .Fields}}
 	{{.Name}} {{.Type}} ` + "`" + `json:"{{.Column}}" jorm:"{{.Tag}}"` + "`" + ` {{if .Comment}}// {{.Comment}}{{end}}
 {{- end}}
+{{- range .Relations}}
+	{{.Name}} {{.Type}} ` + "`" + `json:"{{.JSONName}},omitempty" jorm:"{{.Tag}}"` + "`" + `
+{{- end}}
 }
 
 // TableName 返回真实的数据库表名
 func (m *{{.StructName}}) TableName() string {
 	return "{{.RawTableName}}"
 }
-`
+{{- if .Hooks}}
+
+// BeforeInsert 在插入记录前调用，可用于设置默认值或数据校验
+func (m *{{.StructName}}) BeforeInsert() error {
+	return nil
+}
+
+// AfterFind 在查询到记录后调用，可用于填充派生字段
+func (m *{{.StructName}}) AfterFind() error {
+	return nil
+}
+{{- end}}
+{{- end}}`
 
 // Field 代表模型中的一个字段
 type Field struct {
@@ -61,14 +109,44 @@ type Field struct {
 	Default    string
 	Size       int
 	ForeignKey string // 外键关联 (Format: Table.Column)
+	RefTable   string // 外键引用的原始表名
+	RefColumn  string // 外键引用的原始列名
+}
+
+// Relation 代表模型中的一个关联字段
+type Relation struct {
+	Name     string // Go 结构体字段名
+	Type     string // Go 类型 (*Struct 或 []Struct)
+	JSONName string // json 标签名
+	Tag      string // jorm 标签
+}
+
+// FileData 代表一个输出文件所需的模板数据
+type FileData struct {
+	Package string       // 包名
+	Imports []string     // 合并去重后的导入包
+	Models  []*ModelData // 文件中包含的模型
 }
 
 // ModelData 代表生成模板所需的数据
 type ModelData struct {
-	Package      string  // 包名
-	StructName   string  // 结构体名
-	RawTableName string  // 原始表名
-	Fields       []Field // 字段列表
+	StructName   string     // 结构体名
+	RawTableName string     // 原始表名
+	Imports      []string   // 需要导入的包
+	Fields       []Field    // 字段列表
+	Relations    []Relation // 关联字段列表
+	Hooks        bool       // 是否生成钩子方法
+}
+
+// foreignKey 代表一个外键引用
+type foreignKey struct {
+	Table  string // 引用的表名
+	Column string // 引用的列名
+}
+
+// tagValue 返回 fk 标签使用的格式 (Struct.Field)
+func (fk foreignKey) tagValue() string {
+	return fmt.Sprintf("%s.%s", snakeToCamel(fk.Table, true), snakeToCamel(fk.Column, true))
 }
 
 func main() {
@@ -90,60 +168,117 @@ func main() {
 	}
 	defer db.Close()
 
+	if err := generate(db); err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	fmt.Println("生成完成！")
+}
+
+// generate 读取表结构并生成所有模型文件
+func generate(db *sql.DB) error {
+	if err := loadTypeMap(*typeMap); err != nil {
+		return err
+	}
+	if err := loadTimeFields(*timeFields); err != nil {
+		return err
+	}
+
 	// 确定需要生成的表
 	var tables []string
+	var err error
 	if *tableName != "" {
 		tables = append(tables, *tableName)
 	} else {
 		tables, err = fetchAllTables(db, *driverName)
 		if err != nil {
-			log.Fatalf("获取表列表失败: %v", err)
+			return fmt.Errorf("获取表列表失败: %w", err)
 		}
 	}
 
 	// 确保输出目录存在
 	if err := os.MkdirAll(*outDir, 0755); err != nil {
-		log.Fatalf("创建输出目录失败: %v", err)
+		return fmt.Errorf("创建输出目录失败: %w", err)
 	}
 
-	// 循环生成每个表的模型
+	// 读取每个表的结构
+	var models []*ModelData
 	for _, table := range tables {
-		if err := generateModel(db, table); err != nil {
-			log.Printf("生成表 %s 的模型失败: %v", table, err)
+		data, err := loadModel(db, table)
+		if err != nil {
+			log.Printf("读取表 %s 的结构失败: %v", table, err)
+			continue
 		}
+		models = append(models, data)
 	}
 
-	fmt.Println("生成完成！")
+	if *relations {
+		buildRelations(models)
+	}
+
+	// 单文件模式：所有模型写入 models.go
+	if *single {
+		if len(models) == 0 {
+			return nil
+		}
+		return writeFile(filepath.Join(*outDir, "models.go"), models)
+	}
+
+	// 循环生成每个表的模型
+	for _, data := range models {
+		fileName := filepath.Join(*outDir, strings.ToLower(data.RawTableName)+".go")
+		if err := writeFile(fileName, []*ModelData{data}); err != nil {
+			log.Printf("生成表 %s 的模型失败: %v", data.RawTableName, err)
+		}
+	}
+	return nil
 }
 
-// generateModel 获取表结构并生成 Go 文件
-func generateModel(db *sql.DB, table string) error {
+// loadModel 获取表结构并构造模板数据
+func loadModel(db *sql.DB, table string) (*ModelData, error) {
 	fields, err := fetchTableInfo(db, *driverName, table)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	structName := snakeToCamel(table, true)
-	fileName := filepath.Join(*outDir, strings.ToLower(table)+".go")
+	return &ModelData{
+		StructName:   snakeToCamel(table, true),
+		RawTableName: table,
+		Imports:      collectImports(fields),
+		Fields:       fields,
+		Hooks:        *hooks,
+	}, nil
+}
 
+// writeFile 将一个或多个模型渲染为 Go 文件
+func writeFile(fileName string, models []*ModelData) error {
 	// 检查文件是否存在
 	if _, err := os.Stat(fileName); err == nil && !*overwrite {
 		log.Printf("文件 %s 已存在，跳过 (使用 -overwrite 覆盖)", fileName)
 		return nil
 	}
 
-	data := ModelData{
-		Package:      *pkgName,
-		StructName:   structName,
-		RawTableName: table,
-		Fields:       fields,
-	}
-
 	// 解析并执行模板
-	tmpl, err := template.New("model").Parse(modelTemplate)
+	tmpl, err := template.New("file").Parse(fileTemplate)
 	if err != nil {
 		return err
 	}
+	if _, err := tmpl.Parse(modelTemplate); err != nil {
+		return err
+	}
+
+	// 合并所有模型的导入并去重
+	seen := make(map[string]bool)
+	data := FileData{Package: *pkgName, Models: models}
+	for _, m := range models {
+		for _, imp := range m.Imports {
+			if !seen[imp] {
+				seen[imp] = true
+				data.Imports = append(data.Imports, imp)
+			}
+		}
+	}
+	sort.Strings(data.Imports)
 
 	f, err := os.Create(fileName)
 	if err != nil {
@@ -155,10 +290,128 @@ func generateModel(db *sql.DB, table string) error {
 		return err
 	}
 
-	log.Printf("已生成模型: %s -> %s", table, fileName)
+	for _, m := range models {
+		log.Printf("已生成模型: %s -> %s", m.RawTableName, fileName)
+	}
 	return nil
 }
 
+// buildRelations 根据外键约束或 xxx_id 命名约定推断表之间的关联，
+// 为引用方生成 belongs_to 字段，为被引用方生成 has_many 字段
+func buildRelations(models []*ModelData) {
+	byTable := make(map[string]*ModelData, len(models))
+	for _, m := range models {
+		byTable[strings.ToLower(m.RawTableName)] = m
+	}
+
+	for _, m := range models {
+		for _, f := range m.Fields {
+			if f.IsPK {
+				continue
+			}
+
+			refTable, refColumn := f.RefTable, f.RefColumn
+			if refTable == "" {
+				// 没有外键约束时，按 xxx_id 约定匹配本次生成的表
+				refTable = guessRefTable(f.Column, byTable)
+				if refTable == "" {
+					continue
+				}
+				refColumn = "id"
+			}
+
+			refStruct := snakeToCamel(refTable, true)
+			fkName := f.Name
+
+			// belongs_to: orders.user_id -> User *Users
+			name := refStruct
+			if base := strings.TrimSuffix(strings.ToLower(f.Column), "_id"); base != strings.ToLower(f.Column) && base != "" {
+				name = snakeToCamel(base, true)
+			}
+			tag := fmt.Sprintf("fk:%s;relation:belongs_to", fkName)
+			if refColumn != "" && !strings.EqualFold(refColumn, "id") {
+				tag += ";references:" + refColumn
+			}
+			m.addRelation(Relation{
+				Name:     name,
+				Type:     "*" + refStruct,
+				JSONName: camelToSnake(name),
+				Tag:      tag,
+			})
+
+			// has_many: users <- orders.user_id => Orders []Orders
+			if ref, ok := byTable[strings.ToLower(refTable)]; ok {
+				many := pluralize(m.StructName)
+				ref.addRelation(Relation{
+					Name:     many,
+					Type:     "[]" + m.StructName,
+					JSONName: camelToSnake(many),
+					Tag:      fmt.Sprintf("fk:%s;relation:has_many", fkName),
+				})
+			}
+		}
+	}
+}
+
+// addRelation 添加关联字段，跳过与已有字段同名的关联
+func (m *ModelData) addRelation(r Relation) {
+	for _, f := range m.Fields {
+		if f.Name == r.Name {
+			return
+		}
+	}
+	for _, existing := range m.Relations {
+		if existing.Name == r.Name {
+			return
+		}
+	}
+	m.Relations = append(m.Relations, r)
+}
+
+// guessRefTable 按 xxx_id 约定查找被引用的表 (xxx 或 xxxs)
+func guessRefTable(column string, byTable map[string]*ModelData) string {
+	col := strings.ToLower(column)
+	if !strings.HasSuffix(col, "_id") {
+		return ""
+	}
+	base := strings.TrimSuffix(col, "_id")
+	for _, candidate := range []string{base, base + "s", base + "es"} {
+		if m, ok := byTable[candidate]; ok {
+			return m.RawTableName
+		}
+	}
+	return ""
+}
+
+// pluralize 返回名称的简单复数形式
+func pluralize(name string) string {
+	switch {
+	case strings.HasSuffix(name, "s"):
+		return name
+	case strings.HasSuffix(name, "y") && len(name) > 1 && !strings.ContainsRune("aeiou", rune(name[len(name)-2])):
+		return name[:len(name)-1] + "ies"
+	default:
+		return name + "s"
+	}
+}
+
+// camelToSnake 将驼峰命名转换为下划线命名
+func camelToSnake(s string) string {
+	var res []rune
+	runes := []rune(s)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				res = append(res, '_')
+			}
+			res = append(res, unicode.ToLower(r))
+		} else {
+			res = append(res, r)
+		}
+	}
+	return string(res)
+}
+
 // fetchAllTables 获取数据库中所有的表名
 func fetchAllTables(db *sql.DB, driver string) ([]string, error) {
 	var query string
@@ -222,15 +475,16 @@ func fetchTableInfo(db *sql.DB, driver, table string) ([]Field, error) {
 			}
 
 			f := Field{
-				Name:       snakeToCamel(name, true),
-				Column:     name,
-				Type:       mapType(dataType),
-				DBType:     dataType,
-				IsPK:       pk == 1,
-				IsNotNull:  notnull == 1,
-				Default:    dfltValue.String,
-				ForeignKey: fkMap[name],
+				Name:      snakeToCamel(name, true),
+				Column:    name,
+				Type:      mapType(dataType),
+				DBType:    dataType,
+				IsPK:      pk == 1,
+				IsNotNull: notnull == 1,
+				Default:   dfltValue.String,
 			}
+			setForeignKey(&f, fkMap)
+			setNullable(&f)
 
 			if f.IsPK && strings.Contains(strings.ToUpper(dataType), "INT") {
 				f.IsAuto = true
@@ -268,17 +522,18 @@ func fetchTableInfo(db *sql.DB, driver, table string) ([]Field, error) {
 			}
 
 			f := Field{
-				Name:       snakeToCamel(field, true),
-				Column:     field,
-				Type:       mapType(typ),
-				DBType:     typ,
-				Comment:    comment,
-				IsPK:       key == "PRI",
-				IsNotNull:  null == "NO",
-				IsUnique:   key == "UNI",
-				Default:    defaultVal.String,
-				ForeignKey: fkMap[field],
+				Name:      snakeToCamel(field, true),
+				Column:    field,
+				Type:      mapType(typ),
+				DBType:    typ,
+				Comment:   comment,
+				IsPK:      key == "PRI",
+				IsNotNull: null == "NO",
+				IsUnique:  key == "UNI",
+				Default:   defaultVal.String,
 			}
+			setForeignKey(&f, fkMap)
+			setNullable(&f)
 
 			// 提取自增
 			if strings.Contains(strings.ToLower(extra), "auto_increment") {
@@ -329,17 +584,18 @@ func fetchTableInfo(db *sql.DB, driver, table string) ([]Field, error) {
 			}
 
 			f := Field{
-				Name:       snakeToCamel(name, true),
-				Column:     name,
-				Type:       mapType(dataType),
-				DBType:     dataType,
-				Comment:    comment.String,
-				IsPK:       isPK == "YES",
-				IsNotNull:  isNullable == "NO",
-				Default:    columnDefault.String,
-				Size:       int(maxLength.Int64),
-				ForeignKey: fkMap[name],
+				Name:      snakeToCamel(name, true),
+				Column:    name,
+				Type:      mapType(dataType),
+				DBType:    dataType,
+				Comment:   comment.String,
+				IsPK:      isPK == "YES",
+				IsNotNull: isNullable == "NO",
+				Default:   columnDefault.String,
+				Size:      int(maxLength.Int64),
 			}
+			setForeignKey(&f, fkMap)
+			setNullable(&f)
 
 			if f.IsPK && strings.Contains(strings.ToLower(f.Default), "nextval") {
 				f.IsAuto = true
@@ -352,8 +608,150 @@ func fetchTableInfo(db *sql.DB, driver, table string) ([]Field, error) {
 	return fields, nil
 }
 
-// mapType 将数据库类型映射为 Go 类型
+// setForeignKey 根据外键信息填充字段的外键属性
+func setForeignKey(f *Field, fkMap map[string]foreignKey) {
+	if fk, ok := fkMap[f.Column]; ok {
+		f.ForeignKey = fk.tagValue()
+		f.RefTable = fk.Table
+		f.RefColumn = fk.Column
+	}
+}
+
+// loadTypeMap 解析 -typemap 参数，支持 JSON 文件路径、JSON 对象
+// 或 "tinyint(1)=bool,decimal=github.com/shopspring/decimal.Decimal" 格式
+func loadTypeMap(spec string) error {
+	customTypes = make(map[string]string)
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil
+	}
+
+	entries := make(map[string]string)
+	if data, err := os.ReadFile(spec); err == nil {
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return fmt.Errorf("解析类型映射文件 %s 失败: %w", spec, err)
+		}
+	} else if strings.HasPrefix(spec, "{") {
+		if err := json.Unmarshal([]byte(spec), &entries); err != nil {
+			return fmt.Errorf("解析类型映射失败: %w", err)
+		}
+	} else {
+		for _, pair := range strings.Split(spec, ",") {
+			dbType, goType, ok := strings.Cut(pair, "=")
+			if !ok {
+				return fmt.Errorf("无效的类型映射 %q，格式应为 dbtype=gotype", pair)
+			}
+			entries[dbType] = goType
+		}
+	}
+
+	for dbType, goType := range entries {
+		customTypes[normalizeDBType(dbType)] = registerGoType(goType)
+	}
+	return nil
+}
+
+// registerGoType 拆分带包路径的类型 (如 github.com/shopspring/decimal.Decimal)，
+// 记录其导入路径并返回模型中使用的类型名 (如 decimal.Decimal)
+func registerGoType(goType string) string {
+	goType = strings.TrimSpace(goType)
+	body := strings.TrimLeft(goType, "*[]")
+	prefix := goType[:len(goType)-len(body)]
+
+	slash := strings.LastIndex(body, "/")
+	dot := strings.Index(body[slash+1:], ".")
+	if dot < 0 {
+		return goType
+	}
+
+	short := body[slash+1:]
+	typeImports[short] = body[:slash+1+dot]
+	return prefix + short
+}
+
+// loadTimeFields 解析 -time-fields 参数，格式为 "创建时间列;更新时间列"，
+// 例如 "created_at,create_time;updated_at,update_time,modified_*"
+func loadTimeFields(spec string) error {
+	createSpec, updateSpec, _ := strings.Cut(spec, ";")
+	createTimePatterns = splitPatterns(createSpec)
+	updateTimePatterns = splitPatterns(updateSpec)
+
+	for _, p := range append(append([]string{}, createTimePatterns...), updateTimePatterns...) {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("无效的时间字段规则 %q: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// splitPatterns 拆分逗号分隔的列名规则并统一为小写
+func splitPatterns(spec string) []string {
+	var patterns []string
+	for _, p := range strings.Split(spec, ",") {
+		if p = strings.ToLower(strings.TrimSpace(p)); p != "" {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
+
+// matchColumn 判断列名是否匹配任一规则
+func matchColumn(column string, patterns []string) bool {
+	column = strings.ToLower(column)
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, column); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeDBType 规范化数据库类型用于映射查找 (小写并去除空白)
+func normalizeDBType(dbType string) string {
+	return strings.ToLower(strings.Join(strings.Fields(dbType), ""))
+}
+
+// collectImports 收集字段类型需要导入的包
+func collectImports(fields []Field) []string {
+	seen := make(map[string]bool)
+	var imports []string
+	for _, f := range fields {
+		path, ok := typeImports[strings.TrimLeft(f.Type, "*[]")]
+		if ok && !seen[path] {
+			seen[path] = true
+			imports = append(imports, path)
+		}
+	}
+	sort.Strings(imports)
+	return imports
+}
+
+// setNullable 在启用 -nullable-ptr 时为可为 NULL 的列使用指针类型，
+// 以便区分 NULL 与零值
+func setNullable(f *Field) {
+	if !*nullPtr || f.IsNotNull || f.IsPK {
+		return
+	}
+	// 指针、切片与 any 本身即可表示 NULL
+	if strings.HasPrefix(f.Type, "*") || strings.HasPrefix(f.Type, "[]") || f.Type == "any" {
+		return
+	}
+	f.Type = "*" + f.Type
+}
+
+// mapType 将数据库类型映射为 Go 类型，优先使用 -typemap 中的自定义映射
 func mapType(dbType string) string {
+	// 先匹配完整类型 (如 tinyint(1))，再匹配基础类型 (如 decimal)
+	normalized := normalizeDBType(dbType)
+	if t, ok := customTypes[normalized]; ok {
+		return t
+	}
+	if idx := strings.Index(normalized, "("); idx != -1 {
+		if t, ok := customTypes[normalized[:idx]]; ok {
+			return t
+		}
+	}
+
 	dbTypeUpper := strings.ToUpper(dbType)
 	dbTypeUpper = strings.TrimSpace(dbTypeUpper)
 
@@ -429,16 +827,15 @@ func generateTag(f Field) string {
 		tags = append(tags, fmt.Sprintf("fk:%s", f.ForeignKey))
 	}
 	// 只对字符串或字节数组类型生成 size 标签
-	if f.Size > 0 && (f.Type == "string" || f.Type == "[]byte") {
+	if baseType := strings.TrimPrefix(f.Type, "*"); f.Size > 0 && (baseType == "string" || baseType == "[]byte") {
 		tags = append(tags, fmt.Sprintf("size:%d", f.Size))
 	}
 
-	// 针对时间字段的特殊处理
-	colLower := strings.ToLower(f.Column)
-	switch colLower {
-	case "created_at":
+	// 针对时间字段的特殊处理 (规则由 -time-fields 指定)
+	switch {
+	case matchColumn(f.Column, createTimePatterns):
 		tags = append(tags, "auto_time")
-	case "updated_at":
+	case matchColumn(f.Column, updateTimePatterns):
 		tags = append(tags, "auto_update")
 	}
 
@@ -506,9 +903,9 @@ func snakeToCamel(s string, upperFirst bool) string {
 }
 
 // fetchForeignKeys 获取表的外键信息
-// 返回: map[columnName]TargetTable.TargetColumn (Struct 格式)
-func fetchForeignKeys(db *sql.DB, driver, table string) (map[string]string, error) {
-	fkMap := make(map[string]string)
+// 返回: map[columnName]foreignKey (引用的原始表名与列名)
+func fetchForeignKeys(db *sql.DB, driver, table string) (map[string]foreignKey, error) {
+	fkMap := make(map[string]foreignKey)
 
 	switch driver {
 	case "mysql":
@@ -529,7 +926,7 @@ func fetchForeignKeys(db *sql.DB, driver, table string) (map[string]string, erro
 			if err := rows.Scan(&col, &refTable, &refCol); err != nil {
 				return nil, err
 			}
-			fkMap[col] = fmt.Sprintf("%s.%s", snakeToCamel(refTable, true), snakeToCamel(refCol, true))
+			fkMap[col] = foreignKey{Table: refTable, Column: refCol}
 		}
 
 	case "postgres":
@@ -553,7 +950,7 @@ func fetchForeignKeys(db *sql.DB, driver, table string) (map[string]string, erro
 			if err := rows.Scan(&col, &refTable, &refCol); err != nil {
 				return nil, err
 			}
-			fkMap[col] = fmt.Sprintf("%s.%s", snakeToCamel(refTable, true), snakeToCamel(refCol, true))
+			fkMap[col] = foreignKey{Table: refTable, Column: refCol}
 		}
 
 	case "sqlite3":
@@ -574,9 +971,9 @@ func fetchForeignKeys(db *sql.DB, driver, table string) (map[string]string, erro
 			}
 			to = nsTo.String
 			if to == "" {
-				to = "ID" // Default to ID if not specified (PK)
+				to = "id" // Default to ID if not specified (PK)
 			}
-			fkMap[from] = fmt.Sprintf("%s.%s", snakeToCamel(refTable, true), snakeToCamel(to, true))
+			fkMap[from] = foreignKey{Table: refTable, Column: to}
 		}
 	}
 	return fkMap, nil
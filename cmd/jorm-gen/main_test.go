@@ -0,0 +1,264 @@
+package main
+
+import (
+	"database/sql"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupGenDB creates a sqlite schema with a users/orders foreign key and points
+// the generator flags at a temporary output directory.
+func setupGenDB(t *testing.T, schema string) *sql.DB {
+	t.Helper()
+	dir := t.TempDir()
+	db, err := sql.Open("sqlite3", filepath.Join(dir, "gen.db"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+
+	*driverName = "sqlite3"
+	*tableName = ""
+	*pkgName = "model"
+	*outDir = filepath.Join(dir, "out")
+	*overwrite = true
+	*relations = true
+	*hooks = false
+	*typeMap = ""
+	*single = false
+	*timeFields = "created_at;updated_at"
+	*nullPtr = false
+	return db
+}
+
+func readGenerated(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(*outDir, name))
+	if err != nil {
+		t.Fatalf("read %s: %v", name, err)
+	}
+	return string(data)
+}
+
+func TestGenerateRelations(t *testing.T) {
+	t.Run("ForeignKeyConstraint", func(t *testing.T) {
+		db := setupGenDB(t, `
+			CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, name VARCHAR(100));
+			CREATE TABLE orders (id INTEGER PRIMARY KEY AUTOINCREMENT, user_id INTEGER REFERENCES users(id), amount REAL);
+		`)
+		if err := generate(db); err != nil {
+			t.Fatalf("generate: %v", err)
+		}
+
+		orders := readGenerated(t, "orders.go")
+		if !strings.Contains(orders, "User *Users `json:\"user,omitempty\" jorm:\"fk:UserID;relation:belongs_to\"`") {
+			t.Errorf("orders.go missing belongs_to field:\n%s", orders)
+		}
+		users := readGenerated(t, "users.go")
+		if !strings.Contains(users, "Orders []Orders `json:\"orders,omitempty\" jorm:\"fk:UserID;relation:has_many\"`") {
+			t.Errorf("users.go missing has_many field:\n%s", users)
+		}
+		if strings.Contains(users, "BeforeInsert") {
+			t.Errorf("hooks should not be generated without -hooks:\n%s", users)
+		}
+	})
+
+	t.Run("NamingConvention", func(t *testing.T) {
+		db := setupGenDB(t, `
+			CREATE TABLE author (id INTEGER PRIMARY KEY AUTOINCREMENT, name VARCHAR(100));
+			CREATE TABLE post (id INTEGER PRIMARY KEY AUTOINCREMENT, author_id INTEGER, title VARCHAR(200));
+		`)
+		if err := generate(db); err != nil {
+			t.Fatalf("generate: %v", err)
+		}
+
+		if post := readGenerated(t, "post.go"); !strings.Contains(post, "Author *Author") {
+			t.Errorf("post.go missing belongs_to field:\n%s", post)
+		}
+		if author := readGenerated(t, "author.go"); !strings.Contains(author, "Posts []Post") {
+			t.Errorf("author.go missing has_many field:\n%s", author)
+		}
+	})
+
+	t.Run("Hooks", func(t *testing.T) {
+		db := setupGenDB(t, `CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, name VARCHAR(100));`)
+		*hooks = true
+		if err := generate(db); err != nil {
+			t.Fatalf("generate: %v", err)
+		}
+
+		users := readGenerated(t, "users.go")
+		for _, want := range []string{"func (m *Users) BeforeInsert() error", "func (m *Users) AfterFind() error"} {
+			if !strings.Contains(users, want) {
+				t.Errorf("users.go missing %q:\n%s", want, users)
+			}
+		}
+	})
+}
+
+func TestGenerateTypeMap(t *testing.T) {
+	schema := `CREATE TABLE product (id INTEGER PRIMARY KEY AUTOINCREMENT, price DECIMAL(10,2), active TINYINT(1), stock TINYINT);`
+
+	t.Run("Inline", func(t *testing.T) {
+		db := setupGenDB(t, schema)
+		*typeMap = "tinyint(1)=bool, decimal=github.com/shopspring/decimal.Decimal"
+		if err := generate(db); err != nil {
+			t.Fatalf("generate: %v", err)
+		}
+
+		product := readGenerated(t, "product.go")
+		for _, want := range []string{
+			"Price decimal.Decimal",
+			"Active bool",
+			"Stock int8",
+			`"github.com/shopspring/decimal"`,
+		} {
+			if !strings.Contains(product, want) {
+				t.Errorf("product.go missing %q:\n%s", want, product)
+			}
+		}
+		if strings.Contains(product, `"time"`) {
+			t.Errorf("product.go should not import time:\n%s", product)
+		}
+	})
+
+	t.Run("JSONFile", func(t *testing.T) {
+		db := setupGenDB(t, schema)
+		file := filepath.Join(t.TempDir(), "types.json")
+		if err := os.WriteFile(file, []byte(`{"TINYINT": "*bool"}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+		*typeMap = file
+		if err := generate(db); err != nil {
+			t.Fatalf("generate: %v", err)
+		}
+
+		if product := readGenerated(t, "product.go"); !strings.Contains(product, "Stock *bool") {
+			t.Errorf("product.go missing custom type:\n%s", product)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		db := setupGenDB(t, schema)
+		*typeMap = "decimal"
+		if err := generate(db); err == nil {
+			t.Error("expected error for malformed type map")
+		}
+	})
+}
+
+func TestGenerateSingleFile(t *testing.T) {
+	db := setupGenDB(t, `
+		CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, name VARCHAR(100), created_at DATETIME);
+		CREATE TABLE orders (id INTEGER PRIMARY KEY AUTOINCREMENT, user_id INTEGER REFERENCES users(id), paid_at DATETIME);
+	`)
+	*single = true
+	if err := generate(db); err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	entries, err := os.ReadDir(*outDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "models.go" {
+		t.Fatalf("expected only models.go, got %v", entries)
+	}
+
+	models := readGenerated(t, "models.go")
+	for _, want := range []string{"type Users struct", "type Orders struct"} {
+		if !strings.Contains(models, want) {
+			t.Errorf("models.go missing %q:\n%s", want, models)
+		}
+	}
+	if n := strings.Count(models, "package model"); n != 1 {
+		t.Errorf("expected one package clause, got %d", n)
+	}
+	if n := strings.Count(models, `"time"`); n != 1 {
+		t.Errorf("expected time imported once, got %d", n)
+	}
+	if _, err := format.Source([]byte(models)); err != nil {
+		t.Errorf("models.go is not valid Go: %v\n%s", err, models)
+	}
+}
+
+func TestGenerateTimeFields(t *testing.T) {
+	schema := `CREATE TABLE article (id INTEGER PRIMARY KEY AUTOINCREMENT, create_time DATETIME, modified_at DATETIME, created_at DATETIME);`
+
+	t.Run("Default", func(t *testing.T) {
+		db := setupGenDB(t, schema)
+		if err := generate(db); err != nil {
+			t.Fatalf("generate: %v", err)
+		}
+
+		article := readGenerated(t, "article.go")
+		if !strings.Contains(article, "column:created_at;auto_time") {
+			t.Errorf("created_at should be tagged auto_time:\n%s", article)
+		}
+		if strings.Contains(article, "column:create_time;auto_time") {
+			t.Errorf("create_time should not be tagged by default:\n%s", article)
+		}
+	})
+
+	t.Run("Custom", func(t *testing.T) {
+		db := setupGenDB(t, schema)
+		*timeFields = "create_time;update_time,modified_*"
+		if err := generate(db); err != nil {
+			t.Fatalf("generate: %v", err)
+		}
+
+		article := readGenerated(t, "article.go")
+		for _, want := range []string{"column:create_time;auto_time", "column:modified_at;auto_update"} {
+			if !strings.Contains(article, want) {
+				t.Errorf("article.go missing %q:\n%s", want, article)
+			}
+		}
+		if strings.Contains(article, "column:created_at;auto_time") {
+			t.Errorf("created_at should not be tagged with custom rules:\n%s", article)
+		}
+	})
+}
+
+func TestGenerateNullablePointers(t *testing.T) {
+	schema := `CREATE TABLE profile (id INTEGER PRIMARY KEY AUTOINCREMENT, nickname VARCHAR(50) NOT NULL, bio VARCHAR(255), birthday DATETIME, avatar BLOB);`
+
+	t.Run("Disabled", func(t *testing.T) {
+		db := setupGenDB(t, schema)
+		if err := generate(db); err != nil {
+			t.Fatalf("generate: %v", err)
+		}
+
+		if profile := readGenerated(t, "profile.go"); !strings.Contains(profile, "Bio string") {
+			t.Errorf("bio should be a plain string by default:\n%s", profile)
+		}
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		db := setupGenDB(t, schema)
+		*nullPtr = true
+		if err := generate(db); err != nil {
+			t.Fatalf("generate: %v", err)
+		}
+
+		profile := readGenerated(t, "profile.go")
+		for _, want := range []string{
+			"ID int32",
+			"Nickname string",
+			"Bio *string `json:\"bio\" jorm:\"column:bio;size:255\"`",
+			"Birthday *time.Time",
+			"Avatar []byte",
+			`"time"`,
+		} {
+			if !strings.Contains(profile, want) {
+				t.Errorf("profile.go missing %q:\n%s", want, profile)
+			}
+		}
+	})
+}
@@ -0,0 +1,376 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/shrek82/jorm/model"
+)
+
+// Association manages the rows linked to one relation field of a loaded record.
+// Many-to-many relations are written through the join table, has-many relations
+// through the foreign key column of the related table.
+// Statements run on the executor of the originating query, so use tx.Model to
+// make Replace atomic, and pass through the middleware chain like any other query.
+type Association struct {
+	query    *Query
+	ownerPK  any
+	relation *model.Relation
+	err      error
+}
+
+// Association returns a helper to attach and detach related records of the given
+// relation field on the value passed to Model. The value must have a non-zero primary key.
+func (q *Query) Association(name string) *Association {
+	a := &Association{query: q, err: q.err}
+	if a.err != nil {
+		return a
+	}
+	if q.model == nil || q.value == nil {
+		a.err = fmt.Errorf("association %s requires a model value", name)
+		return a
+	}
+
+	relation, err := q.model.GetRelation(name)
+	if err != nil {
+		a.err = fmt.Errorf("%w: %v", ErrRelationNotFound, err)
+		return a
+	}
+	if relation.Type != model.RelationManyToMany && relation.Type != model.RelationHasMany {
+		a.err = fmt.Errorf("association %s: only many_to_many and has_many relations are supported", name)
+		return a
+	}
+	if relation.Model == nil {
+		fieldType := getRelationFieldType(q.model, relation.Name)
+		if fieldType == nil {
+			a.err = fmt.Errorf("association %s: cannot resolve related type", name)
+			return a
+		}
+		relModel, err := model.GetModel(reflect.New(fieldType).Interface())
+		if err != nil {
+			a.err = err
+			return a
+		}
+		relation.Model = relModel
+	}
+	if q.model.PKField == nil || relation.Model.PKField == nil {
+		a.err = fmt.Errorf("association %s: %w: both models need a primary key", name, ErrInvalidModel)
+		return a
+	}
+
+	owner := reflect.ValueOf(q.value)
+	for owner.Kind() == reflect.Ptr {
+		owner = owner.Elem()
+	}
+	if owner.Kind() != reflect.Struct {
+		a.err = fmt.Errorf("association %s requires a struct value", name)
+		return a
+	}
+	pk := q.model.PKField.Accessor(owner)
+	if pk.IsZero() {
+		a.err = fmt.Errorf("association %s: owner primary key is zero", name)
+		return a
+	}
+
+	a.ownerPK = pk.Interface()
+	a.relation = relation
+	return a
+}
+
+// Error returns the error that occurred while resolving the association, if any.
+func (a *Association) Error() error {
+	return a.err
+}
+
+// Append links the related records to the owner. Records with a zero primary key are
+// inserted first. Pairs already present in a join table are skipped.
+func (a *Association) Append(related ...any) error {
+	if a.err != nil {
+		return a.err
+	}
+	ids, err := a.prepare(related)
+	if err != nil || len(ids) == 0 {
+		return err
+	}
+
+	if a.relation.Type == model.RelationHasMany {
+		return a.setForeignKey(a.ownerPK, ids)
+	}
+
+	existing, err := a.linkedRefs(ids)
+	if err != nil {
+		return err
+	}
+	d := a.query.db.dialect
+	insertSQL, _ := d.InsertSQL(a.relation.JoinTable, []string{d.Quote(a.relation.JoinFK), d.Quote(a.relation.JoinRef)})
+	for _, id := range ids {
+		if existing[fmt.Sprint(id)] {
+			continue
+		}
+		existing[fmt.Sprint(id)] = true
+		if err := a.exec(OpInsert, a.relation.JoinTable, insertSQL, a.ownerPK, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete unlinks the related records from the owner. For many-to-many relations the
+// join rows are removed, for has-many relations the foreign key is set to NULL.
+// The related records themselves are kept.
+func (a *Association) Delete(related ...any) error {
+	if a.err != nil {
+		return a.err
+	}
+	ids, err := a.primaryKeys(related)
+	if err != nil || len(ids) == 0 {
+		return err
+	}
+
+	if a.relation.Type == model.RelationHasMany {
+		b := NewBuilder(a.query.db.dialect)
+		defer PutBuilder(b)
+		b.SetTable(a.relation.Model.TableName)
+		b.Where(a.query.db.dialect.Quote(a.foreignKeyColumn())+" = ?", a.ownerPK)
+		b.WhereIn(a.query.db.dialect.Quote(a.relation.Model.PKField.Column), ids)
+		sqlStr, args := b.BuildUpdate(map[string]any{a.foreignKeyColumn(): nil})
+		if err := b.Err(); err != nil {
+			return err
+		}
+		return a.exec(OpUpdate, a.relation.Model.TableName, sqlStr, args...)
+	}
+
+	b := NewBuilder(a.query.db.dialect)
+	defer PutBuilder(b)
+	b.SetTable(a.relation.JoinTable)
+	b.Where(a.query.db.dialect.Quote(a.relation.JoinFK)+" = ?", a.ownerPK)
+	b.WhereIn(a.query.db.dialect.Quote(a.relation.JoinRef), ids)
+	sqlStr, args := b.BuildDelete()
+	if err := b.Err(); err != nil {
+		return err
+	}
+	return a.exec(OpDelete, a.relation.JoinTable, sqlStr, args...)
+}
+
+// Replace unlinks every record currently associated with the owner and links the
+// given ones instead. Calling it without arguments clears the association.
+func (a *Association) Replace(related ...any) error {
+	if a.err != nil {
+		return a.err
+	}
+	ids, err := a.prepare(related)
+	if err != nil {
+		return err
+	}
+
+	d := a.query.db.dialect
+	b := NewBuilder(d)
+	defer PutBuilder(b)
+	var sqlStr string
+	var args []any
+	op, table := OpDelete, a.relation.JoinTable
+	if a.relation.Type == model.RelationHasMany {
+		op, table = OpUpdate, a.relation.Model.TableName
+		b.SetTable(table)
+		b.Where(d.Quote(a.foreignKeyColumn())+" = ?", a.ownerPK)
+		sqlStr, args = b.BuildUpdate(map[string]any{a.foreignKeyColumn(): nil})
+	} else {
+		b.SetTable(table)
+		b.Where(d.Quote(a.relation.JoinFK)+" = ?", a.ownerPK)
+		sqlStr, args = b.BuildDelete()
+	}
+	if err := b.Err(); err != nil {
+		return err
+	}
+	if err := a.exec(op, table, sqlStr, args...); err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return a.Append(related...)
+}
+
+// prepare inserts related records that have no primary key yet and returns the
+// primary keys of all records.
+func (a *Association) prepare(related []any) ([]any, error) {
+	ids := make([]any, 0, len(related))
+	for _, r := range related {
+		v, err := a.relatedValue(r)
+		if err != nil {
+			return nil, err
+		}
+		pk := a.relation.Model.PKField.Accessor(v)
+		if !pk.IsZero() {
+			ids = append(ids, pk.Interface())
+			continue
+		}
+		if !v.CanAddr() {
+			return nil, fmt.Errorf("association %s: new records must be passed as pointers", a.relation.Name)
+		}
+		if a.relation.Type == model.RelationHasMany {
+			a.assignForeignKey(v)
+		}
+		ptr := v.Addr().Interface()
+		if _, err := a.query.db.newQuery(a.query.executor).WithContext(a.query.ctx).Model(ptr).Insert(ptr); err != nil {
+			return nil, err
+		}
+		ids = append(ids, a.relation.Model.PKField.Accessor(v).Interface())
+	}
+	return ids, nil
+}
+
+// primaryKeys returns the primary keys of the related records without inserting anything.
+func (a *Association) primaryKeys(related []any) ([]any, error) {
+	ids := make([]any, 0, len(related))
+	for _, r := range related {
+		v, err := a.relatedValue(r)
+		if err != nil {
+			return nil, err
+		}
+		pk := a.relation.Model.PKField.Accessor(v)
+		if pk.IsZero() {
+			return nil, fmt.Errorf("association %s: related record has a zero primary key", a.relation.Name)
+		}
+		ids = append(ids, pk.Interface())
+	}
+	return ids, nil
+}
+
+// relatedValue dereferences r and checks it matches the related model type.
+func (a *Association) relatedValue(r any) (reflect.Value, error) {
+	v := reflect.ValueOf(r)
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if !v.IsValid() || v.Type() != a.relation.Model.OriginalType {
+		return reflect.Value{}, fmt.Errorf("association %s: expected %s, got %T", a.relation.Name, a.relation.Model.OriginalType, r)
+	}
+	return v, nil
+}
+
+// foreignKeyColumn resolves the has-many foreign key, which may be given as a field name.
+func (a *Association) foreignKeyColumn() string {
+	if f := a.foreignKeyField(); f != nil {
+		return f.Column
+	}
+	return a.relation.ForeignKey
+}
+
+func (a *Association) foreignKeyField() *model.Field {
+	if f, ok := a.relation.Model.FieldMap[a.relation.ForeignKey]; ok {
+		return f
+	}
+	for _, f := range a.relation.Model.Fields {
+		if f.Name == a.relation.ForeignKey {
+			return f
+		}
+	}
+	return nil
+}
+
+// assignForeignKey sets the owner's primary key on the foreign key field of a related struct.
+func (a *Association) assignForeignKey(v reflect.Value) {
+	f := a.foreignKeyField()
+	if f == nil || !v.CanSet() {
+		return
+	}
+	fv := f.Accessor(v)
+	pk := reflect.ValueOf(a.ownerPK)
+	if pk.Type().ConvertibleTo(fv.Type()) {
+		fv.Set(pk.Convert(fv.Type()))
+	}
+}
+
+// setForeignKey points the related rows with the given primary keys at fk.
+func (a *Association) setForeignKey(fk any, ids []any) error {
+	b := NewBuilder(a.query.db.dialect)
+	defer PutBuilder(b)
+	b.SetTable(a.relation.Model.TableName)
+	b.WhereIn(a.query.db.dialect.Quote(a.relation.Model.PKField.Column), ids)
+	sqlStr, args := b.BuildUpdate(map[string]any{a.foreignKeyColumn(): fk})
+	if err := b.Err(); err != nil {
+		return err
+	}
+	return a.exec(OpUpdate, a.relation.Model.TableName, sqlStr, args...)
+}
+
+// linkedRefs returns which of ids are already linked to the owner in the join table.
+func (a *Association) linkedRefs(ids []any) (map[string]bool, error) {
+	d := a.query.db.dialect
+	b := NewBuilder(d)
+	defer PutBuilder(b)
+	b.Select(d.Quote(a.relation.JoinRef))
+	b.SetTable(a.relation.JoinTable)
+	b.Where(d.Quote(a.relation.JoinFK)+" = ?", a.ownerPK)
+	b.WhereIn(d.Quote(a.relation.JoinRef), ids)
+	sqlStr, args := b.BuildSelect()
+	if err := b.Err(); err != nil {
+		return nil, err
+	}
+
+	linked := make(map[string]bool)
+	err := a.run(OpSelect, a.relation.JoinTable, func(ctx context.Context, q *Query) (*Result, error) {
+		start := time.Now()
+		rows, err := q.executor.QueryContext(ctx, sqlStr, args...)
+		q.logSQL(sqlStr, time.Since(start), args...)
+		if err != nil {
+			return &Result{Error: err}, q.handleError(fmt.Errorf("association query failed: %w", err))
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var ref any
+			if err := rows.Scan(&ref); err != nil {
+				return &Result{Error: err}, err
+			}
+			if raw, ok := ref.([]byte); ok {
+				ref = string(raw)
+			}
+			linked[fmt.Sprint(ref)] = true
+		}
+		if err := rows.Err(); err != nil {
+			return &Result{Error: err}, err
+		}
+		return &Result{Data: linked}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return linked, nil
+}
+
+// exec runs a write statement on table as op, so cache middlewares drop the table's results.
+func (a *Association) exec(op OperationType, table, sqlStr string, args ...any) error {
+	return a.run(op, table, func(ctx context.Context, q *Query) (*Result, error) {
+		start := time.Now()
+		res, err := q.executor.ExecContext(ctx, sqlStr, args...)
+		q.logSQL(sqlStr, time.Since(start), args...)
+		if err != nil {
+			return &Result{Error: err}, q.handleError(fmt.Errorf("association execution failed: %w", err))
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return &Result{Error: err}, q.handleError(fmt.Errorf("failed to get rows affected: %w", err))
+		}
+		return &Result{RowsAffected: rows}, nil
+	})
+}
+
+// run passes final through the middleware chain on a query for table, on the executor and
+// context of the originating query.
+func (a *Association) run(op OperationType, table string, final QueryFunc) error {
+	q := a.query.db.newQuery(a.query.executor).WithContext(a.query.ctx).Table(table)
+	defer q.release()
+	if q.err != nil {
+		return q.err
+	}
+	if op == OpSelect {
+		// The links are scanned by final, which a cached result would skip
+		q.Cache(0)
+	}
+	_, err := q.executeWithMiddleware(op, final)
+	return err
+}
@@ -1,6 +1,8 @@
 package core
 
 import (
+	"database/sql/driver"
+	"fmt"
 	"reflect"
 	"sort"
 	"strings"
@@ -17,14 +19,33 @@ type Builder interface {
 	SetTable(name string) Builder
 	// Alias sets a table alias (e.g., "users AS u").
 	Alias(alias string) Builder
+	// QuoteTable sets whether the table name is quoted in SELECT, UPDATE and DELETE
+	// statements (the default). Disable it for table names that are already quoted,
+	// schema-qualified or carry an alias, e.g. "`shop`.`order` o".
+	QuoteTable(quote bool) Builder
 	// Select specifies columns to retrieve (e.g., "id", "name").
 	Select(columns ...string) Builder
+	// SelectRaw adds an expression with "?" placeholders to the select list; its arguments
+	// precede all others in BuildSelect.
+	SelectRaw(expr string, args ...any) Builder
 	// Where adds an AND condition to the WHERE clause.
 	Where(cond string, args ...any) Builder
 	// OrWhere adds an OR condition to the WHERE clause.
 	OrWhere(cond string, args ...any) Builder
 	// WhereIn adds an IN condition for a column and a slice of values.
 	WhereIn(column string, values any) Builder
+	// WhereInTuple adds a multi-column IN condition, one tuple of values per row.
+	WhereInTuple(columns []string, rows [][]any) Builder
+	// WhereGroup merges the WHERE expression of sub into this builder as a single
+	// parenthesized AND group, appending its arguments in order.
+	WhereGroup(sub Builder) Builder
+	// WhereExists adds an AND EXISTS (subquery) condition built from sub's SELECT,
+	// appending its arguments in order.
+	WhereExists(sub Builder) Builder
+	// WhereNotExists adds an AND NOT EXISTS (subquery) condition built from sub's SELECT.
+	WhereNotExists(sub Builder) Builder
+	// FromSubQuery selects from sub's SELECT as a derived table: FROM (SELECT ...) alias.
+	FromSubQuery(sub Builder, alias string) Builder
 	// Joins adds a raw JOIN clause (e.g., "JOIN orders ON orders.user_id = users.id").
 	Joins(query string, args ...any) Builder
 	// GroupBy adds columns for the GROUP BY clause.
@@ -47,6 +68,10 @@ type Builder interface {
 	BuildDelete() (string, []any)
 	// Clone creates a deep copy of the builder.
 	Clone() Builder
+	// Err reports misuse that would make the built SQL invalid: the first error recorded
+	// by a builder method (e.g. ErrInvalidWhereIn, or ErrTooManyParameters by the Build
+	// methods), otherwise ErrNoTable if no table is set.
+	Err() error
 }
 
 // sqlBuilder is the default implementation of the Builder interface.
@@ -55,7 +80,12 @@ type sqlBuilder struct {
 	dialect    dialect.Dialect // Database-specific dialect
 	table      string          // Target table name
 	alias      string          // Table alias
+	rawTable   bool            // Write the table name unquoted, see QuoteTable
+	fromSQL    string          // Derived table SELECT replacing the table in FROM, see FromSubQuery
+	fromArgs   []any           // Derived table arguments
+	err        error           // First misuse recorded by a builder method
 	selectCols []string        // Columns to select
+	selectArgs []any           // Arguments of SelectRaw expressions
 	whereExpr  string          // WHERE clause expression
 	whereArgs  []any           // WHERE clause arguments
 	joins      []string        // JOIN clauses
@@ -89,7 +119,12 @@ func (b *sqlBuilder) Reset(d dialect.Dialect) {
 	b.dialect = d
 	b.table = ""
 	b.alias = ""
+	b.rawTable = false
+	b.fromSQL = ""
+	b.fromArgs = b.fromArgs[:0]
+	b.err = nil
 	b.selectCols = b.selectCols[:0]
+	b.selectArgs = b.selectArgs[:0]
 	b.whereExpr = ""
 	b.whereArgs = b.whereArgs[:0]
 	b.joins = b.joins[:0]
@@ -112,10 +147,19 @@ func (b *sqlBuilder) Clone() Builder {
 
 	nb.table = b.table
 	nb.alias = b.alias
+	nb.rawTable = b.rawTable
+	nb.fromSQL = b.fromSQL
+	if len(b.fromArgs) > 0 {
+		nb.fromArgs = append(nb.fromArgs, b.fromArgs...)
+	}
+	nb.err = b.err
 
 	if len(b.selectCols) > 0 {
 		nb.selectCols = append(nb.selectCols, b.selectCols...)
 	}
+	if len(b.selectArgs) > 0 {
+		nb.selectArgs = append(nb.selectArgs, b.selectArgs...)
+	}
 
 	nb.whereExpr = b.whereExpr
 	if len(b.whereArgs) > 0 {
@@ -156,18 +200,57 @@ func (b *sqlBuilder) SetTable(name string) Builder {
 	return b
 }
 
+// Err returns the first error recorded by a builder method, or ErrNoTable if no table is set.
+func (b *sqlBuilder) Err() error {
+	if b.err != nil {
+		return b.err
+	}
+	if b.table == "" && b.fromSQL == "" {
+		return ErrNoTable
+	}
+	return nil
+}
+
+// setErr records err unless an earlier error is already recorded.
+func (b *sqlBuilder) setErr(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
 // Alias sets a table alias for the query.
 func (b *sqlBuilder) Alias(alias string) Builder {
 	b.alias = strings.TrimSpace(alias)
 	return b
 }
 
+// QuoteTable sets whether the table name is quoted when statements are built.
+func (b *sqlBuilder) QuoteTable(quote bool) Builder {
+	b.rawTable = !quote
+	return b
+}
+
+// quotedTable returns the table name as written in FROM, UPDATE and DELETE clauses.
+func (b *sqlBuilder) quotedTable() string {
+	if b.rawTable {
+		return b.table
+	}
+	return b.dialect.Quote(b.table)
+}
+
 // Select adds the SELECT clause with specified columns.
 func (b *sqlBuilder) Select(columns ...string) Builder {
 	b.selectCols = append(b.selectCols, columns...)
 	return b
 }
 
+// SelectRaw adds expr to the select list, binding args to its "?" placeholders.
+func (b *sqlBuilder) SelectRaw(expr string, args ...any) Builder {
+	b.selectCols = append(b.selectCols, expr)
+	b.selectArgs = append(b.selectArgs, args...)
+	return b
+}
+
 // Where adds the WHERE clause with condition and arguments.
 func (b *sqlBuilder) Where(cond string, args ...any) Builder {
 	if cond == "" {
@@ -199,13 +282,10 @@ func (b *sqlBuilder) OrWhere(cond string, args ...any) Builder {
 // WhereIn adds an IN condition for the specified column and values.
 func (b *sqlBuilder) WhereIn(column string, values any) Builder {
 	v := reflect.ValueOf(values)
-	if !v.IsValid() {
+	if !v.IsValid() || (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) {
+		b.setErr(fmt.Errorf("%w: %s expects a slice or array, got %T", ErrInvalidWhereIn, column, values))
 		return b
 	}
-	kind := v.Kind()
-	if kind != reflect.Slice && kind != reflect.Array {
-		return b.Where(column+" IN (?)", values)
-	}
 	if v.Len() == 0 {
 		return b.Where("1 = 0")
 	}
@@ -228,6 +308,78 @@ func (b *sqlBuilder) WhereIn(column string, values any) Builder {
 	return b.Where(cond, args...)
 }
 
+// WhereInTuple adds a condition matching columns against the value tuples in rows,
+// e.g. (user_id, role_id) IN ((?, ?), (?, ?)). The SQL comes from the dialect, so
+// databases without row constructors get an equivalent OR of AND groups.
+// An empty rows matches nothing.
+func (b *sqlBuilder) WhereInTuple(columns []string, rows [][]any) Builder {
+	if len(rows) == 0 {
+		return b.Where("1 = 0")
+	}
+	args := make([]any, 0, len(columns)*len(rows))
+	for _, row := range rows {
+		args = append(args, row...)
+	}
+	return b.Where(tupleInSQL(b.dialect, columns, len(rows)), args...)
+}
+
+// WhereGroup splices the WHERE expression of another builder into this one as a
+// single parenthesized group (e.g. "a = ? AND ((b = ?) OR (c = ?))").
+// Only the sub-builder's WHERE expression and arguments are used.
+func (b *sqlBuilder) WhereGroup(sub Builder) Builder {
+	sb, ok := sub.(*sqlBuilder)
+	if !ok {
+		return b
+	}
+	if sb.err != nil {
+		b.setErr(sb.err)
+	}
+	if sb.whereExpr == "" {
+		return b
+	}
+	return b.Where(sb.whereExpr, sb.whereArgs...)
+}
+
+// WhereExists adds an EXISTS condition with sub as the subquery.
+func (b *sqlBuilder) WhereExists(sub Builder) Builder {
+	return b.whereSubquery("EXISTS", sub)
+}
+
+// WhereNotExists adds a NOT EXISTS condition with sub as the subquery.
+func (b *sqlBuilder) WhereNotExists(sub Builder) Builder {
+	return b.whereSubquery("NOT EXISTS", sub)
+}
+
+// whereSubquery embeds the SELECT of sub with its "?" placeholders kept, so they are
+// numbered together with the outer query's arguments when the final SQL is built.
+func (b *sqlBuilder) whereSubquery(op string, sub Builder) Builder {
+	sb, ok := sub.(*sqlBuilder)
+	if !ok {
+		return b
+	}
+	if sb.err != nil {
+		b.setErr(sb.err)
+	}
+	subSQL, subArgs := sb.buildSelect()
+	return b.Where(op+" ("+subSQL+")", subArgs...)
+}
+
+// FromSubQuery selects from the SELECT of sub as a derived table named alias,
+// i.e. FROM (SELECT ...) alias. Its "?" placeholders are kept and its arguments come
+// before all others, so they are numbered first when the final SQL is built.
+func (b *sqlBuilder) FromSubQuery(sub Builder, alias string) Builder {
+	sb, ok := sub.(*sqlBuilder)
+	if !ok {
+		return b
+	}
+	if sb.err != nil {
+		b.setErr(sb.err)
+	}
+	b.fromSQL, b.fromArgs = sb.buildSelect()
+	b.alias = strings.TrimSpace(alias)
+	return b
+}
+
 // Joins adds a raw JOIN clause to the query.
 func (b *sqlBuilder) Joins(query string, args ...any) Builder {
 	if !isValidJoinClause(query) {
@@ -299,36 +451,106 @@ func (b *sqlBuilder) Offset(n int) Builder {
 	return b
 }
 
-func (b *sqlBuilder) replacePlaceholders(sql string) string {
+// replacePlaceholders rewrites "?" placeholders to the dialect's placeholders. A slice
+// argument bound to a single "?" is expanded in place, so Where("id IN (?)", ids) becomes
+// "id IN (?, ?, ?)" with one argument per element. A statement binding more arguments
+// than the dialect's MaxPlaceholders, if it reports one, records ErrTooManyParameters, see Err.
+func (b *sqlBuilder) replacePlaceholders(sql string, args []any) (string, []any) {
 	if !strings.Contains(sql, "?") {
-		return sql
+		return sql, args
 	}
 
 	// Reuse b.sb for building the final string with correct placeholders.
 	// Since sql is the result of b.sb.String() from Build* methods, it is safe to reset b.sb.
 	b.sb.Reset()
+	args = expandPlaceholders(&b.sb, sql, args, b.dialect.Placeholder)
+	if limit := maxPlaceholders(b.dialect); limit > 0 && len(args) > limit {
+		b.setErr(fmt.Errorf("%w: the statement binds %d arguments, the database allows %d",
+			ErrTooManyParameters, len(args), limit))
+	}
+	return b.sb.String(), args
+}
+
+// expandPlaceholders writes sql to sb with each "?" replaced by placeholder(index) and
+// returns the arguments matching the written placeholders. Expandable slice arguments
+// get one placeholder per element; an empty slice is written as NULL, matching nothing.
+func expandPlaceholders(sb *strings.Builder, sql string, args []any, placeholder func(int) string) []any {
+	expand := hasExpandable(args)
+	out := args
+	if expand {
+		out = make([]any, 0, len(args))
+	}
 
-	index := 1
+	index, argIndex := 1, 0
 	for {
 		idx := strings.Index(sql, "?")
 		if idx == -1 {
-			b.sb.WriteString(sql)
+			sb.WriteString(sql)
 			break
 		}
-
-		b.sb.WriteString(sql[:idx])
-		b.sb.WriteString(b.dialect.Placeholder(index))
+		sb.WriteString(sql[:idx])
 		sql = sql[idx+1:]
-		index++
+
+		if argIndex < len(args) && expand && isExpandable(args[argIndex]) {
+			v := reflect.ValueOf(args[argIndex])
+			if v.Len() == 0 {
+				sb.WriteString("NULL")
+			}
+			for i := 0; i < v.Len(); i++ {
+				if i > 0 {
+					sb.WriteString(", ")
+				}
+				sb.WriteString(placeholder(index))
+				out = append(out, v.Index(i).Interface())
+				index++
+			}
+		} else {
+			sb.WriteString(placeholder(index))
+			if expand && argIndex < len(args) {
+				out = append(out, args[argIndex])
+			}
+			index++
+		}
+		argIndex++
+	}
+	if expand && argIndex < len(args) {
+		out = append(out, args[argIndex:]...)
+	}
+	return out
+}
+
+func hasExpandable(args []any) bool {
+	for _, arg := range args {
+		if isExpandable(arg) {
+			return true
+		}
+	}
+	return false
+}
+
+// isExpandable reports whether arg is a slice or array to expand into one placeholder
+// per element. []byte and driver.Valuer types (e.g. pq.Array) are bound as single values.
+func isExpandable(arg any) bool {
+	if arg == nil {
+		return false
+	}
+	if _, ok := arg.(driver.Valuer); ok {
+		return false
 	}
-	return b.sb.String()
+	t := reflect.TypeOf(arg)
+	return (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && t.Elem().Kind() != reflect.Uint8
 }
 
 // BuildSelect generates the complete SELECT SQL statement and its arguments.
 func (b *sqlBuilder) BuildSelect() (string, []any) {
+	return b.replacePlaceholders(b.buildSelect())
+}
+
+// buildSelect assembles the SELECT statement with "?" placeholders.
+func (b *sqlBuilder) buildSelect() (string, []any) {
 	b.sb.Reset()
 
-	argCount := len(b.joinArgs) + len(b.whereArgs) + len(b.havingArgs)
+	argCount := len(b.selectArgs) + len(b.fromArgs) + len(b.joinArgs) + len(b.whereArgs) + len(b.havingArgs)
 	if b.limitSet {
 		argCount++
 	}
@@ -346,13 +568,21 @@ func (b *sqlBuilder) BuildSelect() (string, []any) {
 			}
 			b.sb.WriteString(col)
 		}
+		args = append(args, b.selectArgs...)
 	} else {
 		b.sb.WriteString("*")
 	}
 
 	// FROM
 	b.sb.WriteString(" FROM ")
-	b.sb.WriteString(b.dialect.Quote(b.table))
+	if b.fromSQL != "" {
+		b.sb.WriteString("(")
+		b.sb.WriteString(b.fromSQL)
+		b.sb.WriteString(")")
+		args = append(args, b.fromArgs...)
+	} else {
+		b.sb.WriteString(b.quotedTable())
+	}
 	if b.alias != "" {
 		b.sb.WriteString(" ")
 		b.sb.WriteString(b.alias)
@@ -386,17 +616,20 @@ func (b *sqlBuilder) BuildSelect() (string, []any) {
 		b.sb.WriteString(strings.Join(b.orderBy, ", "))
 	}
 
+	limit, offset := -1, -1
 	if b.limitSet {
-		b.sb.WriteString(" LIMIT ?")
-		args = append(args, b.limit)
+		limit = b.limit
 	}
-
 	if b.offsetSet {
-		b.sb.WriteString(" OFFSET ?")
-		args = append(args, b.offset)
+		offset = b.offset
+	}
+	if clause, pageArgs := limitOffsetSQL(b.dialect, limit, offset, len(b.orderBy) > 0); clause != "" {
+		b.sb.WriteString(" ")
+		b.sb.WriteString(clause)
+		args = append(args, pageArgs...)
 	}
 
-	return b.replacePlaceholders(b.sb.String()), args
+	return b.sb.String(), args
 }
 
 // PutBuilder returns a sqlBuilder to the pool for reuse.
@@ -419,7 +652,7 @@ func (b *sqlBuilder) BuildUpdate(data map[string]any) (string, []any) {
 	args := make([]any, 0, len(data)+len(b.whereArgs))
 
 	b.sb.WriteString("UPDATE ")
-	b.sb.WriteString(b.dialect.Quote(b.table))
+	b.sb.WriteString(b.quotedTable())
 	b.sb.WriteString(" SET ")
 
 	// Sort columns to ensure deterministic SQL generation
@@ -444,7 +677,7 @@ func (b *sqlBuilder) BuildUpdate(data map[string]any) (string, []any) {
 		args = append(args, b.whereArgs...)
 	}
 
-	return b.replacePlaceholders(b.sb.String()), args
+	return b.replacePlaceholders(b.sb.String(), args)
 }
 
 // BuildDelete generates the DELETE SQL statement.
@@ -453,7 +686,7 @@ func (b *sqlBuilder) BuildDelete() (string, []any) {
 	args := make([]any, 0, len(b.whereArgs))
 
 	b.sb.WriteString("DELETE FROM ")
-	b.sb.WriteString(b.dialect.Quote(b.table))
+	b.sb.WriteString(b.quotedTable())
 
 	if b.whereExpr != "" {
 		b.sb.WriteString(" WHERE ")
@@ -461,5 +694,5 @@ func (b *sqlBuilder) BuildDelete() (string, []any) {
 		args = append(args, b.whereArgs...)
 	}
 
-	return b.replacePlaceholders(b.sb.String()), args
+	return b.replacePlaceholders(b.sb.String(), args)
 }
@@ -3,10 +3,13 @@ package core
 import (
 	"context"
 	"database/sql"
-	"errors"
+	"database/sql/driver"
 	"fmt"
+	"reflect"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/shrek82/jorm/dialect"
@@ -27,8 +30,29 @@ type Options struct {
 	MaxRetries int
 	// RetryDelay defines the initial duration to wait between connection retry attempts.
 	RetryDelay time.Duration
+	// Cooldown is how long new queries are rejected with ErrConnectionFailed after a
+	// connection-level error. Defaults to 5 seconds; a negative value disables the cooldown.
+	Cooldown time.Duration
+	// RetryableErrorClassifier reports whether an error indicates the database is unreachable
+	// and should trigger the cooldown. Defaults to ClassifyError(err) == ErrConnectionFailed.
+	RetryableErrorClassifier func(error) bool
+	// Location is the time zone used to interpret date/time strings without a zone read
+	// from the database and to write time values. Defaults to time.Local.
+	Location *time.Location
+	// SoftDeleteColumn names the column marking soft-deleted rows in place of deleted_at.
+	// It may hold a *time.Time (set on delete, NULL while active) or a boolean flag
+	// such as is_deleted (true on delete, false while active). Models without the
+	// column fall back to their DeletedAt field.
+	SoftDeleteColumn string
+	// OnConnect runs on every new connection before the pool uses it, e.g. to set session
+	// variables such as MySQL's time_zone or sql_mode. The connection is closed and the
+	// query needing it fails if OnConnect returns an error.
+	OnConnect func(ctx context.Context, conn *sql.Conn) error
 }
 
+// defaultCooldown is the cooldown applied after a connection error when Options.Cooldown is unset.
+const defaultCooldown = 5 * time.Second
+
 // DB is the central engine of the JORM ORM.
 // It manages the underlying connection pool, SQL dialect, and logging capabilities.
 // Use core.Open to initialize a new instance.
@@ -38,22 +62,87 @@ type DB struct {
 	logger  logger.Logger
 
 	// Health tracking
-	mu           sync.RWMutex
-	lastErr      error
-	lastErrTime  time.Time
 	cooldownTime time.Duration
+	isRetryable  func(error) bool
+
+	strictScan       bool            // Report unconvertible column values instead of leaving zero values
+	preloadBatchSize int             // Maximum number of keys per preload IN list, see PreloadBatchSize
+	location         *time.Location  // Time zone for reading and writing times, nil means time.Local
+	orderDDLColumns  bool            // Create tables with PK columns first and auto time columns last
+	softDeleteColumn string          // Column marking soft-deleted rows, see Options.SoftDeleteColumn
+	ctx              context.Context // Base context of new queries, see WithContext
+
+	*dbState // Shared with the copies returned by WithContext
+}
+
+// dbState holds the mutable state of a DB that its WithContext copies share.
+type dbState struct {
+	// Health tracking
+	mu          sync.RWMutex
+	lastErr     error
+	lastErrTime time.Time
 
 	// Components and Middleware
 	components  map[string]Component
 	middlewares []QueryMiddleware
+	mwMu        sync.RWMutex // Guards components and middlewares
+
+	idempotencyReady atomic.Bool // Set once the InsertIdempotent key table exists
 }
 
 // Use registers one or more middleware components to the DB.
 // It initializes each middleware and adds it to the execution chain.
 func (db *DB) Use(middleware ...QueryMiddleware) {
-	db.middlewares = append(db.middlewares, middleware...)
+	db.mwMu.Lock()
+	// Copy-on-write so in-flight queries keep a consistent snapshot
+	mws := make([]QueryMiddleware, 0, len(db.middlewares)+len(middleware))
+	mws = append(mws, db.middlewares...)
+	db.middlewares = append(mws, middleware...)
+	db.mwMu.Unlock()
+	db.initMiddlewares(middleware)
+}
+
+// UseFirst registers one or more middleware components at the front of the execution chain,
+// so they wrap all previously registered middleware.
+func (db *DB) UseFirst(middleware ...QueryMiddleware) {
+	db.mwMu.Lock()
+	mws := make([]QueryMiddleware, 0, len(db.middlewares)+len(middleware))
+	mws = append(mws, middleware...)
+	db.middlewares = append(mws, db.middlewares...)
+	db.mwMu.Unlock()
+	db.initMiddlewares(middleware)
+}
+
+// RemoveMiddleware removes the first middleware whose Name() matches name from the execution chain.
+// It returns true if a middleware was removed. The removed middleware is not shut down.
+func (db *DB) RemoveMiddleware(name string) bool {
+	db.mwMu.Lock()
+	defer db.mwMu.Unlock()
+
+	for i, m := range db.middlewares {
+		if m.Name() == name {
+			mws := make([]QueryMiddleware, 0, len(db.middlewares)-1)
+			mws = append(mws, db.middlewares[:i]...)
+			db.middlewares = append(mws, db.middlewares[i+1:]...)
+			delete(db.components, name)
+			return true
+		}
+	}
+	return false
+}
+
+// Middlewares returns a snapshot of the registered middleware in execution order.
+func (db *DB) Middlewares() []QueryMiddleware {
+	db.mwMu.RLock()
+	defer db.mwMu.RUnlock()
+	return append([]QueryMiddleware(nil), db.middlewares...)
+}
+
+func (db *DB) initMiddlewares(middleware []QueryMiddleware) {
 	for _, m := range middleware {
+		db.mwMu.Lock()
 		db.components[m.Name()] = m
+		db.mwMu.Unlock()
 		if err := m.Init(db); err != nil {
 			// Since we can't return error here easily without breaking API, just log it.
 			// In a real app, you might want to panic or handle this better.
@@ -78,12 +167,23 @@ func Open(driver, dsn string, opts *Options) (*DB, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database connection: %w", err)
 	}
+	if opts != nil && opts.OnConnect != nil {
+		if sqlDB, err = withOnConnect(sqlDB, dsn, opts.OnConnect); err != nil {
+			return nil, fmt.Errorf("failed to open database connection: %w", err)
+		}
+	}
 
 	p := pool.NewStdPool(sqlDB)
 
 	maxRetries := 0
 	retryDelay := time.Second
+	cooldown := defaultCooldown
+	isRetryable := isConnectionError
+	var location *time.Location
+	var softDeleteColumn string
 	if opts != nil {
+		location = opts.Location
+		softDeleteColumn = opts.SoftDeleteColumn
 		if opts.MaxOpenConns > 0 {
 			p.SetMaxOpenConns(opts.MaxOpenConns)
 		}
@@ -97,6 +197,12 @@ func Open(driver, dsn string, opts *Options) (*DB, error) {
 		if opts.RetryDelay > 0 {
 			retryDelay = opts.RetryDelay
 		}
+		if opts.Cooldown != 0 {
+			cooldown = opts.Cooldown
+		}
+		if opts.RetryableErrorClassifier != nil {
+			isRetryable = opts.RetryableErrorClassifier
+		}
 	}
 
 	var pingErr error
@@ -123,14 +229,34 @@ func Open(driver, dsn string, opts *Options) (*DB, error) {
 	}
 
 	return &DB{
-		pool:         p,
-		dialect:      d,
-		logger:       logger.NewStdLogger(),
-		cooldownTime: 5 * time.Second, // Default cooldown if DB is down
-		components:   make(map[string]Component),
+		pool:             p,
+		dialect:          d,
+		logger:           logger.NewStdLogger(),
+		cooldownTime:     cooldown,
+		isRetryable:      isRetryable,
+		location:         location,
+		softDeleteColumn: softDeleteColumn,
+		dbState:          &dbState{components: make(map[string]Component)},
 	}, nil
 }
 
+// withOnConnect replaces sqlDB, which has not connected yet, with one running onConnect on
+// each new connection.
+func withOnConnect(sqlDB *sql.DB, dsn string, onConnect func(context.Context, *sql.Conn) error) (*sql.DB, error) {
+	drv := sqlDB.Driver()
+	sqlDB.Close()
+	var base driver.Connector
+	if dc, ok := drv.(driver.DriverContext); ok {
+		var err error
+		if base, err = dc.OpenConnector(dsn); err != nil {
+			return nil, err
+		}
+	} else {
+		base = pool.DSNConnector(drv, dsn)
+	}
+	return sql.OpenDB(pool.NewConnector(base, onConnect)), nil
+}
+
 // Close closes the database connection and releases any resources.
 // It should be called when the DB instance is no longer needed.
 func (db *DB) Close() error {
@@ -140,12 +266,108 @@ func (db *DB) Close() error {
 	return nil
 }
 
+// PingContext checks that the database is reachable, e.g. for readiness probes. Unlike
+// queries it always contacts the database, even during a connection cooldown. A successful
+// ping ends the cooldown; a failed one starts it and returns an error wrapping
+// ErrConnectionFailed.
+func (db *DB) PingContext(ctx context.Context) error {
+	if err := db.pool.PingContext(ctx); err != nil {
+		err = fmt.Errorf("%w: %w", ErrConnectionFailed, err)
+		db.reportError(err)
+		return err
+	}
+	db.reportError(nil)
+	return nil
+}
+
+// Capabilities reports the optional features supported by the database.
+func (db *DB) Capabilities() dialect.DialectCapabilities {
+	return capabilities(db.dialect)
+}
+
+// SQLDB returns the underlying *sql.DB as an escape hatch for operations jorm cannot express.
+// Statements run through it bypass jorm entirely: middlewares (cache, tracing, slow log),
+// hooks, SQL logging and health tracking are all skipped.
+func (db *DB) SQLDB() *sql.DB {
+	return db.pool.SQLDB()
+}
+
+// SetMaxOpenConns sets the maximum number of open connections to the database at runtime,
+// e.g. to follow the load. n <= 0 means no limit. See sql.DB.SetMaxOpenConns.
+func (db *DB) SetMaxOpenConns(n int) {
+	db.pool.SetMaxOpenConns(n)
+}
+
+// SetMaxIdleConns sets the maximum number of connections kept in the idle pool at runtime.
+// n <= 0 keeps no idle connections. See sql.DB.SetMaxIdleConns.
+func (db *DB) SetMaxIdleConns(n int) {
+	db.pool.SetMaxIdleConns(n)
+}
+
+// SetConnMaxIdleTime sets how long a connection may stay idle before it is closed.
+// d <= 0 keeps idle connections open. See sql.DB.SetConnMaxIdleTime.
+func (db *DB) SetConnMaxIdleTime(d time.Duration) {
+	db.pool.SetConnMaxIdleTime(d)
+}
+
+// Stats returns the connection pool statistics.
+func (db *DB) Stats() sql.DBStats {
+	return db.pool.Stats()
+}
+
 // SetLogger sets a custom logger for the DB instance.
 // The logger will be used to record SQL queries, execution times, and errors.
 func (db *DB) SetLogger(l logger.Logger) {
 	db.logger = l
 }
 
+// StrictScan enables or disables strict scan mode. By default a column value whose type
+// cannot be converted to its struct field is skipped and the field keeps its zero value.
+// In strict mode Find and First fail with a *ScanError naming the column and both types.
+// It should be configured before the DB is shared between goroutines.
+func (db *DB) StrictScan(enabled bool) {
+	db.strictScan = enabled
+}
+
+// OrderDDLColumns sets whether AutoMigrate creates tables with the primary key columns
+// first and the auto_time and auto_update columns last, keeping the declaration order
+// otherwise. By default columns follow the model's fields, with those of embedded
+// structs where the struct is embedded. Existing tables are not reordered.
+// It should be configured before the DB is shared between goroutines.
+func (db *DB) OrderDDLColumns(enabled bool) {
+	db.orderDDLColumns = enabled
+}
+
+// SetLocation sets the time zone used to interpret date/time strings without a zone read
+// from the database and to write time values; nil restores the default, time.Local.
+// It should be configured before the DB is shared between goroutines.
+func (db *DB) SetLocation(loc *time.Location) {
+	db.location = loc
+}
+
+// defaultPreloadBatchSize is the preload IN list size used when PreloadBatchSize is unset.
+const defaultPreloadBatchSize = 1000
+
+// PreloadBatchSize sets the maximum number of keys placed in one IN list when preloading
+// relations. Larger key sets are loaded in several queries whose results are merged, which
+// keeps statements under the database's parameter limit. A value <= 0 restores the
+// default of 1000. It should be configured before the DB is shared between goroutines.
+func (db *DB) PreloadBatchSize(n int) {
+	db.preloadBatchSize = n
+}
+
+// WithContext returns a copy of the DB whose queries, including those run in its
+// transactions, start from ctx instead of context.Background(), e.g. to carry a tenant or
+// a deadline through every query.
+// Query.WithContext still replaces it for a single query. The copy shares the connection
+// pool, middlewares and health state with db, which is left unchanged; settings such as
+// SetLogger applied to either afterwards do not affect the other.
+func (db *DB) WithContext(ctx context.Context) *DB {
+	c := *db
+	c.ctx = ctx
+	return &c
+}
+
 // checkHealth verifies if the database connection is currently in a cooldown period
 // due to recent connection failures.
 func (db *DB) checkHealth() error {
@@ -169,14 +391,7 @@ func (db *DB) reportError(err error) {
 	}
 
 	// Only trigger cooldown for connection-related errors
-	// This is a simplified check; in a real-world scenario, you might want to check for specific network errors
-	errMsg := err.Error()
-	if strings.Contains(errMsg, "connection refused") ||
-		strings.Contains(errMsg, "i/o timeout") ||
-		strings.Contains(errMsg, "reset by peer") ||
-		strings.Contains(errMsg, "broken pipe") ||
-		errors.Is(err, ErrConnectionFailed) {
-
+	if db.cooldownTime > 0 && db.isRetryable != nil && db.isRetryable(err) {
 		db.mu.Lock()
 		db.lastErr = err
 		db.lastErrTime = time.Now()
@@ -184,11 +399,21 @@ func (db *DB) reportError(err error) {
 	}
 }
 
+// isConnectionError is the default RetryableErrorClassifier. It reports whether err
+// indicates the database connection is unavailable.
+func isConnectionError(err error) bool {
+	return ClassifyError(err) == ErrConnectionFailed
+}
+
 // newQuery creates a new Query instance associated with this DB.
 // It initializes the query builder and checks for database health.
 func (db *DB) newQuery(executor Executor) *Query {
 	builder := NewBuilder(db.dialect)
 	q := NewQuery(db, executor, builder)
+	if db.ctx != nil {
+		// Through WithContext so the query tags of the context are logged
+		q.WithContext(db.ctx)
+	}
 	if err := db.checkHealth(); err != nil {
 		q.err = err
 	}
@@ -208,12 +433,24 @@ func (db *DB) Table(name string) *Query {
 	return db.newQuery(db.pool).Table(name)
 }
 
+// FromSubQuery starts a new query selecting from sub as a derived table named alias.
+// See Query.FromSubQuery.
+func (db *DB) FromSubQuery(sub *Query, alias string) *Query {
+	return db.newQuery(db.pool).FromSubQuery(sub, alias)
+}
+
 // Raw starts a new query with a raw SQL statement and its arguments.
 // It bypasses the JORM query builder and allows for direct SQL execution.
 func (db *DB) Raw(sql string, args ...any) *Query {
 	return db.newQuery(db.pool).Raw(sql, args...)
 }
 
+// RawNamed starts a new query with a raw SQL statement using :name parameters.
+// See Query.RawNamed.
+func (db *DB) RawNamed(sql string, params map[string]any) *Query {
+	return db.newQuery(db.pool).RawNamed(sql, params)
+}
+
 // logSQL logs the SQL statement, its execution duration, and arguments.
 // It only logs if a logger has been configured for the DB.
 func (db *DB) logSQL(sql string, duration time.Duration, args ...any) {
@@ -274,6 +511,65 @@ func (db *DB) Transaction(fn func(tx *Tx) error) (err error) {
 	return err
 }
 
+// transactionRetryDelay is the initial backoff delay used by TransactionWithRetry.
+const transactionRetryDelay = 50 * time.Millisecond
+
+// TransactionWithRetry executes fn within a transaction like Transaction, re-running the
+// whole transaction when it fails with a deadlock or serialization failure (see ClassifyError).
+// It makes at most maxAttempts attempts, waiting with exponential backoff between them.
+// Non-retryable errors are returned immediately.
+func (db *DB) TransactionWithRetry(maxAttempts int, fn func(tx *Tx) error) error {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	var err error
+	for i := 0; i < maxAttempts; i++ {
+		err = db.Transaction(fn)
+		if err == nil {
+			return nil
+		}
+
+		class := ClassifyError(err)
+		if class != ErrDeadlock && class != ErrSerializationFailure {
+			return err
+		}
+
+		if i < maxAttempts-1 {
+			// Exponential backoff: delay * 2^i
+			actualDelay := transactionRetryDelay * (1 << uint(i))
+			if actualDelay > 30*time.Second {
+				actualDelay = 30 * time.Second
+			}
+			time.Sleep(actualDelay)
+		}
+	}
+	return fmt.Errorf("transaction failed after %d attempts: %w", maxAttempts, err)
+}
+
+// ExecBatch runs statements in order within a transaction, e.g. the steps of a migration
+// script. The first failing statement rolls the transaction back, so either all of them
+// are applied or none; note that some databases, such as MySQL, commit DDL implicitly.
+// It returns ErrInvalidQuery if statements is empty or contains a blank statement.
+func (db *DB) ExecBatch(ctx context.Context, statements []string) error {
+	if len(statements) == 0 {
+		return fmt.Errorf("%w: ExecBatch requires at least one statement", ErrInvalidQuery)
+	}
+	for i, stmt := range statements {
+		if strings.TrimSpace(stmt) == "" {
+			return fmt.Errorf("%w: ExecBatch statement %d is empty", ErrInvalidQuery, i+1)
+		}
+	}
+	return db.Transaction(func(tx *Tx) error {
+		for i, stmt := range statements {
+			if _, err := db.newQuery(tx).WithContext(ctx).Raw(stmt).ExecResult(); err != nil {
+				return fmt.Errorf("ExecBatch statement %d failed: %w", i+1, err)
+			}
+		}
+		return nil
+	})
+}
+
 // HasTable checks if the specified table exists in the database.
 // It uses the dialect-specific implementation to perform the check.
 func (db *DB) HasTable(tableName string) (bool, error) {
@@ -288,6 +584,14 @@ func (db *DB) HasTable(tableName string) (bool, error) {
 
 // AutoMigrate creates or updates the table for the given model.
 func (db *DB) AutoMigrate(values ...any) error {
+	return db.AutoMigrateWithCallbacks(values, nil)
+}
+
+// AutoMigrateWithCallbacks migrates the tables of values like AutoMigrate and calls
+// afterEach, if not nil, once the table of each model has been created or altered, e.g.
+// to create a view or a partial index or to seed data. An error returned by afterEach
+// stops the migration of the remaining models.
+func (db *DB) AutoMigrateWithCallbacks(values []any, afterEach func(m *model.Model) error) error {
 	for _, value := range values {
 		m, err := model.GetModel(value)
 		if err != nil {
@@ -300,11 +604,16 @@ func (db *DB) AutoMigrate(values ...any) error {
 		}
 
 		if !exists {
-			createSQL, createArgs := db.dialect.CreateTableSQL(m)
+			createSQL, createArgs := db.dialect.CreateTableSQL(db.ddlModel(m))
 			_, err = db.Exec(createSQL, createArgs...)
 			if err != nil {
 				return fmt.Errorf("failed to create table %s: %w", m.TableName, err)
 			}
+			for _, field := range m.Fields {
+				if err := db.commentColumn(m.TableName, field); err != nil {
+					return err
+				}
+			}
 		} else {
 			if err := db.alterTableIfNeeded(m); err != nil {
 				return err
@@ -314,10 +623,107 @@ func (db *DB) AutoMigrate(values ...any) error {
 		if err := db.syncIndexes(m); err != nil {
 			return err
 		}
+
+		if err := db.migrateJoinTables(m); err != nil {
+			return err
+		}
+
+		if afterEach != nil {
+			if err := afterEach(m); err != nil {
+				return fmt.Errorf("after migrating table %s: %w", m.TableName, err)
+			}
+		}
+	}
+	return nil
+}
+
+// migrateJoinTables creates the join tables of the model's many-to-many relations
+// that do not exist yet, with the two key columns as a composite primary key.
+func (db *DB) migrateJoinTables(m *model.Model) error {
+	typ := m.OriginalType
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := model.ParseTag(field.Tag.Get("jorm"))
+		if tag.JoinTable == "" {
+			continue
+		}
+
+		rel, err := model.GetRelation(m, field.Name)
+		if err != nil {
+			return err
+		}
+		if rel.Type != model.RelationManyToMany {
+			continue
+		}
+
+		exists, err := db.HasTable(rel.JoinTable)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+
+		elemType := field.Type
+		for elemType.Kind() == reflect.Slice || elemType.Kind() == reflect.Ptr {
+			elemType = elemType.Elem()
+		}
+		related, err := model.GetModel(reflect.New(elemType).Interface())
+		if err != nil {
+			return fmt.Errorf("failed to get model for join table %s: %w", rel.JoinTable, err)
+		}
+
+		fkType := db.joinKeyType(m)
+		refType := db.joinKeyType(related)
+		createSQL := fmt.Sprintf("CREATE TABLE %s (%s %s NOT NULL, %s %s NOT NULL, PRIMARY KEY (%s, %s))",
+			db.dialect.Quote(rel.JoinTable),
+			db.dialect.Quote(rel.JoinFK), fkType,
+			db.dialect.Quote(rel.JoinRef), refType,
+			db.dialect.Quote(rel.JoinFK), db.dialect.Quote(rel.JoinRef),
+		)
+		if _, err := db.Exec(createSQL); err != nil {
+			return fmt.Errorf("failed to create join table %s: %w", rel.JoinTable, err)
+		}
 	}
 	return nil
 }
 
+// joinKeyType returns the column type used for a join table key referencing m's primary key.
+func (db *DB) joinKeyType(m *model.Model) string {
+	if m.PKField != nil {
+		return db.dialect.DataTypeOf(m.PKField.Type)
+	}
+	return db.dialect.DataTypeOf(reflect.TypeOf(int64(0)))
+}
+
+// ddlModel returns the model whose fields CREATE TABLE declares: m itself, or with
+// OrderDDLColumns a copy with its primary key fields moved first and its auto_time and
+// auto_update fields moved last.
+func (db *DB) ddlModel(m *model.Model) *model.Model {
+	if !db.orderDDLColumns {
+		return m
+	}
+	rank := func(f *model.Field) int {
+		switch {
+		case f.IsPK:
+			return 0
+		case f.AutoTime || f.AutoUpdate:
+			return 2
+		}
+		return 1
+	}
+	ordered := *m
+	ordered.Fields = make([]*model.Field, len(m.Fields))
+	copy(ordered.Fields, m.Fields)
+	sort.SliceStable(ordered.Fields, func(i, j int) bool {
+		return rank(ordered.Fields[i]) < rank(ordered.Fields[j])
+	})
+	return &ordered
+}
+
 // alterTableIfNeeded compares the model definition with the existing table schema
 // and adds any missing columns.
 func (db *DB) alterTableIfNeeded(m *model.Model) error {
@@ -347,6 +753,9 @@ func (db *DB) alterTableIfNeeded(m *model.Model) error {
 				if err != nil {
 					return fmt.Errorf("failed to add column %s to table %s: %w", field.Column, m.TableName, err)
 				}
+				if err := db.commentColumn(m.TableName, field); err != nil {
+					return err
+				}
 			}
 		}
 	}
@@ -354,6 +763,19 @@ func (db *DB) alterTableIfNeeded(m *model.Model) error {
 	return nil
 }
 
+// commentColumn sets the comment of a new column on databases declaring comments in a
+// separate statement, see dialect.ColumnCommentSQLer.
+func (db *DB) commentColumn(tableName string, field *model.Field) error {
+	sqlStr := columnCommentSQL(db.dialect, tableName, field)
+	if sqlStr == "" {
+		return nil
+	}
+	if _, err := db.Exec(sqlStr); err != nil {
+		return fmt.Errorf("failed to comment column %s of table %s: %w", field.Column, tableName, err)
+	}
+	return nil
+}
+
 func (db *DB) syncIndexes(m *model.Model) error {
 	sqlStr, args := db.dialect.GetIndexesSQL(m.TableName)
 	rows, err := db.pool.QueryContext(context.Background(), sqlStr, args...)
@@ -392,33 +814,71 @@ func (db *DB) syncIndexes(m *model.Model) error {
 		return false
 	}
 
+	createIndex := func(indexName string, columns []string, unique bool, where string) error {
+		// Check by index name (case-insensitive) first
+		for name := range existingIndexes {
+			if strings.EqualFold(name, indexName) {
+				return nil
+			}
+		}
+		if hasIndex(columns, unique) {
+			return nil
+		}
+
+		createIdxSQL, createIdxArgs := createIndexSQL(db.dialect, m.TableName, indexName, columns, unique, where)
+		if createIdxSQL == "" {
+			return nil
+		}
+		if _, ok := db.dialect.(dialect.PartialIndexSQLer); where != "" && !ok && db.logger != nil {
+			db.logger.Warn("Partial indexes are not supported, index %s on table %s is created without WHERE %s", indexName, m.TableName, where)
+		}
+		if _, err := db.Exec(createIdxSQL, createIdxArgs...); err != nil {
+			// Ignore duplicate index errors to keep AutoMigrate idempotent across databases
+			msg := err.Error()
+			if strings.Contains(msg, "Duplicate key name") || strings.Contains(msg, "already exists") {
+				return nil
+			}
+			kind := "index"
+			if unique {
+				kind = "unique index"
+			}
+			return fmt.Errorf("failed to create %s %s on table %s: %w", kind, indexName, m.TableName, err)
+		}
+		return nil
+	}
+
+	// Fields tagged with the same index name form one composite index, in field order
+	var indexNames []string
+	indexColumns := make(map[string][]string)
+	indexWhere := make(map[string]string)
 	for _, field := range m.Fields {
 		if field.IsUnique {
 			indexName := fmt.Sprintf("idx_%s_%s", m.TableName, field.Column)
-
-			// Check by index name (case-insensitive) first
-			existsByName := false
-			for name := range existingIndexes {
-				if strings.EqualFold(name, indexName) {
-					existsByName = true
-					break
-				}
+			where := ""
+			if !field.Indexed {
+				where = field.IndexWhere
 			}
-
-			if !existsByName && !hasIndex([]string{field.Column}, true) {
-				createIdxSQL, createIdxArgs := db.dialect.CreateIndexSQL(m.TableName, indexName, []string{field.Column}, true)
-				if createIdxSQL != "" {
-					_, err = db.Exec(createIdxSQL, createIdxArgs...)
-					if err != nil {
-						// Ignore duplicate index errors to keep AutoMigrate idempotent across databases
-						msg := err.Error()
-						if strings.Contains(msg, "Duplicate key name") || strings.Contains(msg, "already exists") {
-							continue
-						}
-						return fmt.Errorf("failed to create unique index %s on table %s: %w", indexName, m.TableName, err)
-					}
-				}
+			if err := createIndex(indexName, []string{field.Column}, true, where); err != nil {
+				return err
+			}
+		}
+		if field.Indexed {
+			indexName := field.IndexName
+			if indexName == "" {
+				indexName = fmt.Sprintf("idx_%s_%s", m.TableName, field.Column)
+			}
+			if _, ok := indexColumns[indexName]; !ok {
+				indexNames = append(indexNames, indexName)
 			}
+			indexColumns[indexName] = append(indexColumns[indexName], field.Column)
+			if field.IndexWhere != "" {
+				indexWhere[indexName] = field.IndexWhere
+			}
+		}
+	}
+	for _, indexName := range indexNames {
+		if err := createIndex(indexName, indexColumns[indexName], false, indexWhere[indexName]); err != nil {
+			return err
 		}
 	}
 
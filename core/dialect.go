@@ -0,0 +1,171 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shrek82/jorm/dialect"
+	"github.com/shrek82/jorm/model"
+)
+
+// The functions below call the optional dialect methods, see dialect.Dialect. Dialects
+// registered by other packages may implement only dialect.Dialect, so each method is
+// looked up by type assertion and falls back to standard SQL, or to false or an empty
+// string for a feature the database is not known to support.
+
+func createIndexSQL(d dialect.Dialect, tableName, indexName string, columns []string, unique bool, where string) (string, []any) {
+	if p, ok := d.(dialect.PartialIndexSQLer); ok {
+		return p.CreatePartialIndexSQL(tableName, indexName, columns, unique, where)
+	}
+	return d.CreateIndexSQL(tableName, indexName, columns, unique)
+}
+
+func groupConcatSQL(d dialect.Dialect, column, separator string) (string, bool) {
+	if g, ok := d.(dialect.GroupConcatSQLer); ok {
+		return g.GroupConcatSQL(column, separator), true
+	}
+	return "", false
+}
+
+// tupleInSQL falls back to "(a = ? AND b = ?) OR (a = ? AND b = ?)", which every
+// database accepts.
+func tupleInSQL(d dialect.Dialect, columns []string, rowCount int) string {
+	if t, ok := d.(dialect.TupleInSQLer); ok {
+		return t.TupleInSQL(columns, rowCount)
+	}
+	conds := make([]string, len(columns))
+	for i, col := range columns {
+		conds[i] = col + " = ?"
+	}
+	tuple := "(" + strings.Join(conds, " AND ") + ")"
+	return strings.TrimSuffix(strings.Repeat(tuple+" OR ", rowCount), " OR ")
+}
+
+func jsonExtractSQL(d dialect.Dialect, column, path string) (string, bool) {
+	if j, ok := d.(dialect.JSONExtractSQLer); ok {
+		return j.JSONExtractSQL(column, path), true
+	}
+	return "", false
+}
+
+func dateSQL(d dialect.Dialect, column string) (string, bool) {
+	if ds, ok := d.(dialect.DateSQLer); ok {
+		return ds.DateSQL(column), true
+	}
+	return "", false
+}
+
+// likeSQL falls back to declaring the backslash escape, lowering both sides when
+// insensitive is set.
+func likeSQL(d dialect.Dialect, column string, insensitive bool) string {
+	if l, ok := d.(dialect.LikeSQLer); ok {
+		return l.LikeSQL(column, insensitive)
+	}
+	if insensitive {
+		return "LOWER(" + column + ") LIKE LOWER(?) ESCAPE '\\'"
+	}
+	return column + " LIKE ? ESCAPE '\\'"
+}
+
+// orderByNullsSQL falls back to sorting on whether column is NULL before column itself.
+func orderByNullsSQL(d dialect.Dialect, column, direction string, nullsFirst bool) string {
+	if o, ok := d.(dialect.OrderByNullsSQLer); ok {
+		return o.OrderByNullsSQL(column, direction, nullsFirst)
+	}
+	if nullsFirst {
+		return "CASE WHEN " + column + " IS NULL THEN 0 ELSE 1 END, " + column + " " + direction
+	}
+	return "CASE WHEN " + column + " IS NULL THEN 1 ELSE 0 END, " + column + " " + direction
+}
+
+func callProcSQL(d dialect.Dialect, name string, argCount int) string {
+	if c, ok := d.(dialect.CallProcSQLer); ok {
+		return c.CallProcSQL(name, argCount)
+	}
+	return ""
+}
+
+// limitOffsetSQL falls back to "LIMIT ? OFFSET ?", leaving out the unset (negative) parts.
+func limitOffsetSQL(d dialect.Dialect, limit, offset int, hasOrderBy bool) (string, []any) {
+	if l, ok := d.(dialect.LimitOffsetSQLer); ok {
+		return l.LimitOffsetSQL(limit, offset, hasOrderBy)
+	}
+	var clauses []string
+	var args []any
+	if limit >= 0 {
+		clauses = append(clauses, "LIMIT ?")
+		args = append(args, limit)
+	}
+	if offset >= 0 {
+		clauses = append(clauses, "OFFSET ?")
+		args = append(args, offset)
+	}
+	return strings.Join(clauses, " "), args
+}
+
+func savepointSQL(d dialect.Dialect, name string) string {
+	if s, ok := d.(dialect.SavepointSQLer); ok {
+		return s.SavepointSQL(name)
+	}
+	return "SAVEPOINT " + name
+}
+
+func rollbackToSQL(d dialect.Dialect, name string) string {
+	if s, ok := d.(dialect.SavepointSQLer); ok {
+		return s.RollbackToSQL(name)
+	}
+	return "ROLLBACK TO SAVEPOINT " + name
+}
+
+func releaseSavepointSQL(d dialect.Dialect, name string) string {
+	if s, ok := d.(dialect.SavepointSQLer); ok {
+		return s.ReleaseSavepointSQL(name)
+	}
+	return "RELEASE SAVEPOINT " + name
+}
+
+func upsertSQL(d dialect.Dialect, conflictColumns, updateColumns []string) string {
+	if u, ok := d.(dialect.UpsertSQLer); ok {
+		return u.UpsertSQL(conflictColumns, updateColumns)
+	}
+	return ""
+}
+
+func batchFirstInsertID(d dialect.Dialect, lastInsertID, rowCount int64) (int64, bool) {
+	if b, ok := d.(dialect.BatchInsertIDer); ok {
+		return b.BatchFirstInsertID(lastInsertID, rowCount)
+	}
+	return 0, false
+}
+
+func columnCommentSQL(d dialect.Dialect, tableName string, field *model.Field) string {
+	if c, ok := d.(dialect.ColumnCommentSQLer); ok {
+		return c.ColumnCommentSQL(tableName, field)
+	}
+	return ""
+}
+
+// defaultMaxPlaceholders is the parameter limit BatchUpsert chunks by when the dialect
+// reports none: SQLite's historical limit, the lowest of the common databases.
+const defaultMaxPlaceholders = 999
+
+// maxPlaceholders returns 0 if the dialect does not report a limit.
+func maxPlaceholders(d dialect.Dialect) int {
+	if p, ok := d.(dialect.PlaceholderLimiter); ok {
+		return p.MaxPlaceholders()
+	}
+	return 0
+}
+
+func capabilities(d dialect.Dialect) dialect.DialectCapabilities {
+	if c, ok := d.(dialect.CapabilitiesReporter); ok {
+		return c.Capabilities()
+	}
+	return dialect.DialectCapabilities{}
+}
+
+// unsupported returns the error of a query method needing a dialect method the database
+// has no form of.
+func unsupported(method string) error {
+	return fmt.Errorf("%w: %s is not supported by this database", ErrInvalidQuery, method)
+}
@@ -2,6 +2,8 @@ package core
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 )
 
 var (
@@ -23,4 +25,88 @@ var (
 	ErrConnectionFailed = errors.New("connection failed")
 	// ErrInvalidSQL is returned when a raw SQL statement is empty or malformed.
 	ErrInvalidSQL = errors.New("invalid sql")
+	// ErrInvalidEnum is returned when a value written to an enum field is not one of its allowed values.
+	ErrInvalidEnum = errors.New("invalid enum value")
+	// ErrDeadlock is returned when the database aborts a statement to resolve a deadlock.
+	ErrDeadlock = errors.New("deadlock")
+	// ErrSerializationFailure is returned when a transaction cannot be serialized with concurrent transactions.
+	ErrSerializationFailure = errors.New("serialization failure")
+	// ErrNoTable is returned when a statement is built without a table, e.g. no Model or Table call.
+	ErrNoTable = errors.New("no table set")
+	// ErrQueryConsumed is returned when a terminal method such as Find or Count is called on a
+	// query that already ran one. Clone the query before running it to execute it more than once.
+	ErrQueryConsumed = errors.New("query already executed")
+	// ErrInvalidWhereIn is returned when WhereIn is given nil or a value that is not a slice or array.
+	ErrInvalidWhereIn = errors.New("invalid WhereIn values")
+	// ErrValueOutOfRange is returned when a field value cannot be bound without losing
+	// precision, e.g. a uint64 above the int64 range.
+	ErrValueOutOfRange = errors.New("value out of range")
+	// ErrColumnNotAllowed is returned when Select, OrderBy or GroupBy is given a column that
+	// is not in the list set by Query.AllowColumns.
+	ErrColumnNotAllowed = errors.New("column not allowed")
+	// ErrTooManyParameters is returned when a built statement binds more arguments than the
+	// database accepts (see dialect.Dialect.MaxPlaceholders), e.g. for a huge WhereIn list.
+	ErrTooManyParameters = errors.New("too many parameters")
 )
+
+// ScanError is returned in strict scan mode (see DB.StrictScan) when a column value
+// cannot be stored in its destination field.
+type ScanError struct {
+	Column  string // Result column name
+	Field   string // Struct field name
+	SrcType string // Go type of the value returned by the driver
+	DstType string // Go type of the destination field
+	Err     error  // Underlying driver conversion error, if any
+}
+
+func (e *ScanError) Error() string {
+	msg := fmt.Sprintf("cannot scan column %q (%s) into field %s (%s)", e.Column, e.SrcType, e.Field, e.DstType)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *ScanError) Unwrap() error {
+	return e.Err
+}
+
+// errorPatterns maps lower-cased driver error message fragments to sentinel errors.
+// Messages cover MySQL, PostgreSQL, SQLite and SQL Server drivers.
+var errorPatterns = []struct {
+	pattern string
+	class   error
+}{
+	{"deadlock", ErrDeadlock},
+	{"could not serialize access", ErrSerializationFailure},
+	{"serialization failure", ErrSerializationFailure},
+	{"duplicate entry", ErrDuplicateKey},
+	{"duplicate key", ErrDuplicateKey},
+	{"unique constraint failed", ErrDuplicateKey},
+	{"foreign key constraint", ErrForeignKey},
+	{"connection refused", ErrConnectionFailed},
+	{"broken pipe", ErrConnectionFailed},
+	{"reset by peer", ErrConnectionFailed},
+	{"i/o timeout", ErrConnectionFailed},
+}
+
+// ClassifyError maps a driver error to one of the sentinel errors defined in this package
+// (ErrDeadlock, ErrSerializationFailure, ErrDuplicateKey, ErrForeignKey, ErrConnectionFailed).
+// It returns nil if err is nil or cannot be classified.
+func ClassifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+	for _, sentinel := range []error{ErrDeadlock, ErrSerializationFailure, ErrDuplicateKey, ErrForeignKey, ErrConnectionFailed} {
+		if errors.Is(err, sentinel) {
+			return sentinel
+		}
+	}
+	msg := strings.ToLower(err.Error())
+	for _, p := range errorPatterns {
+		if strings.Contains(msg, p.pattern) {
+			return p.class
+		}
+	}
+	return nil
+}
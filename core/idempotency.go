@@ -0,0 +1,100 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shrek82/jorm/model"
+)
+
+// idempotencyKey is a row of the table in which InsertIdempotent records its keys.
+type idempotencyKey struct {
+	Key    string `jorm:"column:idem_key;pk;size:191"`
+	Target string `jorm:"column:table_name;size:128;notnull"`
+	RowID  int64  `jorm:"column:row_id;notnull"`
+}
+
+func (k *idempotencyKey) TableName() string {
+	return "jorm_idempotency_keys"
+}
+
+// InsertIdempotent inserts value like Insert unless key was already used by an earlier
+// call, in which case nothing is inserted and the ID of the row created by that call is
+// returned. It makes inserts safe to retry, e.g. when processing at-least-once messages
+// keyed by message ID. Keys are recorded in the jorm_idempotency_keys table, created on
+// first use, in the same transaction as the row; outside a transaction one is started.
+// If a concurrent call with the same key wins the race, the row it created is returned.
+func (q *Query) InsertIdempotent(value any, key string) (int64, error) {
+	defer q.release()
+	if q.err != nil {
+		return 0, q.err
+	}
+	if key == "" {
+		return 0, fmt.Errorf("%w: InsertIdempotent requires a key", ErrInvalidQuery)
+	}
+	m, err := model.GetModel(value)
+	if err != nil {
+		return 0, err
+	}
+	if err := q.db.migrateIdempotencyKeys(); err != nil {
+		return 0, err
+	}
+
+	insert := func(tx *Tx) (int64, error) {
+		if id, err := q.db.idempotentRowID(q.ctx, tx, key); !errors.Is(err, ErrRecordNotFound) {
+			if err == nil && m.PKField != nil {
+				setPKValue(value, m.PKField, id)
+			}
+			return id, err
+		}
+		id, err := tx.Model(value).WithContext(q.ctx).Insert(value)
+		if err != nil {
+			return 0, err
+		}
+		record := &idempotencyKey{Key: key, Target: m.TableName, RowID: id}
+		if _, err := tx.Model(record).WithContext(q.ctx).Insert(record); err != nil {
+			return 0, err
+		}
+		return id, nil
+	}
+
+	if tx, ok := q.executor.(*Tx); ok {
+		return insert(tx)
+	}
+	var id int64
+	err = q.db.Transaction(func(tx *Tx) error {
+		id, err = insert(tx)
+		return err
+	})
+	if ClassifyError(err) == ErrDuplicateKey {
+		// Another call recorded the key first; its row is committed now
+		if winner, lookupErr := q.db.idempotentRowID(q.ctx, q.db.pool, key); lookupErr == nil {
+			if m.PKField != nil {
+				setPKValue(value, m.PKField, winner)
+			}
+			return winner, nil
+		}
+	}
+	return id, err
+}
+
+// idempotentRowID returns the ID of the row recorded for key, or ErrRecordNotFound.
+func (db *DB) idempotentRowID(ctx context.Context, executor Executor, key string) (int64, error) {
+	var id int64
+	err := db.newQuery(executor).WithContext(ctx).Table((&idempotencyKey{}).TableName()).
+		Select("row_id").Where(db.dialect.Quote("idem_key")+" = ?", key).Value(&id)
+	return id, err
+}
+
+// migrateIdempotencyKeys creates the InsertIdempotent key table once per DB.
+func (db *DB) migrateIdempotencyKeys() error {
+	if db.idempotencyReady.Load() {
+		return nil
+	}
+	if err := db.AutoMigrate(&idempotencyKey{}); err != nil {
+		return fmt.Errorf("failed to create idempotency key table: %w", err)
+	}
+	db.idempotencyReady.Store(true)
+	return nil
+}
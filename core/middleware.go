@@ -29,3 +29,33 @@ type QueryMiddleware interface {
 	Component
 	Process(ctx context.Context, query *Query, next QueryFunc) (*Result, error)
 }
+
+// OperationType is the kind of statement a terminal method runs, see Query.Operation.
+type OperationType int
+
+const (
+	OpUnknown OperationType = iota // Not executed yet
+	OpSelect                       // First, Find, Count, Scan and the other reads, raw or built
+	OpInsert                       // Insert and BatchInsert
+	OpUpdate                       // Update and Restore
+	OpDelete                       // Delete and DeleteByIDs, soft deletes included
+	OpRaw                          // A raw statement run by Exec or CallProc, which may read or write
+)
+
+// String returns the name of the operation, e.g. "select".
+func (op OperationType) String() string {
+	switch op {
+	case OpSelect:
+		return "select"
+	case OpInsert:
+		return "insert"
+	case OpUpdate:
+		return "update"
+	case OpDelete:
+		return "delete"
+	case OpRaw:
+		return "raw"
+	default:
+		return "unknown"
+	}
+}
@@ -3,6 +3,7 @@ package core
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"reflect"
 	"sync"
 	"time"
@@ -17,6 +18,8 @@ type preloadConfig struct {
 	path []string
 	// builder is an optional function to customize the query (e.g., adding WHERE clauses).
 	builder func(*Query)
+	// countInto names the parent field that receives the related row count instead of the rows.
+	countInto string
 }
 
 // preloadExecutor handles the execution of loading related data.
@@ -111,6 +114,10 @@ func (e *preloadExecutor) execute(mainModel *model.Model, dest any, config *prel
 		relation.Model = relModel
 	}
 
+	if config.countInto != "" {
+		return e.executeCount(mainModel, dest, relation, config)
+	}
+
 	// Switch on relation type to call specific execution logic
 	switch relation.Type {
 	case model.RelationHasMany, model.RelationHasOne:
@@ -143,13 +150,14 @@ func (e *preloadExecutor) execute(mainModel *model.Model, dest any, config *prel
 
 // executeHasRelation handles HasOne and HasMany relations.
 // It collects primary keys from the parent objects, queries the related objects,
-// and then maps them back to the parents.
+// and assigns each related row to its parents as it is scanned, so the related set
+// never has to be held in memory apart from the parents' fields.
 //
 // Workflow:
 // 1. Normalize dest to a slice (handling single object vs slice).
-// 2. Collect primary keys (IDs) from parent objects.
+// 2. Index the parents' relation fields by primary key.
 // 3. Query related table where Foreign Key IN (IDs).
-// 4. Map the results back to the parent objects' fields.
+// 4. Assign each scanned row to the parent objects' fields.
 func (e *preloadExecutor) executeHasRelation(mainModel *model.Model, dest any, relation *model.Relation, config *preloadConfig) error {
 	destValue := reflect.ValueOf(dest)
 	if destValue.Kind() != reflect.Ptr {
@@ -177,33 +185,21 @@ func (e *preloadExecutor) executeHasRelation(mainModel *model.Model, dest any, r
 		return nil
 	}
 
-	// Collect IDs from the parent objects
-	ids, err := e.collectPrimaryKeys(sliceValue, pkField)
-	if err != nil {
-		return err
-	}
-
-	if len(ids) == 0 {
+	target := newHasRelationTarget(sliceValue, relation, pkField)
+	if target == nil || len(target.ids) == 0 {
 		return nil
 	}
 
-	// Query related data
-	relatedData, err := e.queryHasRelationData(relation, ids, config)
-	if err != nil {
+	// Query related data, assigning it to the parents row by row
+	if err := e.queryHasRelationData(relation, target, config); err != nil {
 		return err
 	}
 
-	// Assign related data back to parent objects
-	if isSlice {
-		return e.mapHasRelation(sliceValue, relation, pkField, relatedData)
-	} else {
+	if !isSlice {
 		// Map back to the single object
-		err := e.mapHasRelation(sliceValue, relation, pkField, relatedData)
-		if err == nil {
-			destValue.Elem().Set(sliceValue.Index(0))
-		}
-		return err
+		destValue.Elem().Set(sliceValue.Index(0))
 	}
+	return nil
 }
 
 // executeBelongsTo handles BelongsTo relations.
@@ -403,7 +399,7 @@ func (e *preloadExecutor) collectPrimaryKeys(slice reflect.Value, pkField *model
 		if item.Kind() == reflect.Ptr {
 			item = item.Elem()
 		}
-		pkValue := item.Field(pkField.Index).Interface()
+		pkValue := pkField.Accessor(item).Interface()
 		ids = append(ids, pkValue)
 	}
 	return ids, nil
@@ -427,69 +423,230 @@ func (e *preloadExecutor) collectForeignKeys(slice reflect.Value, fkField *model
 	return ids, nil
 }
 
-// queryHasRelationData queries the database for HasOne/HasMany relations.
-// Returns a map of ParentID -> Slice of Related Objects.
-// It constructs a query selecting all related objects where the foreign key matches the parent IDs.
-func (e *preloadExecutor) queryHasRelationData(relation *model.Relation, ids []any, config *preloadConfig) (map[any][]any, error) {
+// queryHasRelationData queries the database for HasOne/HasMany relations and assigns
+// the related objects to target's parents.
+// The parent IDs are queried in batches (see DB.PreloadBatchSize).
+func (e *preloadExecutor) queryHasRelationData(relation *model.Relation, target *hasRelationTarget, config *preloadConfig) error {
+	for _, batch := range e.batches(target.ids) {
+		if err := e.queryHasRelationBatch(relation, batch, config, target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queryHasRelationBatch selects the related objects whose foreign key matches one of
+// ids and assigns each of them to target's parents as soon as it is scanned.
+func (e *preloadExecutor) queryHasRelationBatch(relation *model.Relation, ids []any, config *preloadConfig, target *hasRelationTarget) error {
 	builder := NewBuilder(e.db.dialect)
 	builder.Select("*")
 	builder.SetTable(relation.Model.TableName)
 
-	// Determine the column name for the foreign key in the related table
+	columnName := foreignKeyColumn(relation)
+	builder.WhereIn(columnName, ids)
+	if relation.OrderBy != "" {
+		builder.OrderBy(relation.OrderBy)
+	}
+
+	e.scope(builder, relation.Model, config)
+
+	sqlStr, args := builder.BuildSelect()
+	buildErr := builder.Err()
+	PutBuilder(builder)
+	if buildErr != nil {
+		return buildErr
+	}
+
+	fkField, ok := relation.Model.FieldMap[columnName]
+	if !ok {
+		return fmt.Errorf("%w: foreign key column %s not found in %s", ErrInvalidModel, columnName, relation.Model.TableName)
+	}
+
+	rows, err := e.executor.QueryContext(e.ctx, sqlStr, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	// Rows copied into value fields are scanned into one reused object
+	var item reflect.Value
+	for rows.Next() {
+		if target.byValue && item.IsValid() {
+			item.Elem().SetZero()
+		} else {
+			item = reflect.New(relation.Model.OriginalType)
+		}
+		if err := e.scanRow(rows, item.Interface()); err != nil {
+			return err
+		}
+		target.assign(item.Elem().Field(fkField.Index).Interface(), item)
+	}
+
+	return rows.Err()
+}
+
+// foreignKeyColumn returns the column of the foreign key in the related table of a
+// HasOne/HasMany relation.
+func foreignKeyColumn(relation *model.Relation) string {
 	columnName := relation.ForeignKey
 	if field, ok := relation.Model.FieldMap[columnName]; ok {
-		columnName = field.Column
+		return field.Column
+	}
+	for _, f := range relation.Model.Fields {
+		if f.Name == columnName {
+			return f.Column
+		}
+	}
+	return columnName
+}
+
+// executeCount handles PreloadCount for HasOne and HasMany relations.
+// It counts the related rows per parent with a single grouped query and stores each
+// count in the parent's config.countInto field; parents without related rows get 0.
+func (e *preloadExecutor) executeCount(mainModel *model.Model, dest any, relation *model.Relation, config *preloadConfig) error {
+	if relation.Type != model.RelationHasMany && relation.Type != model.RelationHasOne {
+		return fmt.Errorf("%w: PreloadCount requires a has_one or has_many relation, %s is not", ErrInvalidQuery, relation.Name)
+	}
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || mainModel.PKField == nil {
+		return nil
+	}
+
+	var sliceValue reflect.Value
+	isSlice := destValue.Elem().Kind() == reflect.Slice
+	if isSlice {
+		sliceValue = destValue.Elem()
 	} else {
-		for _, f := range relation.Model.Fields {
-			if f.Name == columnName {
-				columnName = f.Column
-				break
-			}
+		sliceValue = reflect.MakeSlice(reflect.SliceOf(destValue.Type().Elem()), 1, 1)
+		sliceValue.Index(0).Set(destValue.Elem())
+	}
+	if sliceValue.Len() == 0 {
+		return nil
+	}
+
+	elemType := sliceValue.Type().Elem()
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	countField, ok := elemType.FieldByName(config.countInto)
+	if !ok || !countField.IsExported() || !isIntKind(countField.Type.Kind()) {
+		return fmt.Errorf("%w: %s has no exported integer field %s", ErrInvalidModel, elemType.Name(), config.countInto)
+	}
+
+	ids, err := e.collectPrimaryKeys(sliceValue, mainModel.PKField)
+	if err != nil {
+		return err
+	}
+	counts := make(map[any]int64)
+	for _, batch := range e.batches(ids) {
+		if err := e.queryCountBatch(relation, batch, config, counts); err != nil {
+			return err
 		}
 	}
-	builder.WhereIn(columnName, ids)
 
-	// Apply custom query modifications if provided
-	if config.builder != nil {
-		tempQuery := &Query{
-			db:       e.db,
-			executor: e.executor,
-			builder:  builder,
-			ctx:      e.ctx,
-			model:    relation.Model,
+	for i := 0; i < sliceValue.Len(); i++ {
+		item := sliceValue.Index(i)
+		if item.Kind() == reflect.Ptr {
+			item = item.Elem()
 		}
-		config.builder(tempQuery)
+		pkValue := mainModel.PKField.Accessor(item).Interface()
+		item.FieldByIndex(countField.Index).SetInt(counts[countKey(pkValue)])
+	}
+	if !isSlice {
+		destValue.Elem().Set(sliceValue.Index(0))
+	}
+	return nil
+}
+
+// queryCountBatch runs SELECT fk, COUNT(*) ... GROUP BY fk for one batch of parent IDs
+// and adds the results to counts.
+func (e *preloadExecutor) queryCountBatch(relation *model.Relation, ids []any, config *preloadConfig, counts map[any]int64) error {
+	columnName := foreignKeyColumn(relation)
+	fkField, ok := relation.Model.FieldMap[columnName]
+	if !ok {
+		return fmt.Errorf("%w: foreign key %s not found on %s", ErrInvalidModel, relation.ForeignKey, relation.Model.TableName)
 	}
 
+	builder := NewBuilder(e.db.dialect)
+	quoted := e.db.dialect.Quote(columnName)
+	builder.Select(quoted, "COUNT(*)")
+	builder.SetTable(relation.Model.TableName)
+	builder.WhereIn(columnName, ids)
+	builder.GroupBy(quoted)
+
+	e.scope(builder, relation.Model, config)
+
 	sqlStr, args := builder.BuildSelect()
+	buildErr := builder.Err()
 	PutBuilder(builder)
+	if buildErr != nil {
+		return buildErr
+	}
 
 	rows, err := e.executor.QueryContext(e.ctx, sqlStr, args...)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer rows.Close()
 
-	result := make(map[any][]any)
-
 	for rows.Next() {
-		item := reflect.New(relation.Model.OriginalType).Interface()
-		if err := e.scanRow(rows, item); err != nil {
-			return nil, err
+		fk := reflect.New(fkField.Type)
+		var n int64
+		if err := rows.Scan(fk.Interface(), &n); err != nil {
+			return err
 		}
+		if key := countKey(fk.Elem().Interface()); key != nil {
+			counts[key] += n
+		}
+	}
+	return rows.Err()
+}
 
-		// Group by Foreign Key (which matches Parent's Primary Key)
-		fkValue := getFieldValue(item, columnName)
-		result[fkValue] = append(result[fkValue], item)
+// countKey normalizes an integer key to int64, so the parent's primary key matches the
+// foreign key of its rows when their integer types differ (e.g. int64 and int). Pointers
+// are dereferenced; a nil pointer gives nil.
+func countKey(v any) any {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if !rv.IsValid() {
+		return nil
 	}
+	switch {
+	case isIntKind(rv.Kind()):
+		return rv.Int()
+	case rv.CanUint():
+		return int64(rv.Uint())
+	}
+	return rv.Interface()
+}
 
-	return result, rows.Err()
+func isIntKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
 }
 
 // queryBelongsToData queries the database for BelongsTo relations.
 // Returns a map of RelatedID -> Related Object.
-// It constructs a query selecting all related objects where the Primary Key matches the Foreign Keys collected from parents.
+// The foreign keys are queried in batches (see DB.PreloadBatchSize) and the results merged.
 func (e *preloadExecutor) queryBelongsToData(relation *model.Relation, ids []any, config *preloadConfig) (map[any]any, error) {
+	result := make(map[any]any)
+	for _, batch := range e.batches(ids) {
+		part, err := e.queryBelongsToBatch(relation, batch, config)
+		if err != nil {
+			return nil, err
+		}
+		for pk, item := range part {
+			result[pk] = item
+		}
+	}
+	return result, nil
+}
+
+// queryBelongsToBatch selects the related objects whose primary key matches one of the
+// foreign keys in ids.
+func (e *preloadExecutor) queryBelongsToBatch(relation *model.Relation, ids []any, config *preloadConfig) (map[any]any, error) {
 	builder := NewBuilder(e.db.dialect)
 	builder.Select("*")
 	builder.SetTable(relation.Model.TableName)
@@ -507,19 +664,14 @@ func (e *preloadExecutor) queryBelongsToData(relation *model.Relation, ids []any
 	}
 	builder.WhereIn(columnName, ids)
 
-	if config.builder != nil {
-		tempQuery := &Query{
-			db:       e.db,
-			executor: e.executor,
-			builder:  builder,
-			ctx:      e.ctx,
-			model:    relation.Model,
-		}
-		config.builder(tempQuery)
-	}
+	e.scope(builder, relation.Model, config)
 
 	sqlStr, args := builder.BuildSelect()
+	buildErr := builder.Err()
 	PutBuilder(builder)
+	if buildErr != nil {
+		return nil, buildErr
+	}
 
 	rows, err := e.executor.QueryContext(e.ctx, sqlStr, args...)
 	if err != nil {
@@ -545,140 +697,231 @@ func (e *preloadExecutor) queryBelongsToData(relation *model.Relation, ids []any
 // queryManyToManyData queries the database for ManyToMany relations.
 // It involves a join query to map Parent IDs to Related Object IDs.
 // Returns a map of ParentID -> Slice of Related Objects.
+// Both the join table and the related table are queried in batches (see DB.PreloadBatchSize).
 func (e *preloadExecutor) queryManyToManyData(relation *model.Relation, ids []any, config *preloadConfig) (map[any][]any, error) {
 	// Step 1: Query the Join Table to get (ParentID, RelatedID) pairs
+	fkToRefs := make(map[any][]any)
+	for _, batch := range e.batches(ids) {
+		if err := e.queryJoinPairs(relation, batch, fkToRefs); err != nil {
+			return nil, err
+		}
+	}
+
+	// Step 2: Query the Related Table using the collected Related IDs
+	allRefValues := make([]any, 0)
+	for _, refs := range fkToRefs {
+		allRefValues = append(allRefValues, refs...)
+	}
+
+	if len(allRefValues) == 0 {
+		return make(map[any][]any), nil
+	}
+
+	refToData := make(map[any]any)
+	for _, batch := range e.batches(allRefValues) {
+		if err := e.queryRelatedByPK(relation, batch, config, refToData); err != nil {
+			return nil, err
+		}
+	}
+
+	// Step 3: Reconstruct the result map (ParentID -> Related Objects)
+	result := make(map[any][]any)
+	for fk, refs := range fkToRefs {
+		for _, ref := range refs {
+			if data, ok := refToData[ref]; ok {
+				result[fk] = append(result[fk], data)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// queryJoinPairs reads the (ParentID, RelatedID) pairs for the parent ids from the join
+// table into fkToRefs.
+func (e *preloadExecutor) queryJoinPairs(relation *model.Relation, ids []any, fkToRefs map[any][]any) error {
 	joinQuery := NewBuilder(e.db.dialect)
 	joinQuery.Select("jt."+relation.JoinFK, "jt."+relation.JoinRef)
 	joinQuery.SetTable(relation.JoinTable).Alias("jt")
 	joinQuery.WhereIn("jt."+relation.JoinFK, ids)
 
 	sqlStr, args := joinQuery.BuildSelect()
+	buildErr := joinQuery.Err()
 	PutBuilder(joinQuery)
+	if buildErr != nil {
+		return buildErr
+	}
 
 	rows, err := e.executor.QueryContext(e.ctx, sqlStr, args...)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer rows.Close()
 
-	fkToRefs := make(map[any][]any)
 	for rows.Next() {
 		var fkValue, refValue any
 		if err := rows.Scan(&fkValue, &refValue); err != nil {
-			return nil, err
+			return err
 		}
 		fkToRefs[fkValue] = append(fkToRefs[fkValue], refValue)
 	}
+	return rows.Err()
+}
 
-	// Step 2: Query the Related Table using the collected Related IDs
-	allRefValues := make([]any, 0)
-	for _, refs := range fkToRefs {
-		allRefValues = append(allRefValues, refs...)
+// scope runs the custom query modifications of config on builder, then adds the default
+// scope and soft delete condition of m, unless the modifications asked for all rows,
+// e.g. with Unscoped or WithTrashed.
+func (e *preloadExecutor) scope(builder Builder, m *model.Model, config *preloadConfig) {
+	q := &Query{
+		db:       e.db,
+		executor: e.executor,
+		builder:  builder,
+		ctx:      e.ctx,
+		model:    m,
 	}
-
-	if len(allRefValues) == 0 {
-		return make(map[any][]any), nil
+	if config.builder != nil {
+		config.builder(q)
 	}
+	q.applyScopes(m)
+}
 
+// queryRelatedByPK loads the related objects with the given primary keys into refToData.
+func (e *preloadExecutor) queryRelatedByPK(relation *model.Relation, refs []any, config *preloadConfig, refToData map[any]any) error {
 	builder := NewBuilder(e.db.dialect)
 	builder.Select("*")
 	builder.SetTable(relation.Model.TableName)
 
 	pkColumn := relation.Model.PKField.Column
-	builder.WhereIn(pkColumn, allRefValues)
+	builder.WhereIn(pkColumn, refs)
 
-	if config.builder != nil {
-		tempQuery := &Query{
-			db:       e.db,
-			executor: e.executor,
-			builder:  builder,
-			ctx:      e.ctx,
-			model:    relation.Model,
-		}
-		config.builder(tempQuery)
-	}
+	e.scope(builder, relation.Model, config)
 
-	sqlStr, args = builder.BuildSelect()
+	sqlStr, args := builder.BuildSelect()
+	buildErr := builder.Err()
 	PutBuilder(builder)
+	if buildErr != nil {
+		return buildErr
+	}
 
 	dataRows, err := e.executor.QueryContext(e.ctx, sqlStr, args...)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer dataRows.Close()
 
-	refToData := make(map[any]any)
 	for dataRows.Next() {
 		item := reflect.New(relation.Model.OriginalType).Interface()
 		if err := e.scanRow(dataRows, item); err != nil {
-			return nil, err
+			return err
 		}
 		pkValue := getFieldValue(item, pkColumn)
 		refToData[pkValue] = item
 	}
+	return dataRows.Err()
+}
 
-	// Step 3: Reconstruct the result map (ParentID -> Related Objects)
-	result := make(map[any][]any)
-	for fk, refs := range fkToRefs {
-		for _, ref := range refs {
-			if data, ok := refToData[ref]; ok {
-				result[fk] = append(result[fk], data)
-			}
-		}
+// batches splits ids into chunks of at most the DB's preload batch size.
+func (e *preloadExecutor) batches(ids []any) [][]any {
+	size := e.db.preloadBatchSize
+	if size <= 0 {
+		size = defaultPreloadBatchSize
+	}
+	chunks := make([][]any, 0, (len(ids)+size-1)/size)
+	for len(ids) > size {
+		chunks = append(chunks, ids[:size:size])
+		ids = ids[size:]
+	}
+	if len(ids) > 0 {
+		chunks = append(chunks, ids)
 	}
+	return chunks
+}
 
-	return result, nil
+// hasRelationTarget assigns the rows of a HasOne/HasMany preload to the parent
+// objects while they are scanned. It matches parent objects with related rows using
+// the primary key. Only the parents' fields are indexed, so memory does not grow with
+// the related set beyond the objects assigned.
+type hasRelationTarget struct {
+	hasOne  bool
+	byValue bool // the relation field holds structs rather than pointers
+	ids     []any
+	fields  map[any][]reflect.Value // parent primary key -> relation fields
+	seen    map[any]struct{}        // parent primary keys that received a row
 }
 
-// mapHasRelation assigns the loaded HasOne/HasMany data back to the parent objects.
-// It matches parent objects with related data using the primary key.
-func (e *preloadExecutor) mapHasRelation(slice reflect.Value, relation *model.Relation, pkField *model.Field, data map[any][]any) error {
-	fieldIndex := getRelationFieldIndex(slice.Type().Elem(), relation.Name)
+// newHasRelationTarget indexes the relation fields of the parents in slice. It returns
+// nil if their type has no field for the relation.
+func newHasRelationTarget(slice reflect.Value, relation *model.Relation, pkField *model.Field) *hasRelationTarget {
+	typ := slice.Type().Elem()
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	fieldIndex := getRelationFieldIndex(typ, relation.Name)
 	if fieldIndex < 0 {
 		return nil
 	}
 
+	t := &hasRelationTarget{
+		hasOne: relation.Type == model.RelationHasOne,
+		ids:    make([]any, 0, slice.Len()),
+		fields: make(map[any][]reflect.Value, slice.Len()),
+		seen:   make(map[any]struct{}),
+	}
+	elemType := typ.Field(fieldIndex).Type
+	if !t.hasOne && elemType.Kind() == reflect.Slice {
+		elemType = elemType.Elem()
+	}
+	t.byValue = elemType.Kind() != reflect.Ptr
 	for i := 0; i < slice.Len(); i++ {
 		item := slice.Index(i)
 		if item.Kind() == reflect.Ptr {
 			item = item.Elem()
 		}
 		pkValue := item.Field(pkField.Index).Interface()
-
-		items, ok := data[pkValue]
-		if !ok {
-			continue
+		if _, ok := t.fields[pkValue]; !ok {
+			t.ids = append(t.ids, pkValue)
 		}
+		t.fields[pkValue] = append(t.fields[pkValue], item.Field(fieldIndex))
+	}
+	return t
+}
 
-		field := item.Field(fieldIndex)
-		if relation.Type == model.RelationHasOne {
-			if len(items) > 0 {
-				if field.Kind() == reflect.Ptr {
-					field.Set(reflect.ValueOf(items[0]))
-				} else {
-					field.Set(reflect.ValueOf(items[0]).Elem())
-				}
+// assign sets the related object item, a pointer to a struct, on the parents whose
+// primary key is fk. Value fields receive a copy, so item may be reused if byValue. The first row of a parent replaces the field's previous value;
+// for HasOne later rows are ignored, for HasMany they are appended in query order.
+func (t *hasRelationTarget) assign(fk any, item reflect.Value) {
+	fields, ok := t.fields[fk]
+	if !ok {
+		return
+	}
+	_, loaded := t.seen[fk]
+	if !loaded {
+		t.seen[fk] = struct{}{}
+	}
+
+	for _, field := range fields {
+		if t.hasOne {
+			if loaded {
+				continue
 			}
-		} else {
-			// HasMany: Assign slice
-			sliceType := field.Type()
-			if sliceType.Kind() == reflect.Ptr {
-				sliceType = sliceType.Elem()
+			if field.Kind() == reflect.Ptr {
+				field.Set(item)
+			} else {
+				field.Set(item.Elem())
 			}
+			continue
+		}
 
-			newSlice := reflect.MakeSlice(sliceType, 0, len(items))
-			for _, item := range items {
-				if sliceType.Elem().Kind() == reflect.Ptr {
-					newSlice = reflect.Append(newSlice, reflect.ValueOf(item))
-				} else {
-					newSlice = reflect.Append(newSlice, reflect.ValueOf(item).Elem())
-				}
-			}
-			field.Set(newSlice)
+		// HasMany: append to a slice started by the parent's first row
+		if !loaded {
+			field.Set(reflect.MakeSlice(field.Type(), 0, 1))
+		}
+		if field.Type().Elem().Kind() == reflect.Ptr {
+			field.Set(reflect.Append(field, item))
+		} else {
+			field.Set(reflect.Append(field, item.Elem()))
 		}
 	}
-
-	return nil
 }
 
 // mapBelongsTo assigns the loaded BelongsTo data back to the parent objects.
@@ -771,10 +1014,8 @@ func (e *preloadExecutor) scanRow(rows *sql.Rows, dest any) error {
 	values := make([]any, len(columns))
 	for i, field := range plan.fields {
 		if field != nil {
-			if field.Type == timeType {
-				values[i] = &TimeScanner{}
-			} else if field.Type == timePtrType {
-				values[i] = &TimeScanner{}
+			if field.Type == timeType || field.Type == timePtrType {
+				values[i] = &TimeScanner{Location: e.db.location}
 			} else {
 				values[i] = reflect.New(field.Type).Interface()
 			}
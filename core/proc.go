@@ -0,0 +1,108 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MultiResult holds the result sets returned by a stored procedure, see DB.CallProc.
+// The sets are read from an open cursor in order: Scan moves forward to the requested
+// set, discarding the ones skipped, so a set cannot be scanned twice. Close must be
+// called to release the connection.
+type MultiResult struct {
+	query *Query
+	rows  *sql.Rows
+	set   int  // Index of the set rows is positioned at
+	read  bool // Whether the current set was scanned
+}
+
+// CallProc calls the stored procedure name with args and returns its result sets, e.g.
+//
+//	res, err := db.CallProc(ctx, "user_report", 2024)
+//	if err != nil {
+//		return err
+//	}
+//	defer res.Close()
+//	err = res.Scan(0, &users)
+//	err = res.Scan(1, &totals)
+//
+// The name may be qualified with a schema ("sales.user_report"). It is intended for
+// MySQL and SQL Server, whose procedures return rows directly; it returns
+// ErrInvalidQuery for databases without stored procedures, such as SQLite.
+func (db *DB) CallProc(ctx context.Context, name string, args ...any) (*MultiResult, error) {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		if !isIdentifier(part) {
+			return nil, fmt.Errorf("%w: invalid procedure name %q", ErrInvalidQuery, name)
+		}
+		parts[i] = db.dialect.Quote(part)
+	}
+	sqlStr := callProcSQL(db.dialect, strings.Join(parts, "."), len(args))
+	if sqlStr == "" {
+		return nil, fmt.Errorf("%w: the database does not support stored procedures", ErrInvalidQuery)
+	}
+
+	q := db.newQuery(db.pool).WithContext(ctx).Raw(sqlStr, args...)
+	defer q.release()
+	if q.err != nil {
+		return nil, q.err
+	}
+
+	final := func(ctx context.Context, query *Query) (*Result, error) {
+		if err := query.ctxErr(); err != nil {
+			return &Result{Error: err}, err
+		}
+		// Not retried like other reads, as the procedure may write
+		start := time.Now()
+		rows, err := query.executor.QueryContext(ctx, query.rawSQL, query.rawArgs...)
+		query.logSQL(query.rawSQL, time.Since(start), query.rawArgs...)
+		if err != nil {
+			err = query.handleError(fmt.Errorf("query execution failed: %w", err))
+			return &Result{Error: err}, fmt.Errorf("CallProc failed: %w", err)
+		}
+		query.handleError(nil)
+		return &Result{Data: rows}, nil
+	}
+
+	res, err := q.executeWithMiddleware(OpRaw, final)
+	if err != nil {
+		return nil, err
+	}
+	rows, ok := res.Data.(*sql.Rows)
+	if !ok {
+		return nil, fmt.Errorf("CallProc failed: middleware returned %T instead of rows", res.Data)
+	}
+	return &MultiResult{query: q, rows: rows}, nil
+}
+
+// Scan appends the rows of the result set at index (0 for the first) to dest, a pointer
+// to a slice of structs or struct pointers, as Find does. It returns ErrInvalidQuery if
+// the set was already read or passed, or if the procedure returned fewer sets.
+func (r *MultiResult) Scan(index int, dest any) error {
+	if index < r.set || (index == r.set && r.read) {
+		return fmt.Errorf("%w: result set %d was already read", ErrInvalidQuery, index)
+	}
+	for r.set < index {
+		if !r.rows.NextResultSet() {
+			if err := r.rows.Err(); err != nil {
+				return fmt.Errorf("failed to advance to result set %d: %w", index, err)
+			}
+			return fmt.Errorf("%w: result set %d does not exist", ErrInvalidQuery, index)
+		}
+		r.set++
+		r.read = false
+	}
+	r.read = true
+	if err := r.query.scanRows(r.rows, dest); err != nil {
+		return fmt.Errorf("failed to scan result set %d: %w", index, err)
+	}
+	return nil
+}
+
+// Close closes the cursor, discarding any result sets not read yet.
+func (r *MultiResult) Close() error {
+	return r.rows.Close()
+}
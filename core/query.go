@@ -3,13 +3,17 @@ package core
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/lib/pq"
+	"github.com/shrek82/jorm/dialect"
 	"github.com/shrek82/jorm/logger"
 	"github.com/shrek82/jorm/model"
 	"github.com/shrek82/jorm/validator"
@@ -30,14 +34,24 @@ type Query struct {
 	builder  Builder
 	ctx      context.Context
 	model    *model.Model
+	value    any // The value passed to Model, used by Association
 	err      error
 	rawSQL   string
 	rawArgs  []any
+	rawWhere bool // Set by WhereRaw, marks the query as containing hand-written SQL
 	LastSQL  string
 	LastArgs []any
 	Dest     any // The destination for query results (set by Find/First)
 	preloads []*preloadConfig
 	logger   logger.Logger
+	scope    softDeleteScope // Which soft-deleted rows the query sees
+	unscoped bool            // Set by Unscoped, skips the model's default scope
+	allowed  map[string]bool // Set by AllowColumns, lower-cased column names
+	emptyIn  EmptyInBehavior // Set by EmptyIn, how WhereIn treats an empty slice
+	op       OperationType   // Set by the terminal method, see Operation
+	// Set once the soft delete and default scope conditions have been added to the builder
+	scopesApplied bool
+	consumed      bool // Set by the first terminal method, see release
 }
 
 type scanPlan struct {
@@ -45,7 +59,8 @@ type scanPlan struct {
 	converters []converter
 }
 
-type converter func(src, dst reflect.Value)
+// converter stores src into dst and reports whether the types were compatible.
+type converter func(src, dst reflect.Value) bool
 
 var converterCache sync.Map
 
@@ -57,16 +72,19 @@ func getConverter(srcType, dstType reflect.Type) converter {
 
 	var conv converter
 	if srcType == dstType {
-		conv = func(src, dst reflect.Value) {
+		conv = func(src, dst reflect.Value) bool {
 			dst.Set(src)
+			return true
 		}
 	} else if srcType.ConvertibleTo(dstType) {
-		conv = func(src, dst reflect.Value) {
+		conv = func(src, dst reflect.Value) bool {
 			dst.Set(src.Convert(dstType))
+			return true
 		}
 	} else {
-		conv = func(src, dst reflect.Value) {
-			// Do nothing or handle error? The original code ignored failures.
+		// Incompatible types leave dst untouched; DB.StrictScan turns this into a ScanError
+		conv = func(src, dst reflect.Value) bool {
+			return false
 		}
 	}
 
@@ -93,6 +111,10 @@ type scanPlanKey struct {
 
 var scanPlanCache sync.Map
 
+// getScanPlan maps result columns to the fields of m and caches the mapping. Columns are
+// matched by column name, then by table-prefixed or JoinSelect name, then by an alias
+// equal to a field name, so computed columns such as "ROW_NUMBER() OVER (...) AS rn"
+// fill a field Rn of a result struct. Columns matching no field are scanned and dropped.
 func getScanPlan(m *model.Model, columns []string) *scanPlan {
 	key := scanPlanKey{
 		model: m,
@@ -110,17 +132,31 @@ func getScanPlan(m *model.Model, columns []string) *scanPlan {
 		var field *model.Field
 		if f, ok := m.FieldMap[col]; ok {
 			field = f
-		} else {
-			// Try matching with table prefix (e.g., "preload_user.name")
-			parts := strings.Split(col, ".")
-			if len(parts) > 1 {
-				lastPart := parts[len(parts)-1]
-				if f, ok := m.FieldMap[lastPart]; ok {
-					field = f
-				}
+		} else if i := strings.LastIndex(col, "."); i > 0 {
+			// Table-prefixed columns (e.g., "preload_user.name") go to the nested struct
+			// for that table if there is one, otherwise to the model's own field
+			prefix := col[:i]
+			if j := strings.LastIndex(prefix, "."); j >= 0 {
+				prefix = prefix[j+1:] // schema-qualified, e.g. "public.preload_user.name"
+			}
+			field = matchJoinedField(m, prefix, col[i+1:])
+			if f, ok := m.FieldMap[col[i+1:]]; ok && field == nil {
+				field = f
 			}
 		}
 
+		if field == nil && strings.Contains(col, joinAliasSep) {
+			// Columns selected by JoinSelect, e.g. "user__id" -> User.ID
+			prefix, column, _ := strings.Cut(col, joinAliasSep)
+			field = matchJoinedField(m, prefix, column)
+		}
+
+		if field == nil {
+			// Fall back to matching aliases such as "total_price" or "totalPrice"
+			// against the field name or column, ignoring case and underscores
+			field = matchFieldByName(m, col)
+		}
+
 		if field != nil {
 			fields[i] = field
 			// We can't easily get the destination field type here because of NestedIdx
@@ -136,6 +172,69 @@ func getScanPlan(m *model.Model, columns []string) *scanPlan {
 	return plan
 }
 
+// matchFieldByName finds the field whose name or column equals col after
+// normalization (lower case, underscores removed).
+func matchFieldByName(m *model.Model, col string) *model.Field {
+	key := normalizeName(col)
+	for _, f := range m.Fields {
+		if normalizeName(f.Name) == key || normalizeName(f.Column) == key {
+			return f
+		}
+	}
+	return nil
+}
+
+// matchJoinedField resolves a joined column, selected as "prefix__column" by JoinSelect
+// or labelled "prefix.column", to the field of a nested struct in m whose table name or
+// field name matches prefix. The returned field is a copy whose accessor reaches through
+// the nested struct, allocating it if it is a nil pointer.
+func matchJoinedField(m *model.Model, prefix, column string) *model.Field {
+	typ := m.OriginalType
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if !sf.IsExported() || sf.Anonymous || ft.Kind() != reflect.Struct || ft == timeType {
+			continue
+		}
+		nested, err := model.GetModel(reflect.New(ft).Interface())
+		if err != nil || (nested.TableName != prefix && normalizeName(sf.Name) != normalizeName(prefix)) {
+			continue
+		}
+		inner, ok := nested.FieldMap[column]
+		if !ok {
+			return nil
+		}
+		field := *inner
+		field.NestedIdx = append([]int{i}, inner.NestedIdx...)
+		index := i
+		field.Accessor = func(dest reflect.Value) reflect.Value {
+			f := dest.Field(index)
+			if f.Kind() == reflect.Ptr {
+				if f.IsNil() {
+					if !f.CanSet() {
+						return reflect.Value{}
+					}
+					f.Set(reflect.New(f.Type().Elem()))
+				}
+				f = f.Elem()
+			}
+			return inner.Accessor(f)
+		}
+		return &field
+	}
+	return nil
+}
+
+func normalizeName(name string) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
 // NewQuery creates a new Query instance with the specified DB, executor, and builder.
 // This is typically called internally by DB.Model, DB.Table, or DB.Raw.
 func NewQuery(db *DB, executor Executor, builder Builder) *Query {
@@ -156,10 +255,46 @@ func (q *Query) Model(value any) *Query {
 		return q
 	}
 	q.model = m
+	q.value = value
 	q.builder.SetTable(m.TableName)
 	return q
 }
 
+// GetModel returns the model metadata set by Model, or nil for table and raw queries.
+func (q *Query) GetModel() *model.Model {
+	return q.model
+}
+
+// Operation returns the kind of statement the query runs. It is set by the terminal method
+// before the middlewares run, so they can tell reads from writes, and is OpUnknown before.
+func (q *Query) Operation() OperationType {
+	return q.op
+}
+
+// TableName returns the table the query targets, or "" for raw queries. The cache
+// middlewares use it to drop the results cached for a table written to.
+func (q *Query) TableName() string {
+	if q.rawSQL != "" {
+		return ""
+	}
+	if sb, ok := q.builder.(*sqlBuilder); ok {
+		return sb.table
+	}
+	if q.model != nil {
+		return q.model.TableName
+	}
+	return ""
+}
+
+// ModelColumns returns the column names of the query's model in declaration order, or nil
+// for table and raw queries. See model.Model.Columns.
+func (q *Query) ModelColumns() []string {
+	if q.model == nil {
+		return nil
+	}
+	return q.model.Columns()
+}
+
 // Table sets the target table name for the query.
 func (q *Query) Table(name string) *Query {
 	q.builder.SetTable(name)
@@ -171,13 +306,57 @@ func (q *Query) Alias(alias string) *Query {
 	return q
 }
 
+// QuoteTable sets whether the query's table name is quoted, which it is by default.
+// Pass false when the name given to Table is already quoted, schema-qualified or
+// aliased, e.g. Table("`shop`.`order` o").QuoteTable(false), so it is written as is.
+// Inserts always quote the table.
+func (q *Query) QuoteTable(quote bool) *Query {
+	q.builder.QuoteTable(quote)
+	return q
+}
+
 // Select specifies the columns to be retrieved by the query.
 // If not called, all columns (*) will be selected by default.
 func (q *Query) Select(columns ...string) *Query {
+	if !q.checkColumns("Select", columns, false) {
+		return q
+	}
 	q.builder.Select(columns...)
 	return q
 }
 
+// SelectAs adds expr to the selected columns under alias, e.g. SelectAs("SUM(price)", "total")
+// selects SUM(price) AS total. Aliases are matched to struct fields by column or by name,
+// so "total" scans into a field named Total without a column tag.
+func (q *Query) SelectAs(expr, alias string) *Query {
+	q.builder.Select(expr + " AS " + q.db.dialect.Quote(alias))
+	return q
+}
+
+// SelectRaw adds expr to the selected columns as written, binding args to its "?"
+// placeholders, e.g. SelectRaw("COALESCE(name, ?) AS name", "unknown"). The arguments
+// come before those of joins and conditions, and are part of the SQL seen by caches.
+func (q *Query) SelectRaw(expr string, args ...any) *Query {
+	q.builder.SelectRaw(expr, args...)
+	return q
+}
+
+// SelectSum adds SUM(column) AS alias to the selected columns. Like SelectAs, the alias
+// scans into the struct field whose name or column matches it, e.g. "total" into Total.
+func (q *Query) SelectSum(column, alias string) *Query {
+	return q.SelectAs("SUM("+q.db.dialect.Quote(column)+")", alias)
+}
+
+// SelectAvg adds AVG(column) AS alias to the selected columns.
+func (q *Query) SelectAvg(column, alias string) *Query {
+	return q.SelectAs("AVG("+q.db.dialect.Quote(column)+")", alias)
+}
+
+// SelectCount adds COUNT(*) AS alias to the selected columns.
+func (q *Query) SelectCount(alias string) *Query {
+	return q.SelectAs("COUNT(*)", alias)
+}
+
 // Where adds a WHERE clause to the query.
 func (q *Query) Where(cond string, args ...any) *Query {
 	q.builder.Where(cond, args...)
@@ -190,11 +369,279 @@ func (q *Query) OrWhere(cond string, args ...any) *Query {
 	return q
 }
 
+// groupWhere parenthesizes the WHERE expression built so far, so that a condition ANDed
+// after it applies to all of it.
+func (q *Query) groupWhere() {
+	if sb, ok := q.builder.(*sqlBuilder); ok && sb.whereExpr != "" {
+		sb.whereExpr = "(" + sb.whereExpr + ")"
+	}
+}
+
+// WhereRaw adds a raw SQL condition to the WHERE clause. It builds the same SQL as Where,
+// but marks the query as containing raw SQL so linters and middlewares can audit it.
+func (q *Query) WhereRaw(sql string, args ...any) *Query {
+	q.rawWhere = true
+	q.builder.Where(sql, args...)
+	return q
+}
+
+// HasRawSQL reports whether the query was built with Raw or WhereRaw.
+func (q *Query) HasRawSQL() bool {
+	return q.rawWhere || q.rawSQL != ""
+}
+
+// EmptyInBehavior selects what WhereIn does with an empty slice, see Query.EmptyIn.
+type EmptyInBehavior int
+
+const (
+	// EmptyInMatchNone adds a condition matching no rows, as "column IN ()" would. It is
+	// the default, so an empty filter never widens a query, e.g. a DELETE, to every row.
+	EmptyInMatchNone EmptyInBehavior = iota
+	// EmptyInIgnore adds no condition, so an empty slice does not filter at all.
+	EmptyInIgnore
+)
+
+// EmptyIn sets how later WhereIn calls on the query treat an empty slice of values.
+func (q *Query) EmptyIn(behavior EmptyInBehavior) *Query {
+	q.emptyIn = behavior
+	return q
+}
+
+// WhereIn adds a "column IN (...)" condition with one placeholder per element of values,
+// which must be a slice or array. An empty slice matches no rows unless the query is set
+// to EmptyInIgnore, see EmptyIn and WhereInOrAll.
 func (q *Query) WhereIn(column string, values any) *Query {
+	if q.emptyIn == EmptyInIgnore && isEmptyList(values) {
+		return q
+	}
+	q.builder.WhereIn(column, values)
+	return q
+}
+
+// WhereInOrAll is like WhereIn but adds no condition if values is empty, for optional
+// filters such as a list of selected categories where none selected means all of them.
+func (q *Query) WhereInOrAll(column string, values any) *Query {
+	if isEmptyList(values) {
+		return q
+	}
 	q.builder.WhereIn(column, values)
 	return q
 }
 
+// isEmptyList reports whether values is a slice or array without elements.
+func isEmptyList(values any) bool {
+	v := reflect.ValueOf(values)
+	return (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Len() == 0
+}
+
+// WhereInTuple adds a multi-column IN condition for composite keys:
+//
+//	q.WhereInTuple([]string{"user_id", "role_id"}, [][]any{{1, 2}, {3, 4}})
+//
+// produces "(user_id, role_id) IN ((?, ?), (?, ?))", or an OR of AND groups on SQL Server.
+// Every row must have one value per column. An empty rows matches nothing.
+func (q *Query) WhereInTuple(columns []string, rows [][]any) *Query {
+	if len(columns) == 0 {
+		q.err = fmt.Errorf("%w: WhereInTuple requires at least one column", ErrInvalidQuery)
+		return q
+	}
+	for i, row := range rows {
+		if len(row) != len(columns) {
+			q.err = fmt.Errorf("%w: WhereInTuple row %d has %d values, expected %d", ErrInvalidQuery, i, len(row), len(columns))
+			return q
+		}
+	}
+	q.builder.WhereInTuple(columns, rows)
+	return q
+}
+
+// Scopes applies reusable query modifiers in order, so common filters can be shared:
+//
+//	func ActiveOnly(q *Query) *Query { return q.Where("status = ?", "active") }
+//	db.Model(&User{}).Scopes(ActiveOnly, ForTenant(7)).Find(&users)
+func (q *Query) Scopes(fns ...func(*Query) *Query) *Query {
+	for _, fn := range fns {
+		q = fn(q)
+	}
+	return q
+}
+
+// jsonOperators lists the comparison operators accepted by WhereJSON.
+var jsonOperators = map[string]bool{
+	"=": true, "<>": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true,
+	"LIKE": true, "NOT LIKE": true,
+}
+
+// WhereJSON compares the value at a dot-separated path inside a JSON column:
+//
+//	q.WhereJSON("attrs", "address.city", "=", "Paris")
+//
+// produces "JSON_EXTRACT(attrs, '$.address.city') = ?" on MySQL and SQLite and
+// "attrs#>>'{address,city}' = ?" on PostgreSQL. Postgres extracts text, so compare
+// numbers with a cast in Where instead.
+func (q *Query) WhereJSON(column, path, op string, value any) *Query {
+	op = strings.ToUpper(strings.TrimSpace(op))
+	if !jsonOperators[op] {
+		q.err = fmt.Errorf("%w: WhereJSON operator %q is not supported", ErrInvalidQuery, op)
+		return q
+	}
+	if path == "" {
+		q.err = fmt.Errorf("%w: WhereJSON requires a path", ErrInvalidQuery)
+		return q
+	}
+	expr, ok := jsonExtractSQL(q.db.dialect, column, path)
+	if !ok {
+		q.err = unsupported("WhereJSON")
+		return q
+	}
+	q.builder.Where(expr+" "+op+" ?", value)
+	return q
+}
+
+// WhereDate compares the date part of a datetime column with the date of value:
+//
+//	q.WhereDate("created_at", ">=", time.Now().AddDate(0, 0, -7))
+//
+// produces "DATE(created_at) >= ?" on MySQL and SQLite and "created_at::date >= ?" on
+// PostgreSQL, with value passed as "YYYY-MM-DD" in the DB's time zone.
+func (q *Query) WhereDate(column, op string, value time.Time) *Query {
+	op = strings.TrimSpace(op)
+	if !dateOperators[op] {
+		q.err = fmt.Errorf("%w: WhereDate operator %q is not supported", ErrInvalidQuery, op)
+		return q
+	}
+	if loc := q.location(); loc != nil {
+		value = value.In(loc)
+	}
+	expr, ok := dateSQL(q.db.dialect, column)
+	if !ok {
+		q.err = unsupported("WhereDate")
+		return q
+	}
+	q.builder.Where(expr+" "+op+" ?", value.Format("2006-01-02"))
+	return q
+}
+
+// WhereLike adds a "column LIKE pattern" condition. The pattern is passed as is, so % and
+// _ in it are wildcards; escape user input placed in it with EscapeLike:
+//
+//	q.WhereLike("name", "%"+core.EscapeLike(input)+"%")
+func (q *Query) WhereLike(column, pattern string) *Query {
+	q.builder.Where(likeSQL(q.db.dialect, column, false), pattern)
+	return q
+}
+
+// WhereILike is like WhereLike but matches case-insensitively, using ILIKE on PostgreSQL
+// and comparing LOWER() of both sides elsewhere.
+func (q *Query) WhereILike(column, pattern string) *Query {
+	q.builder.Where(likeSQL(q.db.dialect, column, true), pattern)
+	return q
+}
+
+// likeEscaper escapes the LIKE metacharacters with a backslash, see EscapeLike.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// EscapeLike escapes %, _ and backslashes in s so that it matches literally in a pattern
+// passed to WhereLike or WhereILike.
+func EscapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// dateOperators lists the comparison operators accepted by WhereDate.
+var dateOperators = map[string]bool{
+	"=": true, "<>": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true,
+}
+
+// WhereGroup builds a sub-condition in isolation and adds it to the WHERE clause
+// as a single parenthesized group joined with AND.
+// Example: q.Where("a = ?", 1).WhereGroup(func(g *Query) { g.Where("b = ?", 2).OrWhere("c = ?", 3) })
+// produces "WHERE (a = ?) AND ((b = ?) OR (c = ?))".
+func (q *Query) WhereGroup(fn func(q *Query)) *Query {
+	sub := &Query{
+		db:       q.db,
+		executor: q.executor,
+		builder:  NewBuilder(q.db.dialect),
+		ctx:      q.ctx,
+		model:    q.model,
+		logger:   q.logger,
+	}
+	fn(sub)
+	q.builder.WhereGroup(sub.builder)
+	PutBuilder(sub.builder)
+	q.rawWhere = q.rawWhere || sub.rawWhere
+	if sub.err != nil && q.err == nil {
+		q.err = sub.err
+	}
+	return q
+}
+
+// WhereExists adds a WHERE EXISTS (subquery) condition built from sub, typically a
+// correlated query referencing the outer table by name:
+//
+//	orders := db.Table("orders").Select("1").Where("orders.user_id = users.id")
+//	db.Table("users").WhereExists(orders)
+//
+// Arguments of sub are merged after the existing ones and renumbered for the dialect.
+// The default scope and soft delete filter of sub's model apply to sub.
+func (q *Query) WhereExists(sub *Query) *Query {
+	return q.whereSubquery(sub, false)
+}
+
+// WhereNotExists adds a WHERE NOT EXISTS (subquery) condition built from sub.
+func (q *Query) WhereNotExists(sub *Query) *Query {
+	return q.whereSubquery(sub, true)
+}
+
+func (q *Query) whereSubquery(sub *Query, not bool) *Query {
+	if sub.err != nil {
+		if q.err == nil {
+			q.err = sub.err
+		}
+		return q
+	}
+	sub.applyScopes(sub.model)
+	if not {
+		q.builder.WhereNotExists(sub.builder)
+	} else {
+		q.builder.WhereExists(sub.builder)
+	}
+	q.rawWhere = q.rawWhere || sub.rawWhere
+	return q
+}
+
+// FromSubQuery makes the query select from sub as a derived table named alias instead of
+// from its table:
+//
+//	totals := db.Table("orders").Select("user_id", "SUM(amount) AS total").GroupBy("user_id")
+//	db.FromSubQuery(totals, "t").Where("t.total > ?", 100).FindMaps(&rows)
+//
+// Arguments of sub are placed before the outer query's and numbered for the dialect.
+// The default scope and soft delete filter of sub's model apply to sub. The alias must be
+// a plain identifier, and sub cannot be a Raw query.
+func (q *Query) FromSubQuery(sub *Query, alias string) *Query {
+	if sub.err != nil {
+		if q.err == nil {
+			q.err = sub.err
+		}
+		return q
+	}
+	if q.err != nil {
+		return q
+	}
+	if sub.rawSQL != "" {
+		q.err = fmt.Errorf("%w: FromSubQuery does not support raw SQL subqueries", ErrInvalidQuery)
+		return q
+	}
+	if !isIdentifier(alias) {
+		q.err = fmt.Errorf("%w: invalid subquery alias %q", ErrInvalidQuery, alias)
+		return q
+	}
+	sub.applyScopes(sub.model)
+	q.builder.FromSubQuery(sub.builder, alias)
+	q.rawWhere = q.rawWhere || sub.rawWhere
+	return q
+}
+
 // Limit sets the LIMIT clause.
 func (q *Query) Limit(n int) *Query {
 	q.builder.Limit(n)
@@ -209,16 +656,112 @@ func (q *Query) Offset(n int) *Query {
 
 // OrderBy adds an ORDER BY clause.
 func (q *Query) OrderBy(columns ...string) *Query {
+	if !q.checkColumns("OrderBy", columns, true) {
+		return q
+	}
 	q.builder.OrderBy(columns...)
 	return q
 }
 
+// AllowColumns restricts the columns that later Select, OrderBy, GroupBy and OrderByNulls
+// calls accept to cols, for queries whose columns come from clients, e.g. a sort
+// parameter. Each entry passed to those methods must be one of cols, optionally followed
+// by ASC or DESC in OrderBy; comma-separated lists are checked entry by entry. Anything
+// else, including expressions, records ErrColumnNotAllowed on the query, which its
+// terminal method returns without running any SQL. Names are compared case-insensitively.
+// SelectAs, SelectRaw and the other expression methods are not checked.
+//
+//	q := db.Model(&User{}).AllowColumns("name", "age", "created_at").OrderBy(r.URL.Query().Get("sort"))
+func (q *Query) AllowColumns(cols ...string) *Query {
+	q.allowed = make(map[string]bool, len(cols))
+	for _, c := range cols {
+		q.allowed[strings.ToLower(strings.TrimSpace(c))] = true
+	}
+	return q
+}
+
+// checkColumns reports whether columns pass the AllowColumns list, recording
+// ErrColumnNotAllowed on the query if not. Sort terms may end with ASC or DESC.
+func (q *Query) checkColumns(method string, columns []string, sort bool) bool {
+	if q.allowed == nil {
+		return true
+	}
+	for _, entry := range columns {
+		for _, term := range strings.Split(entry, ",") {
+			parts := strings.Fields(term)
+			ok := len(parts) == 1 || (sort && len(parts) == 2 &&
+				(strings.EqualFold(parts[1], "ASC") || strings.EqualFold(parts[1], "DESC")))
+			if !ok || !q.allowed[strings.ToLower(parts[0])] {
+				q.err = fmt.Errorf("%w: %s %q", ErrColumnNotAllowed, method, strings.TrimSpace(term))
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// OrderByNulls adds an ORDER BY term sorting column in direction ("ASC" or "DESC") with
+// NULLs first or last, as nulls says ("FIRST" or "LAST"), whatever the database's
+// default. It uses NULLS FIRST/LAST where supported and a CASE on column IS NULL
+// elsewhere:
+//
+//	q.OrderByNulls("last_login", "DESC", "LAST")
+func (q *Query) OrderByNulls(column, direction, nulls string) *Query {
+	direction = strings.ToUpper(strings.TrimSpace(direction))
+	if direction != "ASC" && direction != "DESC" {
+		q.err = fmt.Errorf("%w: OrderByNulls direction %q must be ASC or DESC", ErrInvalidQuery, direction)
+		return q
+	}
+	nulls = strings.ToUpper(strings.TrimSpace(nulls))
+	if nulls != "FIRST" && nulls != "LAST" {
+		q.err = fmt.Errorf("%w: OrderByNulls nulls %q must be FIRST or LAST", ErrInvalidQuery, nulls)
+		return q
+	}
+	if !q.checkColumns("OrderByNulls", []string{column}, false) {
+		return q
+	}
+	q.builder.OrderBy(orderByNullsSQL(q.db.dialect, column, direction, nulls == "FIRST"))
+	return q
+}
+
 // WithContext sets the context for the query execution.
+// Tags attached to ctx with WithQueryTags are added to the query's logger fields.
 func (q *Query) WithContext(ctx context.Context) *Query {
 	q.ctx = ctx
+	if tags := QueryTags(ctx); len(tags) > 0 {
+		q.WithFields(tags)
+	}
 	return q
 }
 
+// queryTagsKey is the context key under which WithQueryTags stores its tags.
+type queryTagsKey struct{}
+
+// WithQueryTags returns a copy of ctx carrying request-scoped metadata such as a tenant
+// or trace id. Every query run with the context (via WithContext) logs the tags as
+// structured fields, and the cache middlewares include them in their cache keys.
+// Tags already present in ctx are kept unless overridden.
+func WithQueryTags(ctx context.Context, tags map[string]any) context.Context {
+	merged := make(map[string]any, len(tags))
+	for k, v := range QueryTags(ctx) {
+		merged[k] = v
+	}
+	for k, v := range tags {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, queryTagsKey{}, merged)
+}
+
+// QueryTags returns the tags attached to ctx by WithQueryTags, or nil if there are none.
+// The returned map must not be modified.
+func QueryTags(ctx context.Context) map[string]any {
+	if ctx == nil {
+		return nil
+	}
+	tags, _ := ctx.Value(queryTagsKey{}).(map[string]any)
+	return tags
+}
+
 // Cache enables caching for this query.
 // If ttl is provided, it sets the cache expiration.
 // If no ttl is provided, it uses the default expiration (usually 24h if not configured).
@@ -241,6 +784,13 @@ func (q *Query) WithFields(fields map[string]any) *Query {
 	return q
 }
 
+// WithLogger replaces the query's logger with l, e.g. to route a sensitive query to an
+// audit log. SQL and errors of this query are logged to l instead of the DB's logger.
+func (q *Query) WithLogger(l logger.Logger) *Query {
+	q.logger = l
+	return q
+}
+
 func (q *Query) logSQL(sql string, duration time.Duration, args ...any) {
 	q.LastSQL = sql
 	q.LastArgs = args
@@ -252,18 +802,97 @@ func (q *Query) logSQL(sql string, duration time.Duration, args ...any) {
 }
 
 // Raw sets a raw SQL query and its arguments.
+// A slice argument bound to a "?" placeholder is expanded to one "?" per element,
+// e.g. Raw("SELECT * FROM users WHERE id IN (?)", []int{1, 2}).
 func (q *Query) Raw(sql string, args ...any) *Query {
+	if hasExpandable(args) {
+		var sb strings.Builder
+		args = expandPlaceholders(&sb, sql, args, func(int) string { return "?" })
+		sql = sb.String()
+	}
 	q.rawSQL = sql
 	q.rawArgs = args
 	return q
 }
 
+// RawNamed sets a raw SQL statement that uses :name parameters instead of positional
+// ones. Each :name is replaced with a dialect placeholder in order of appearance and its
+// value from params is appended to the arguments, so a name may be used several times.
+// Tokens inside quoted strings and PostgreSQL "::" casts are left untouched.
+// A slice value is expanded to one placeholder per element, e.g. "id IN (:ids)".
+func (q *Query) RawNamed(sql string, params map[string]any) *Query {
+	expanded, args, err := expandNamed(q.db.dialect.Placeholder, sql, params)
+	if err != nil {
+		q.err = err
+		return q
+	}
+	return q.Raw(expanded, args...)
+}
+
+// expandNamed rewrites :name parameters in sql using placeholder and collects their values.
+func expandNamed(placeholder func(int) string, sql string, params map[string]any) (string, []any, error) {
+	var sb strings.Builder
+	var args []any
+	var quote byte
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == ':' && i+1 < len(sql) && sql[i+1] == ':':
+			sb.WriteString("::")
+			i++
+			continue
+		case c == ':' && i+1 < len(sql) && isNameStart(sql[i+1]):
+			j := i + 1
+			for j < len(sql) && (isNameStart(sql[j]) || (sql[j] >= '0' && sql[j] <= '9')) {
+				j++
+			}
+			name := sql[i+1 : j]
+			value, ok := params[name]
+			if !ok {
+				return "", nil, fmt.Errorf("%w: missing value for named parameter :%s", ErrInvalidSQL, name)
+			}
+			if isExpandable(value) {
+				v := reflect.ValueOf(value)
+				if v.Len() == 0 {
+					sb.WriteString("NULL")
+				}
+				for k := 0; k < v.Len(); k++ {
+					if k > 0 {
+						sb.WriteString(", ")
+					}
+					args = append(args, v.Index(k).Interface())
+					sb.WriteString(placeholder(len(args)))
+				}
+			} else {
+				args = append(args, value)
+				sb.WriteString(placeholder(len(args)))
+			}
+			i = j - 1
+			continue
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String(), args, nil
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
 // Preload preloads the specified relation.
 func (q *Query) Preload(name string) *Query {
 	return q.PreloadWith(name, nil)
 }
 
 // PreloadWith preloads the specified relation with a custom query function.
+// The related model's default scope and soft delete filter apply as for Find; call
+// Unscoped or WithTrashed in fn to load the rows they hide.
 func (q *Query) PreloadWith(name string, fn func(*Query)) *Query {
 	path := strings.Split(name, ".")
 	q.preloads = append(q.preloads, &preloadConfig{
@@ -273,6 +902,17 @@ func (q *Query) PreloadWith(name string, fn func(*Query)) *Query {
 	return q
 }
 
+// PreloadCount loads the number of related rows of a HasOne/HasMany relation into the
+// integer field named into on each parent, without loading the rows themselves, e.g.
+// PreloadCount("Orders", "OrdersCount"). The field must not be a column (tag it jorm:"-").
+func (q *Query) PreloadCount(relation, into string) *Query {
+	q.preloads = append(q.preloads, &preloadConfig{
+		path:      []string{relation},
+		countInto: into,
+	})
+	return q
+}
+
 // Joins adds a JOIN clause to the query.
 // It supports raw SQL JOIN clauses: q.Joins("JOIN users ON users.id = orders.user_id")
 func (q *Query) Joins(query string, args ...any) *Query {
@@ -280,8 +920,40 @@ func (q *Query) Joins(query string, args ...any) *Query {
 	return q
 }
 
+// joinAliasSep separates the table prefix from the column name in JoinSelect aliases.
+const joinAliasSep = "__"
+
+// JoinSelect selects every column of value's model from table (a joined table or its
+// alias) as "table__column", so columns with the same name in different tables, such as
+// id, no longer collide. Find and First scan these columns into the nested struct field
+// of dest whose type has that table name or whose field name matches table:
+//
+//	type OrderRow struct {
+//		Order Order
+//		User  User
+//	}
+//	db.Model(&Order{}).JoinSelect("order", &Order{}).JoinSelect("user", &User{}).
+//		Joins("JOIN user ON user.id = order.user_id").Find(&rows)
+func (q *Query) JoinSelect(table string, value any) *Query {
+	m, err := model.GetModel(value)
+	if err != nil {
+		q.err = fmt.Errorf("%w: JoinSelect: %w", ErrInvalidModel, err)
+		return q
+	}
+	quotedTable := q.db.dialect.Quote(table)
+	columns := make([]string, len(m.Fields))
+	for i, f := range m.Fields {
+		columns[i] = quotedTable + "." + q.db.dialect.Quote(f.Column) + " AS " + q.db.dialect.Quote(table+joinAliasSep+f.Column)
+	}
+	q.builder.Select(columns...)
+	return q
+}
+
 // GroupBy adds a GROUP BY clause to the query for the specified columns.
 func (q *Query) GroupBy(columns ...string) *Query {
+	if !q.checkColumns("GroupBy", columns, false) {
+		return q
+	}
 	q.builder.GroupBy(columns...)
 	return q
 }
@@ -298,78 +970,366 @@ func (q *Query) GetSelectSQL() (string, []any) {
 		return q.rawSQL, q.rawArgs
 	}
 	// Copy builder to avoid side effects? BuildSelect usually doesn't have side effects.
+	q.applyScopes(q.model)
 	return q.builder.BuildSelect()
 }
 
-func (q *Query) executeWithMiddleware(final QueryFunc) (*Result, error) {
-	var handler QueryFunc = final
-	middlewares := q.db.middlewares
-	for i := len(middlewares) - 1; i >= 0; i-- {
-		m := middlewares[i]
-		next := handler
-		handler = func(ctx context.Context, query *Query) (*Result, error) {
-			return m.Process(ctx, query, next)
-		}
+// release returns the builder to the pool after a terminal method and marks the query as
+// consumed. The query keeps a detached builder that is never pooled, so a later call fails
+// with ErrQueryConsumed instead of building SQL from a builder now owned by another query.
+// The detached builder keeps the dialect, so chaining on the query does not panic.
+func (q *Query) release() {
+	if q.consumed {
+		return
+	}
+	q.consumed = true
+	var d dialect.Dialect
+	if sb, ok := q.builder.(*sqlBuilder); ok {
+		d = sb.dialect
+	}
+	if d == nil && q.db != nil {
+		d = q.db.dialect
+	}
+	PutBuilder(q.builder)
+	q.builder = &sqlBuilder{dialect: d}
+	q.err = ErrQueryConsumed
+}
+
+// builderErr returns the builder's misuse error for non-raw queries, so terminals fail
+// fast instead of issuing broken SQL.
+func (q *Query) builderErr() error {
+	if q.rawSQL != "" {
+		return nil
+	}
+	return q.builder.Err()
+}
+
+// executeWithMiddleware records op as the query's operation and runs final through the
+// registered middlewares.
+func (q *Query) executeWithMiddleware(op OperationType, final QueryFunc) (*Result, error) {
+	q.op = op
+	// A cancelled or expired context fails fast instead of reaching the driver
+	if err := q.ctxErr(); err != nil {
+		return &Result{Error: err}, err
+	}
+	q.applyScopes(q.model)
+	var handler QueryFunc = func(ctx context.Context, query *Query) (*Result, error) {
+		// Run with the context the middleware passed on, e.g. one with a deadline, and put
+		// the caller's back as a middleware may cancel its own once the query is done
+		defer func(callerCtx context.Context) { query.ctx = callerCtx }(query.ctx)
+		query.ctx = ctx
+		return final(ctx, query)
+	}
+	middlewares := q.db.Middlewares()
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		m := middlewares[i]
+		next := handler
+		handler = func(ctx context.Context, query *Query) (*Result, error) {
+			return m.Process(ctx, query, next)
+		}
+	}
+	return handler(q.ctx, q)
+}
+
+// First retrieves the first record matching the query into dest. For Raw queries the SQL
+// is run as written, without adding a LIMIT, and the first row is scanned.
+func (q *Query) First(dest any) error {
+	defer q.release()
+	if q.err != nil {
+		return q.err
+	}
+	if err := q.builderErr(); err != nil {
+		return err
+	}
+	q.Dest = dest
+
+	final := func(ctx context.Context, query *Query) (*Result, error) {
+		if query.rawSQL == "" {
+			query.builder.Limit(1)
+		}
+		sqlStr, args := query.GetSelectSQL()
+		if err := query.queryRow(sqlStr, args, dest); err != nil {
+			return &Result{Error: err}, fmt.Errorf("First failed: %w", err)
+		}
+		return &Result{Data: dest}, nil
+	}
+
+	res, err := q.executeWithMiddleware(OpSelect, final)
+	if err != nil {
+		return err
+	}
+
+	if res.Data != dest && res.Data != nil {
+		// If middleware returned cached data, copy it to dest
+		q.copyResult(res.Data, dest)
+	}
+
+	return q.executePreloads(dest)
+}
+
+// FirstOr is like First, but if no record matches it returns the result of fallback,
+// which can fill dest with defaults or return a more specific error. A nil fallback
+// ignores the missing record.
+//
+//	err := db.Model(&Setting{}).Where("key = ?", key).FirstOr(&s, func() error {
+//		s = Setting{Key: key, Value: "default"}
+//		return nil
+//	})
+func (q *Query) FirstOr(dest any, fallback func() error) error {
+	err := q.First(dest)
+	if errors.Is(err, ErrRecordNotFound) {
+		if fallback == nil {
+			return nil
+		}
+		return fallback()
+	}
+	return err
+}
+
+// Take is like First, but reports whether a record was found instead of returning
+// ErrRecordNotFound: it returns (false, nil) if no record matches.
+func (q *Query) Take(dest any) (found bool, err error) {
+	err = q.First(dest)
+	if errors.Is(err, ErrRecordNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Reload re-selects value (a pointer to a model struct) by its primary key and overwrites
+// it with the row currently stored in the database. Fields not backed by a column, such as
+// relations, are reset unless preloaded again on q. It fails if the primary key is zero.
+func (q *Query) Reload(value any) error {
+	if err := q.err; err != nil {
+		q.release()
+		return err
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		q.release()
+		return fmt.Errorf("%w: Reload requires a pointer to a struct", ErrInvalidQuery)
+	}
+	m, err := model.GetModel(value)
+	if err != nil {
+		q.release()
+		return err
+	}
+	if m.PKField == nil {
+		q.release()
+		return fmt.Errorf("%w: %s has no primary key", ErrInvalidModel, m.TableName)
+	}
+	pk := m.PKField.Accessor(v.Elem())
+	if pk.IsZero() {
+		q.release()
+		return fmt.Errorf("%w: cannot reload %s with a zero primary key", ErrInvalidQuery, m.TableName)
+	}
+
+	// Scan into a fresh value so a failed reload leaves value untouched
+	fresh := reflect.New(m.OriginalType)
+	if err := q.Model(value).Where(q.db.dialect.Quote(m.PKField.Column)+" = ?", pk.Interface()).First(fresh.Interface()); err != nil {
+		return err
+	}
+	v.Elem().Set(fresh.Elem())
+	return nil
+}
+
+// Find retrieves all records matching the query into dest (must be a pointer to a slice).
+// The slice is reset to zero length first, so calling Find again with the same slice
+// replaces its contents; use FindAppend to keep them.
+// It also runs Raw queries, scanning rows through the model's scan plan and AfterFind hook.
+func (q *Query) Find(dest any) error {
+	defer q.release()
+	if q.err != nil {
+		return q.err
+	}
+	if err := q.builderErr(); err != nil {
+		return err
+	}
+	if v := reflect.ValueOf(dest); v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().Kind() == reflect.Slice {
+		v.Elem().SetLen(0)
+	}
+	q.Dest = dest
+
+	final := func(ctx context.Context, query *Query) (*Result, error) {
+		sqlStr, args := query.GetSelectSQL()
+		if err := query.queryRows(sqlStr, args, dest); err != nil {
+			return &Result{Error: err}, fmt.Errorf("Find failed: %w", err)
+		}
+		return &Result{Data: dest}, nil
+	}
+
+	res, err := q.executeWithMiddleware(OpSelect, final)
+	if err != nil {
+		return err
+	}
+
+	if res.Data != dest && res.Data != nil {
+		q.copyResult(res.Data, dest)
+	}
+
+	return q.executePreloads(dest)
+}
+
+// FindAppend is like Find but appends the records to the elements already in dest, e.g.
+// to collect the results of several queries into one slice. Preloads run for the appended
+// records only.
+func (q *Query) FindAppend(dest any) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		q.release()
+		return fmt.Errorf("FindAppend failed: dest must be a pointer to a slice")
+	}
+	found := reflect.New(v.Elem().Type())
+	if err := q.Find(found.Interface()); err != nil {
+		return err
 	}
-	return handler(q.ctx, q)
+	v.Elem().Set(reflect.AppendSlice(v.Elem(), found.Elem()))
+	return nil
 }
 
-// First retrieves the first record matching the query into dest.
-func (q *Query) First(dest any) error {
-	defer PutBuilder(q.builder)
+// FindMaps retrieves all records matching the query into dest as column-to-value maps,
+// without requiring a model. It works with Table, Model and Raw queries; []byte values
+// are converted to strings.
+func (q *Query) FindMaps(dest *[]map[string]any) error {
+	defer q.release()
 	if q.err != nil {
 		return q.err
 	}
+	if err := q.builderErr(); err != nil {
+		return err
+	}
+	if dest == nil {
+		return fmt.Errorf("FindMaps dest must not be nil")
+	}
 	q.Dest = dest
 
 	final := func(ctx context.Context, query *Query) (*Result, error) {
-		query.builder.Limit(1)
-		sqlStr, args := query.builder.BuildSelect()
-		if err := query.queryRow(sqlStr, args, dest); err != nil {
-			return &Result{Error: err}, fmt.Errorf("First failed: %w", err)
+		sqlStr, args := query.GetSelectSQL()
+		maps, err := query.queryMaps(sqlStr, args, 0)
+		if err != nil {
+			return &Result{Error: err}, fmt.Errorf("FindMaps failed: %w", err)
 		}
-		return &Result{Data: dest}, nil
+		return &Result{Data: maps}, nil
 	}
 
-	res, err := q.executeWithMiddleware(final)
+	res, err := q.executeWithMiddleware(OpSelect, final)
 	if err != nil {
 		return err
 	}
+	if maps, ok := res.Data.([]map[string]any); ok {
+		*dest = maps
+	}
+	return nil
+}
 
-	if res.Data != dest && res.Data != nil {
-		// If middleware returned cached data, copy it to dest
-		q.copyResult(res.Data, dest)
+// FirstMap retrieves the first record matching the query into dest as a column-to-value map.
+// It returns ErrRecordNotFound if no record matches.
+func (q *Query) FirstMap(dest *map[string]any) error {
+	defer q.release()
+	if q.err != nil {
+		return q.err
+	}
+	if err := q.builderErr(); err != nil {
+		return err
+	}
+	if dest == nil {
+		return fmt.Errorf("FirstMap dest must not be nil")
+	}
+	q.Dest = dest
+
+	// Limit before executing so cache middlewares do not key it like FindMaps
+	q.builder.Limit(1)
+
+	final := func(ctx context.Context, query *Query) (*Result, error) {
+		sqlStr, args := query.GetSelectSQL()
+		maps, err := query.queryMaps(sqlStr, args, 1)
+		if err != nil {
+			return &Result{Error: err}, fmt.Errorf("FirstMap failed: %w", err)
+		}
+		if len(maps) == 0 {
+			return &Result{Error: ErrRecordNotFound}, fmt.Errorf("FirstMap failed: %w", ErrRecordNotFound)
+		}
+		return &Result{Data: maps[0]}, nil
 	}
 
-	return q.executePreloads(dest)
+	res, err := q.executeWithMiddleware(OpSelect, final)
+	if err != nil {
+		return err
+	}
+	if row, ok := res.Data.(map[string]any); ok {
+		*dest = row
+	}
+	return nil
 }
 
-// Find retrieves all records matching the query into dest (must be a pointer to a slice).
-func (q *Query) Find(dest any) error {
-	defer PutBuilder(q.builder)
+// Value retrieves a single value, the only column of the first row, into dest, which
+// must be a pointer to a scalar such as *int64, *string or *sql.NullInt64:
+//
+//	var maxID int64
+//	err := db.Table("user").Select("MAX(id)").Value(&maxID)
+//
+// It works with Raw queries as well. It returns ErrRecordNotFound if no row matches and
+// ErrInvalidQuery if the result has more than one column. Use a sql.Null* type when the
+// value can be NULL, e.g. an aggregate over no rows.
+func (q *Query) Value(dest any) error {
+	defer q.release()
 	if q.err != nil {
 		return q.err
 	}
+	if err := q.builderErr(); err != nil {
+		return err
+	}
+	if v := reflect.ValueOf(dest); v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("%w: Value dest must be a non-nil pointer", ErrInvalidQuery)
+	}
 	q.Dest = dest
 
 	final := func(ctx context.Context, query *Query) (*Result, error) {
-		sqlStr, args := query.builder.BuildSelect()
-		if err := query.queryRows(sqlStr, args, dest); err != nil {
-			return &Result{Error: err}, fmt.Errorf("Find failed: %w", err)
+		if query.rawSQL == "" {
+			query.builder.Limit(1)
+		}
+		sqlStr, args := query.GetSelectSQL()
+		if err := query.builderErr(); err != nil {
+			return &Result{Error: err}, err
+		}
+
+		start := time.Now()
+		rows, err := query.executor.QueryContext(ctx, sqlStr, args...)
+		query.logSQL(sqlStr, time.Since(start), args...)
+		if err != nil {
+			return &Result{Error: err}, query.handleError(fmt.Errorf("Value failed: %w", err))
+		}
+		defer rows.Close()
+
+		cols, err := rows.Columns()
+		if err != nil {
+			return &Result{Error: err}, fmt.Errorf("Value failed: %w", err)
+		}
+		if len(cols) != 1 {
+			err := fmt.Errorf("%w: Value expects one column, got %d", ErrInvalidQuery, len(cols))
+			return &Result{Error: err}, err
+		}
+		if !rows.Next() {
+			if err := rows.Err(); err != nil {
+				return &Result{Error: err}, fmt.Errorf("Value failed: %w", err)
+			}
+			return &Result{Error: ErrRecordNotFound}, fmt.Errorf("Value failed: %w", ErrRecordNotFound)
+		}
+		if err := rows.Scan(dest); err != nil {
+			return &Result{Error: err}, fmt.Errorf("Value failed: %w", err)
 		}
 		return &Result{Data: dest}, nil
 	}
 
-	res, err := q.executeWithMiddleware(final)
+	res, err := q.executeWithMiddleware(OpSelect, final)
 	if err != nil {
 		return err
 	}
-
 	if res.Data != dest && res.Data != nil {
 		q.copyResult(res.Data, dest)
 	}
-
-	return q.executePreloads(dest)
+	return nil
 }
 
 func (q *Query) copyResult(src, dest any) {
@@ -389,14 +1349,22 @@ func (q *Query) copyResult(src, dest any) {
 // Count returns the total number of records matching the query.
 // It executes a "SELECT COUNT(*)" query and returns the result as an int64.
 func (q *Query) Count() (int64, error) {
-	defer PutBuilder(q.builder)
+	defer q.release()
 	if q.err != nil {
 		return 0, q.err
 	}
+	if err := q.builderErr(); err != nil {
+		return 0, err
+	}
+
+	// Select before executing so cache middlewares key on the aggregate, not SELECT *
+	q.builder.Select("COUNT(*)")
 
 	final := func(ctx context.Context, query *Query) (*Result, error) {
-		query.builder.Select("COUNT(*)")
 		sqlStr, args := query.builder.BuildSelect()
+		if err := query.builderErr(); err != nil {
+			return &Result{Error: err}, err
+		}
 
 		var count int64
 		start := time.Now()
@@ -412,7 +1380,7 @@ func (q *Query) Count() (int64, error) {
 	var countResult int64
 	q.Dest = &countResult
 
-	res, err := q.executeWithMiddleware(final)
+	res, err := q.executeWithMiddleware(OpSelect, final)
 	if err != nil {
 		return 0, err
 	}
@@ -437,15 +1405,22 @@ func (q *Query) Count() (int64, error) {
 // Sum calculates the sum of the specified numeric column for records matching the query.
 // It returns a float64 value and any error encountered.
 func (q *Query) Sum(column string) (float64, error) {
-	defer PutBuilder(q.builder)
+	defer q.release()
 	if q.err != nil {
 		return 0, q.err
 	}
+	if err := q.builderErr(); err != nil {
+		return 0, err
+	}
+
+	// Select before executing so cache middlewares key on the aggregate, not SELECT *
+	q.builder.Select("SUM(" + q.db.dialect.Quote(column) + ")")
 
 	final := func(ctx context.Context, query *Query) (*Result, error) {
-		quoted := query.db.dialect.Quote(column)
-		query.builder.Select("SUM(" + quoted + ")")
 		sqlStr, args := query.builder.BuildSelect()
+		if err := query.builderErr(); err != nil {
+			return &Result{Error: err}, err
+		}
 
 		var sum sql.NullFloat64
 		start := time.Now()
@@ -464,7 +1439,7 @@ func (q *Query) Sum(column string) (float64, error) {
 	var sumResult float64
 	q.Dest = &sumResult
 
-	res, err := q.executeWithMiddleware(final)
+	res, err := q.executeWithMiddleware(OpSelect, final)
 	if err != nil {
 		return 0, err
 	}
@@ -485,6 +1460,130 @@ func (q *Query) Sum(column string) (float64, error) {
 	return 0, fmt.Errorf("invalid sum result type: %T", res.Data)
 }
 
+// CountDistinct counts the distinct non-NULL values of column for records matching the query,
+// generating COUNT(DISTINCT column). The column may be qualified with its table, e.g.
+// "orders.user_id" in a query with joins.
+func (q *Query) CountDistinct(column string) (int64, error) {
+	defer q.release()
+	if q.err != nil {
+		return 0, q.err
+	}
+	if err := q.builderErr(); err != nil {
+		return 0, err
+	}
+
+	// Select before executing so cache middlewares key on the aggregate, not SELECT *
+	q.builder.Select("COUNT(DISTINCT " + q.quoteColumn(column) + ")")
+
+	final := func(ctx context.Context, query *Query) (*Result, error) {
+		sqlStr, args := query.builder.BuildSelect()
+		if err := query.builderErr(); err != nil {
+			return &Result{Error: err}, err
+		}
+
+		var count int64
+		start := time.Now()
+		err := query.executor.QueryRowContext(ctx, sqlStr, args...).Scan(&count)
+		query.logSQL(sqlStr, time.Since(start), args...)
+		if err != nil {
+			return &Result{Error: err}, fmt.Errorf("CountDistinct failed for column %s: %w", column, err)
+		}
+		return &Result{Data: count}, nil
+	}
+
+	// Set Dest to allow middleware to cache the result
+	var countResult int64
+	q.Dest = &countResult
+
+	res, err := q.executeWithMiddleware(OpSelect, final)
+	if err != nil {
+		return 0, err
+	}
+
+	if cPtr, ok := res.Data.(*int64); ok {
+		return *cPtr, nil
+	}
+	if count, ok := res.Data.(int64); ok {
+		return count, nil
+	}
+	return 0, fmt.Errorf("invalid count result type: %T", res.Data)
+}
+
+// quoteColumn quotes each dot-separated part of a possibly table-qualified column name.
+func (q *Query) quoteColumn(column string) string {
+	parts := strings.Split(column, ".")
+	for i, part := range parts {
+		parts[i] = q.db.dialect.Quote(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+// GroupConcat concatenates the values of column with separator using the dialect's
+// aggregate (GROUP_CONCAT, STRING_AGG or LISTAGG). dest receives one string per group
+// when combined with GroupBy, or a single string otherwise. Groups with no non-NULL
+// values are skipped.
+func (q *Query) GroupConcat(column, separator string, dest *[]string) error {
+	defer q.release()
+	if q.err != nil {
+		return q.err
+	}
+	if err := q.builderErr(); err != nil {
+		return err
+	}
+	if dest == nil {
+		return fmt.Errorf("GroupConcat dest must not be nil")
+	}
+
+	// Select before executing so cache middlewares key on the aggregate, not SELECT *
+	expr, ok := groupConcatSQL(q.db.dialect, q.db.dialect.Quote(column), separator)
+	if !ok {
+		return unsupported("GroupConcat")
+	}
+	q.builder.Select(expr)
+
+	final := func(ctx context.Context, query *Query) (*Result, error) {
+		sqlStr, args := query.builder.BuildSelect()
+		if err := query.builderErr(); err != nil {
+			return &Result{Error: err}, err
+		}
+
+		start := time.Now()
+		rows, err := query.executor.QueryContext(ctx, sqlStr, args...)
+		query.logSQL(sqlStr, time.Since(start), args...)
+		if err != nil {
+			return &Result{Error: err}, fmt.Errorf("GroupConcat failed for column %s: %w", column, err)
+		}
+		defer rows.Close()
+
+		values := []string{}
+		for rows.Next() {
+			var v sql.NullString
+			if err := rows.Scan(&v); err != nil {
+				return &Result{Error: err}, fmt.Errorf("GroupConcat scan failed: %w", err)
+			}
+			if v.Valid {
+				values = append(values, v.String)
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return &Result{Error: err}, fmt.Errorf("rows iteration error: %w", err)
+		}
+		return &Result{Data: values}, nil
+	}
+
+	// Set Dest to allow middleware to cache the result
+	q.Dest = dest
+
+	res, err := q.executeWithMiddleware(OpSelect, final)
+	if err != nil {
+		return err
+	}
+	if values, ok := res.Data.([]string); ok {
+		*dest = values
+	}
+	return nil
+}
+
 // Clone creates a new Query instance with a deep copy of the builder and other fields.
 func (q *Query) Clone() *Query {
 	newQ := &Query{
@@ -493,9 +1592,17 @@ func (q *Query) Clone() *Query {
 		builder:  q.builder.Clone(),
 		ctx:      q.ctx,
 		model:    q.model,
+		value:    q.value,
 		err:      q.err,
 		rawSQL:   q.rawSQL,
+		rawWhere: q.rawWhere,
 		logger:   q.logger,
+		scope:    q.scope,
+		unscoped: q.unscoped,
+		allowed:  q.allowed,
+		emptyIn:  q.emptyIn,
+
+		scopesApplied: q.scopesApplied,
 	}
 
 	if len(q.rawArgs) > 0 {
@@ -559,9 +1666,64 @@ func (q *Query) Paginate(page, perPage int64, dest any) (*Pagination, error) {
 	}, nil
 }
 
+// FindInBatches loads the records matching the query batchSize at a time into dest, a
+// pointer to a slice, and calls fn with dest after each batch. Batches are paged by
+// primary key ("WHERE pk > last ORDER BY pk") instead of OFFSET, so rows inserted or
+// deleted while iterating do not shift later batches. The query should not set its own
+// ORDER BY or LIMIT. An error returned by fn stops the iteration and is returned.
+func (q *Query) FindInBatches(dest any, batchSize int, fn func(batch any) error) error {
+	defer q.release()
+	if q.err != nil {
+		return q.err
+	}
+	if batchSize < 1 {
+		return fmt.Errorf("%w: FindInBatches batch size must be positive, got %d", ErrInvalidQuery, batchSize)
+	}
+	if q.model == nil || q.model.PKField == nil {
+		return fmt.Errorf("%w: FindInBatches requires a model with a primary key", ErrInvalidModel)
+	}
+	destVal := reflect.ValueOf(dest)
+	if destVal.Kind() != reflect.Ptr || destVal.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("%w: FindInBatches dest must be a pointer to a slice", ErrInvalidQuery)
+	}
+	sliceVal := destVal.Elem()
+	pk := q.db.dialect.Quote(q.model.PKField.Column)
+
+	var last any
+	for {
+		batchQ := q.Clone()
+		if last != nil {
+			// Group the query's conditions so that an OrWhere cannot bypass the keyset
+			batchQ.groupWhere()
+			batchQ.Where(pk+" > ?", last)
+		}
+		batchQ.OrderBy(pk).Limit(batchSize)
+
+		sliceVal.Set(reflect.MakeSlice(sliceVal.Type(), 0, batchSize))
+		if err := batchQ.Find(dest); err != nil {
+			return err
+		}
+		n := sliceVal.Len()
+		if n == 0 {
+			return nil
+		}
+		if err := fn(dest); err != nil {
+			return err
+		}
+		if n < batchSize {
+			return nil
+		}
+		lastRow := reflect.Indirect(sliceVal.Index(n - 1))
+		last = q.model.PKField.Accessor(lastRow).Interface()
+	}
+}
+
 // Scan executes a raw query and scans the result into dest.
 // dest can be a pointer to a struct or a pointer to a slice.
 func (q *Query) Scan(dest any) error {
+	if q.err != nil {
+		return q.err
+	}
 	if q.rawSQL == "" {
 		return fmt.Errorf("raw sql is empty")
 	}
@@ -586,7 +1748,7 @@ func (q *Query) Scan(dest any) error {
 		return &Result{Data: dest}, nil
 	}
 
-	res, err := q.executeWithMiddleware(final)
+	res, err := q.executeWithMiddleware(OpSelect, final)
 	if err != nil {
 		return err
 	}
@@ -609,6 +1771,9 @@ func (q *Query) Exec() (int64, error) {
 
 // ExecResult executes a raw SQL statement and returns the sql.Result.
 func (q *Query) ExecResult() (sql.Result, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
 	if q.rawSQL == "" {
 		return nil, fmt.Errorf("raw sql is empty")
 	}
@@ -630,7 +1795,7 @@ func (q *Query) ExecResult() (sql.Result, error) {
 		return &Result{RowsAffected: rows, LastInsertId: lastId}, nil
 	}
 
-	res, err := q.executeWithMiddleware(final)
+	res, err := q.executeWithMiddleware(OpRaw, final)
 	if err != nil {
 		return nil, err
 	}
@@ -646,24 +1811,75 @@ type startResult struct {
 func (r *startResult) LastInsertId() (int64, error) { return r.lastInsertId, nil }
 func (r *startResult) RowsAffected() (int64, error) { return r.rowsAffected, nil }
 
+// location returns the DB's configured time zone, or nil for time.Local.
+func (q *Query) location() *time.Location {
+	if q.db == nil {
+		return nil
+	}
+	return q.db.location
+}
+
+// ctxErr returns the error of the query's context if it is already cancelled or past its
+// deadline, e.g. context.Canceled.
+func (q *Query) ctxErr() error {
+	if q.ctx == nil {
+		return nil
+	}
+	if err := q.ctx.Err(); err != nil {
+		return fmt.Errorf("query not executed: %w", err)
+	}
+	return nil
+}
+
 func (q *Query) handleError(err error) error {
 	if err != nil && q.db != nil {
 		q.db.reportError(err)
-		if q.db.logger != nil && !errors.Is(err, ErrRecordNotFound) {
+		l := q.logger
+		if l == nil {
+			l = q.db.logger
+		}
+		if l != nil && !errors.Is(err, ErrRecordNotFound) {
 			if q.LastSQL != "" {
-				q.db.logger.Error("SQL: %s | args: %v |  SQL execution error: %v", q.LastSQL, q.LastArgs, err)
+				l.Error("SQL: %s | args: %v |  SQL execution error: %v", q.LastSQL, q.LastArgs, err)
 			} else {
-				q.db.logger.Error("SQL execution error: %v", err)
+				l.Error("SQL execution error: %v", err)
 			}
 		}
 	}
 	return err
 }
 
-func (q *Query) queryRow(sqlStr string, args []any, dest any) error {
+// queryContext runs a read query, re-issuing it once when it fails on a stale or dropped
+// connection (see isBadConn). Reads are idempotent, so the retry cannot duplicate effects.
+// Queries inside a transaction are not retried as they are bound to its connection.
+func (q *Query) queryContext(sqlStr string, args []any) (*sql.Rows, error) {
 	start := time.Now()
 	rows, err := q.executor.QueryContext(q.ctx, sqlStr, args...)
 	q.logSQL(sqlStr, time.Since(start), args...)
+	if _, inTx := q.executor.(*Tx); err != nil && !inTx && isBadConn(err) && q.ctx.Err() == nil {
+		start = time.Now()
+		rows, err = q.executor.QueryContext(q.ctx, sqlStr, args...)
+		q.logSQL(sqlStr, time.Since(start), args...)
+	}
+	return rows, err
+}
+
+// isBadConn reports whether err means the connection was stale or dropped, so the
+// statement never ran and can safely be re-issued on another connection.
+func isBadConn(err error) bool {
+	return errors.Is(err, driver.ErrBadConn) || ClassifyError(err) == ErrConnectionFailed
+}
+
+func (q *Query) queryRow(sqlStr string, args []any, dest any) error {
+	if err := q.ctxErr(); err != nil {
+		return err
+	}
+	// Fail on errors found while building the statement, e.g. ErrTooManyParameters, before
+	// the driver reports them less clearly
+	if err := q.builderErr(); err != nil {
+		return err
+	}
+	rows, err := q.queryContext(sqlStr, args)
 	if err != nil {
 		return q.handleError(fmt.Errorf("query execution failed: %w", err))
 	}
@@ -703,15 +1919,75 @@ func (q *Query) queryRow(sqlStr string, args []any, dest any) error {
 	return nil
 }
 
-func (q *Query) queryRows(sqlStr string, args []any, dest any) error {
+// queryMaps executes the query and returns each row as a column-to-value map.
+// A positive limit stops reading after that many rows.
+func (q *Query) queryMaps(sqlStr string, args []any, limit int) ([]map[string]any, error) {
+	if err := q.builderErr(); err != nil {
+		return nil, err
+	}
 	start := time.Now()
 	rows, err := q.executor.QueryContext(q.ctx, sqlStr, args...)
 	q.logSQL(sqlStr, time.Since(start), args...)
+	if err != nil {
+		return nil, q.handleError(fmt.Errorf("query execution failed: %w", err))
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, q.handleError(fmt.Errorf("failed to get columns: %w", err))
+	}
+
+	results := []map[string]any{}
+	values := make([]any, len(columns))
+	ptrs := make([]any, len(columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, q.handleError(fmt.Errorf("row scan failed: %w", err))
+		}
+		row := make(map[string]any, len(columns))
+		for i, col := range columns {
+			if b, ok := values[i].([]byte); ok {
+				row[col] = string(b)
+			} else {
+				row[col] = values[i]
+			}
+		}
+		results = append(results, row)
+		if limit > 0 && len(results) >= limit {
+			break
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, q.handleError(fmt.Errorf("rows iteration error: %w", err))
+	}
+	return results, nil
+}
+
+func (q *Query) queryRows(sqlStr string, args []any, dest any) error {
+	if err := q.ctxErr(); err != nil {
+		return err
+	}
+	// Fail on errors found while building the statement, e.g. ErrTooManyParameters, before
+	// the driver reports them less clearly
+	if err := q.builderErr(); err != nil {
+		return err
+	}
+	rows, err := q.queryContext(sqlStr, args)
 	if err != nil {
 		return q.handleError(fmt.Errorf("query execution failed: %w", err))
 	}
 	defer rows.Close()
+	return q.scanRows(rows, dest)
+}
 
+// scanRows appends the remaining rows of the current result set to dest, a pointer to a
+// slice of structs or struct pointers, running AfterFind hooks.
+func (q *Query) scanRows(rows *sql.Rows, dest any) error {
 	destValue := reflect.ValueOf(dest)
 	if destValue.Kind() != reflect.Ptr || destValue.Elem().Kind() != reflect.Slice {
 		return fmt.Errorf("dest must be a pointer to a slice")
@@ -801,6 +2077,9 @@ var (
 type TimeScanner struct {
 	Value time.Time
 	Valid bool
+	// Location interprets strings without a zone and is the zone of the scanned value.
+	// Nil means time.Local.
+	Location *time.Location
 }
 
 // Scan implements the sql.Scanner interface.
@@ -813,6 +2092,9 @@ func (s *TimeScanner) Scan(value any) error {
 	switch v := value.(type) {
 	case time.Time:
 		s.Value = v
+		if s.Location != nil {
+			s.Value = v.In(s.Location)
+		}
 		s.Valid = true
 		return nil
 	case []byte:
@@ -837,16 +2119,13 @@ func (s *TimeScanner) parse(v string) error {
 		time.RFC3339Nano,
 	}
 
-	for _, layout := range layouts {
-		if t, e := time.ParseInLocation(layout, v, time.Local); e == nil {
-			s.Value = t
-			s.Valid = true
-			return nil
-		}
+	loc := s.Location
+	if loc == nil {
+		loc = time.Local
 	}
-	// Fallback to UTC if Local fails or just try Parse
+	// Layouts with a zone offset keep it; the others are read in loc
 	for _, layout := range layouts {
-		if t, e := time.Parse(layout, v); e == nil {
+		if t, e := time.ParseInLocation(layout, v, loc); e == nil {
 			s.Value = t
 			s.Valid = true
 			return nil
@@ -856,6 +2135,16 @@ func (s *TimeScanner) parse(v string) error {
 	return fmt.Errorf("failed to parse time: %s", v)
 }
 
+// arrayScanner scans a Postgres array column into the slice pointed to by ptr.
+type arrayScanner struct {
+	ptr reflect.Value
+}
+
+// Scan implements the sql.Scanner interface.
+func (s *arrayScanner) Scan(value any) error {
+	return pq.Array(s.ptr.Interface()).Scan(value)
+}
+
 func (q *Query) scanRowWithPlan(rows *sql.Rows, dest any, plan *scanPlan) error {
 	buf := scanBufferPool.Get().(*scanBuffer)
 	if cap(buf.values) < len(plan.fields) {
@@ -867,10 +2156,10 @@ func (q *Query) scanRowWithPlan(rows *sql.Rows, dest any, plan *scanPlan) error
 
 	for i, field := range plan.fields {
 		if field != nil {
-			if field.Type == timeType {
-				buf.values[i] = &TimeScanner{}
-			} else if field.Type == timePtrType {
-				buf.values[i] = &TimeScanner{}
+			if field.Type == timeType || field.Type == timePtrType {
+				buf.values[i] = &TimeScanner{Location: q.location()}
+			} else if field.IsArray {
+				buf.values[i] = &arrayScanner{ptr: reflect.New(field.Type)}
 			} else {
 				buf.values[i] = reflect.New(field.Type).Interface()
 			}
@@ -881,6 +2170,11 @@ func (q *Query) scanRowWithPlan(rows *sql.Rows, dest any, plan *scanPlan) error
 	}
 
 	if err := rows.Scan(buf.values...); err != nil {
+		if q.db != nil && q.db.strictScan {
+			if scanErr := findScanError(rows, buf.values, plan); scanErr != nil {
+				return scanErr
+			}
+		}
 		return fmt.Errorf("sql scan failed: %w", err)
 	}
 
@@ -912,17 +2206,23 @@ func (q *Query) scanRowWithPlan(rows *sql.Rows, dest any, plan *scanPlan) error
 						val = reflect.Zero(field.Type)
 					}
 				}
+			} else if as, ok := buf.values[i].(*arrayScanner); ok {
+				val = as.ptr.Elem()
 			} else {
 				val = reflect.ValueOf(buf.values[i]).Elem()
 			}
-			setFieldValue(destValue, field, val, plan, i)
+			if err := setFieldValue(destValue, field, val, plan, i); err != nil && q.db != nil && q.db.strictScan {
+				return err
+			}
 		}
 	}
 
 	return nil
 }
 
-func setFieldValue(dest reflect.Value, field *model.Field, value reflect.Value, plan *scanPlan, index int) {
+// setFieldValue stores value into the field of dest. It returns a ScanError if the
+// value's type cannot be converted to the field's type, leaving the field unchanged.
+func setFieldValue(dest reflect.Value, field *model.Field, value reflect.Value, plan *scanPlan, index int) error {
 	f := field.Accessor(dest)
 	if f.IsValid() && f.CanSet() {
 		conv := plan.converters[index]
@@ -930,8 +2230,40 @@ func setFieldValue(dest reflect.Value, field *model.Field, value reflect.Value,
 			conv = getConverter(value.Type(), f.Type())
 			plan.converters[index] = conv
 		}
-		conv(value, f)
+		if !conv(value, f) {
+			return &ScanError{Column: field.Column, Field: field.Name, SrcType: value.Type().String(), DstType: f.Type().String()}
+		}
+	}
+	return nil
+}
+
+// findScanError rescans the current row one column at a time to find the column the
+// driver could not convert, and describes it as a ScanError. It returns nil if no
+// single column fails on its own.
+func findScanError(rows *sql.Rows, dests []any, plan *scanPlan) error {
+	probe := make([]any, len(dests))
+	for i := range probe {
+		var ignore any
+		probe[i] = &ignore
+	}
+	for i, field := range plan.fields {
+		if field == nil {
+			continue
+		}
+		probe[i] = dests[i]
+		err := rows.Scan(probe...)
+		var raw any
+		probe[i] = &raw
+		if err == nil {
+			continue
+		}
+		srcType := "NULL"
+		if rows.Scan(probe...) == nil && raw != nil {
+			srcType = reflect.TypeOf(raw).String()
+		}
+		return &ScanError{Column: field.Column, Field: field.Name, SrcType: srcType, DstType: field.Type.String(), Err: err}
 	}
+	return nil
 }
 
 // InsertWithValidator performs an insertion after successfully validating the model.
@@ -949,7 +2281,7 @@ func (q *Query) InsertWithValidator(value any, validators ...validator.Validator
 // It returns the last inserted ID and any error encountered.
 // It also handles BeforeInsert and AfterInsert hooks, and auto-populates time fields.
 func (q *Query) Insert(value any) (int64, error) {
-	defer PutBuilder(q.builder)
+	defer q.release()
 	if q.err != nil {
 		return 0, q.err
 	}
@@ -969,7 +2301,13 @@ func (q *Query) Insert(value any) (int64, error) {
 		}
 
 		query.builder.SetTable(m.TableName)
-		cols, vals := getModelValues(m, value, false)
+		cols, vals, err := getModelValues(m, value, false, query.location())
+		if err != nil {
+			return &Result{Error: err}, err
+		}
+		if err := validateEnums(m, cols, vals); err != nil {
+			return &Result{Error: err}, err
+		}
 		sqlStr, args := query.builder.BuildInsert(cols)
 
 		start := time.Now()
@@ -997,14 +2335,14 @@ func (q *Query) Insert(value any) (int64, error) {
 		return &Result{LastInsertId: id, Data: value}, nil
 	}
 
-	res, err := q.executeWithMiddleware(final)
+	res, err := q.executeWithMiddleware(OpInsert, final)
 	if err != nil {
 		return 0, err
 	}
 	return res.LastInsertId, nil
 }
 
-func getModelValues(m *model.Model, value any, update bool) ([]string, []any) {
+func getModelValues(m *model.Model, value any, update bool, loc *time.Location) ([]string, []any, error) {
 	val := reflect.ValueOf(value)
 	if val.Kind() == reflect.Ptr {
 		val = val.Elem()
@@ -1015,34 +2353,162 @@ func getModelValues(m *model.Model, value any, update bool) ([]string, []any) {
 	now := time.Now()
 
 	for _, field := range m.Fields {
-		if !update && field.IsAuto {
+		if field.Generated || (!update && field.IsAuto) {
 			continue
 		}
-		if update && field.IsPK {
+		if update && (field.IsPK || field.ReadOnly) {
 			continue
 		}
 
 		fVal := field.Accessor(val)
+		if !update && (field.DefaultFunc || field.NoAutoTime) && fVal.IsZero() {
+			// Leave the column out so the database evaluates its default expression,
+			// or stores NULL for an optional time without a default
+			continue
+		}
 		if !update && field.AutoTime && fVal.CanSet() {
 			fVal.Set(reflect.ValueOf(now))
 		} else if !update && fVal.CanSet() && field.Type.String() == "time.Time" && fVal.IsZero() {
 			// Auto-fill time.Time fields that are zero on insert, if not explicitly AutoTime
 			// This helps with MySQL 0000-00-00 error for non-nullable datetime columns
-			// But only if it's not a pointer (pointers can be nil)
+			// But only if it's not a pointer (pointers can be nil); no_auto_time opts out
 			fVal.Set(reflect.ValueOf(now))
 		}
 		if field.AutoUpdate && fVal.CanSet() {
 			fVal.Set(reflect.ValueOf(now))
 		}
 
+		// On update, zero values are skipped. For pointer fields only nil is skipped:
+		// a non-nil pointer is written even when it points to a zero value.
 		if update && fVal.IsZero() {
 			continue
 		}
 
+		arg, err := columnValue(field, fVal, loc)
+		if err != nil {
+			return nil, nil, err
+		}
 		columns = append(columns, field.Column)
-		args = append(args, fVal.Interface())
+		args = append(args, arg)
+	}
+	return columns, args, nil
+}
+
+// withoutReadOnly returns data without the entries for readonly and generated columns.
+// The caller's map is only copied when something has to be dropped.
+func withoutReadOnly(m *model.Model, data map[string]any) map[string]any {
+	for col := range data {
+		if f, ok := m.FieldMap[col]; ok && (f.ReadOnly || f.Generated) {
+			filtered := make(map[string]any, len(data))
+			for c, v := range data {
+				if f, ok := m.FieldMap[c]; !ok || !(f.ReadOnly || f.Generated) {
+					filtered[c] = v
+				}
+			}
+			return filtered
+		}
+	}
+	return data
+}
+
+// withNulls returns data with nil pointer values replaced by an untyped nil, which every
+// driver binds as NULL; some reject typed nil pointers. Pointers implementing
+// driver.Valuer are kept, as their Value method decides what a nil pointer writes.
+// data is copied rather than modified if a value is replaced.
+func withNulls(data map[string]any) map[string]any {
+	var out map[string]any
+	for col, v := range data {
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Ptr || !rv.IsNil() {
+			continue
+		}
+		if _, ok := v.(driver.Valuer); ok {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]any, len(data))
+			for c, v := range data {
+				out[c] = v
+			}
+		}
+		out[col] = nil
+	}
+	if out == nil {
+		return data
+	}
+	return out
+}
+
+// validateEnums checks the values written to enum columns against their allowed values.
+func validateEnums(m *model.Model, cols []string, vals []any) error {
+	for i, col := range cols {
+		if f, ok := m.FieldMap[col]; ok {
+			if err := checkEnum(f, vals[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkEnum returns ErrInvalidEnum if value is not allowed for the field. Nil values pass,
+// matching the CHECK constraint semantics for NULL.
+func checkEnum(f *model.Field, value any) error {
+	if len(f.Enum) == 0 {
+		return nil
+	}
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+	s := fmt.Sprint(v.Interface())
+	for _, allowed := range f.Enum {
+		if s == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s must be one of [%s], got %q", ErrInvalidEnum, f.Name, strings.Join(f.Enum, ", "), s)
+}
+
+// columnValue returns the driver argument for a field, wrapping array fields with pq.Array.
+// Pointer fields are dereferenced so the pointed-to value is written (nil writes NULL),
+// unless the pointer type implements driver.Valuer itself. Unsigned integers are bound as
+// int64, which every driver accepts; values above the int64 range return ErrValueOutOfRange
+// rather than being truncated. Times are converted to loc, if set, before being written.
+func columnValue(field *model.Field, fVal reflect.Value, loc *time.Location) (any, error) {
+	if field.IsArray {
+		return pq.Array(fVal.Interface()), nil
+	}
+	if fVal.Kind() == reflect.Ptr {
+		if fVal.IsNil() {
+			return nil, nil
+		}
+		if _, ok := fVal.Interface().(driver.Valuer); ok {
+			return fVal.Interface(), nil
+		}
+		fVal = fVal.Elem()
+	}
+	switch fVal.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if _, ok := fVal.Interface().(driver.Valuer); ok {
+			break
+		}
+		u := fVal.Uint()
+		if u > math.MaxInt64 {
+			return nil, fmt.Errorf("%w: %s value %d exceeds the int64 range", ErrValueOutOfRange, field.Name, u)
+		}
+		return int64(u), nil
 	}
-	return columns, args
+	if t, ok := fVal.Interface().(time.Time); ok && loc != nil {
+		return t.In(loc), nil
+	}
+	return fVal.Interface(), nil
 }
 
 func setPKValue(value any, pkField *model.Field, id int64) {
@@ -1052,8 +2518,13 @@ func setPKValue(value any, pkField *model.Field, id int64) {
 	}
 	f := pkField.Accessor(v)
 	if f.CanSet() {
-		if f.Kind() == reflect.Int64 || f.Kind() == reflect.Int {
+		switch f.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 			f.SetInt(id)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if id >= 0 {
+				f.SetUint(uint64(id))
+			}
 		}
 	}
 }
@@ -1063,11 +2534,61 @@ func setPKValue(value any, pkField *model.Field, id int64) {
 // It returns the total number of rows affected and any error encountered.
 // It also handles BeforeInsert and AfterInsert hooks for each record.
 func (q *Query) BatchInsert(values any) (int64, error) {
-	defer PutBuilder(q.builder)
+	defer q.release()
 	if q.err != nil {
 		return 0, q.err
 	}
+	res, err := q.batchInsert(values)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected, nil
+}
+
+// BatchResult is the outcome of BatchInsertResult.
+type BatchResult struct {
+	RowsAffected int64
+	// FirstInsertID is the auto-increment ID of the first inserted row; the others
+	// follow contiguously. It is 0 if the database does not report it (PostgreSQL,
+	// SQL Server, Oracle).
+	FirstInsertID int64
+}
+
+// BatchInsertResult is like BatchInsert but also returns the ID of the first inserted
+// row, as reported by MySQL and SQLite, and sets the auto-increment primary key of each
+// record in values from it. Records must be pointers, or values a slice of structs, for
+// their keys to be set.
+func (q *Query) BatchInsertResult(values any) (*BatchResult, error) {
+	defer q.release()
+	if q.err != nil {
+		return nil, q.err
+	}
+	res, err := q.batchInsert(values)
+	if err != nil {
+		return nil, err
+	}
+	result := &BatchResult{RowsAffected: res.RowsAffected, FirstInsertID: res.LastInsertId}
+	sliceVal := reflect.ValueOf(values)
+	if result.FirstInsertID == 0 || sliceVal.Len() == 0 {
+		return result, nil
+	}
+	m, err := model.GetModel(sliceVal.Index(0).Interface())
+	if err != nil || m.PKField == nil || !m.PKField.IsAuto {
+		return result, nil
+	}
+	for i := 0; i < sliceVal.Len(); i++ {
+		item := sliceVal.Index(i)
+		if item.Kind() != reflect.Ptr {
+			item = item.Addr()
+		}
+		setPKValue(item.Interface(), m.PKField, result.FirstInsertID+int64(i))
+	}
+	return result, nil
+}
 
+// batchInsert runs the multi-row INSERT of BatchInsert and BatchInsertResult. The
+// Result's LastInsertId holds the ID of the first row, or 0 if it is not known.
+func (q *Query) batchInsert(values any) (*Result, error) {
 	final := func(ctx context.Context, query *Query) (*Result, error) {
 		sliceVal := reflect.ValueOf(values)
 		if sliceVal.Kind() != reflect.Slice {
@@ -1084,46 +2605,14 @@ func (q *Query) BatchInsert(values any) (int64, error) {
 			return &Result{Error: err}, err
 		}
 
-		var columns []string
-		for _, field := range m.Fields {
-			if !field.IsAuto {
-				columns = append(columns, field.Column)
-			}
+		columns, rows, err := query.batchRows(m, sliceVal)
+		if err != nil {
+			return &Result{Error: err}, err
 		}
-
 		sqlStr, _ := query.db.dialect.BatchInsertSQL(m.TableName, columns, sliceVal.Len())
 		var args []any
-		now := time.Now()
-
-		for i := 0; i < sliceVal.Len(); i++ {
-			item := sliceVal.Index(i).Interface()
-			val := reflect.ValueOf(item)
-			if val.Kind() == reflect.Ptr {
-				val = val.Elem()
-			}
-
-			// Hooks
-			if m.HasBeforeInsert {
-				if h, ok := item.(model.BeforeInserter); ok {
-					if err := h.BeforeInsert(); err != nil {
-						return &Result{Error: err}, err
-					}
-				}
-			}
-
-			for _, field := range m.Fields {
-				if field.IsAuto {
-					continue
-				}
-				fVal := val.Field(field.Index)
-				if (field.AutoTime || field.AutoUpdate) && fVal.CanSet() {
-					fVal.Set(reflect.ValueOf(now))
-				} else if fVal.CanSet() && field.Type.String() == "time.Time" && fVal.IsZero() {
-					// Auto-fill time.Time fields that are zero on insert for BatchInsert as well
-					fVal.Set(reflect.ValueOf(now))
-				}
-				args = append(args, fVal.Interface())
-			}
+		for _, row := range rows {
+			args = append(args, row...)
 		}
 
 		start := time.Now()
@@ -1134,16 +2623,25 @@ func (q *Query) BatchInsert(values any) (int64, error) {
 		}
 
 		totalAffected, _ := res.RowsAffected()
+		var firstID int64
+		if lastID, err := res.LastInsertId(); err == nil {
+			if id, ok := batchFirstInsertID(query.db.dialect, lastID, int64(sliceVal.Len())); ok {
+				firstID = id
+			}
+		}
 
 		// AfterInsert hooks (Batch)
 		if m.HasAfterInsert {
 			for i := 0; i < sliceVal.Len(); i++ {
 				item := sliceVal.Index(i).Interface()
 				if h, ok := item.(model.AfterInserter); ok {
-					// Note: LastInsertId in batch mode is driver-dependent
-					// Usually returns the first ID of the batch
-					id, _ := res.LastInsertId()
-					if err := h.AfterInsert(id + int64(i)); err != nil {
+					// Note: LastInsertId in batch mode is driver-dependent,
+					// the IDs are only known where the dialect can derive the first one
+					var id int64
+					if firstID != 0 {
+						id = firstID + int64(i)
+					}
+					if err := h.AfterInsert(id); err != nil {
 						return &Result{RowsAffected: totalAffected, Error: err}, query.handleError(err)
 					}
 				}
@@ -1151,10 +2649,171 @@ func (q *Query) BatchInsert(values any) (int64, error) {
 		}
 
 		query.handleError(nil)
-		return &Result{RowsAffected: totalAffected}, nil
+		return &Result{RowsAffected: totalAffected, LastInsertId: firstID}, nil
+	}
+
+	return q.executeWithMiddleware(OpInsert, final)
+}
+
+// batchRows runs the BeforeInsert hooks of the items in sliceVal and returns the columns
+// written by a batch insert along with the arguments of each row. As the rows share one
+// column list, a column with a function default or no_auto_time is left out only when it
+// is zero in every item, so the database evaluates its default; otherwise zero values are
+// written, as NULL for no_auto_time.
+func (q *Query) batchRows(m *model.Model, sliceVal reflect.Value) ([]string, [][]any, error) {
+	items := make([]reflect.Value, 0, sliceVal.Len())
+	for i := 0; i < sliceVal.Len(); i++ {
+		item := sliceVal.Index(i).Interface()
+		// Hooks
+		if m.HasBeforeInsert {
+			if h, ok := item.(model.BeforeInserter); ok {
+				if err := h.BeforeInsert(); err != nil {
+					return nil, nil, err
+				}
+			}
+		}
+		items = append(items, reflect.Indirect(reflect.ValueOf(item)))
+	}
+
+	var fields []*model.Field
+	var columns []string
+	for _, field := range m.Fields {
+		if field.IsAuto || field.Generated {
+			continue
+		}
+		if (field.DefaultFunc || field.NoAutoTime) && allZero(field, items) {
+			continue
+		}
+		fields = append(fields, field)
+		columns = append(columns, field.Column)
+	}
+
+	rows := make([][]any, 0, len(items))
+	now := time.Now()
+	for _, val := range items {
+		args := make([]any, 0, len(columns))
+		for _, field := range fields {
+			fVal := field.Accessor(val)
+			if field.NoAutoTime && fVal.IsZero() {
+				args = append(args, nil)
+				continue
+			}
+			if (field.AutoTime || field.AutoUpdate) && fVal.CanSet() {
+				fVal.Set(reflect.ValueOf(now))
+			} else if fVal.CanSet() && field.Type.String() == "time.Time" && fVal.IsZero() {
+				// Auto-fill time.Time fields that are zero on insert for BatchInsert as well
+				fVal.Set(reflect.ValueOf(now))
+			}
+			if err := checkEnum(field, fVal.Interface()); err != nil {
+				return nil, nil, err
+			}
+			arg, err := columnValue(field, fVal, q.location())
+			if err != nil {
+				return nil, nil, err
+			}
+			args = append(args, arg)
+		}
+		rows = append(rows, args)
+	}
+	return columns, rows, nil
+}
+
+// allZero reports whether field is zero in each of items.
+func allZero(field *model.Field, items []reflect.Value) bool {
+	for _, val := range items {
+		if !field.Accessor(val).IsZero() {
+			return false
+		}
+	}
+	return true
+}
+
+// BatchUpsert inserts values (a slice of models) with multi-row INSERT statements; rows
+// conflicting with an existing row on conflictColumns, which must form a primary or
+// unique key, update that row instead. All inserted columns except the conflict columns,
+// the primary key and auto_time and readonly columns are updated. Rows are sent in
+// chunks that stay under the database's placeholder limit; the chunks are not atomic, so
+// run BatchUpsert inside a transaction when that matters. BeforeInsert hooks run, but
+// AfterInsert hooks do not as the ids of updated rows are unknown.
+// It returns the number of rows affected as reported by the driver; MySQL counts an
+// updated row twice.
+func (q *Query) BatchUpsert(values any, conflictColumns []string) (int64, error) {
+	defer q.release()
+	if q.err != nil {
+		return 0, q.err
+	}
+	if len(conflictColumns) == 0 {
+		return 0, fmt.Errorf("%w: BatchUpsert requires conflict columns", ErrInvalidQuery)
+	}
+
+	final := func(ctx context.Context, query *Query) (*Result, error) {
+		sliceVal := reflect.ValueOf(values)
+		if sliceVal.Kind() != reflect.Slice {
+			return &Result{Error: fmt.Errorf("values must be a slice")}, fmt.Errorf("values must be a slice")
+		}
+		if sliceVal.Len() == 0 {
+			return &Result{RowsAffected: 0}, nil
+		}
+
+		m, err := model.GetModel(sliceVal.Index(0).Interface())
+		if err != nil {
+			return &Result{Error: err}, err
+		}
+		columns, rows, err := query.batchRows(m, sliceVal)
+		if err != nil {
+			return &Result{Error: err}, err
+		}
+
+		conflict := make(map[string]bool, len(conflictColumns))
+		for _, c := range conflictColumns {
+			conflict[c] = true
+		}
+		var updates []string
+		for _, field := range m.Fields {
+			if !field.IsAuto && !field.IsPK && !field.AutoTime && !field.ReadOnly && !field.Generated && !conflict[field.Column] {
+				updates = append(updates, field.Column)
+			}
+		}
+		clause := upsertSQL(query.db.dialect, conflictColumns, updates)
+		if clause == "" {
+			err := unsupported("BatchUpsert")
+			return &Result{Error: err}, err
+		}
+
+		params := maxPlaceholders(query.db.dialect)
+		if params == 0 {
+			params = defaultMaxPlaceholders
+		}
+		chunkSize := params / len(columns)
+		if chunkSize < 1 {
+			chunkSize = 1
+		}
+		var total int64
+		for len(rows) > 0 {
+			n := min(chunkSize, len(rows))
+			sqlStr, _ := query.db.dialect.BatchInsertSQL(m.TableName, columns, n)
+			sqlStr += " " + clause
+			var args []any
+			for _, row := range rows[:n] {
+				args = append(args, row...)
+			}
+			rows = rows[n:]
+
+			start := time.Now()
+			res, err := query.executor.ExecContext(ctx, sqlStr, args...)
+			query.logSQL(sqlStr, time.Since(start), args...)
+			if err != nil {
+				return &Result{RowsAffected: total, Error: err}, query.handleError(fmt.Errorf("BatchUpsert failed: %w", err))
+			}
+			affected, _ := res.RowsAffected()
+			total += affected
+		}
+
+		query.handleError(nil)
+		return &Result{RowsAffected: total}, nil
 	}
 
-	res, err := q.executeWithMiddleware(final)
+	res, err := q.executeWithMiddleware(OpInsert, final)
 	if err != nil {
 		return 0, err
 	}
@@ -1174,10 +2833,14 @@ func (q *Query) UpdateWithValidator(value any, validators ...validator.Validator
 
 // Update updates the records matching the query with the provided data.
 // The value parameter can be a struct (updates non-zero fields) or a map[string]any.
+// For pointer fields in a struct, nil is skipped and a non-nil pointer always writes the
+// value it points to, so a *string pointing at "" sets the column to an empty string.
+// Columns tagged readonly are never written; map entries for them are dropped.
+// A nil map value, including a nil pointer, sets the column to NULL.
 // It returns the number of rows affected and any error encountered.
 // It handles BeforeUpdate and AfterUpdate hooks for struct updates.
 func (q *Query) Update(value any) (int64, error) {
-	defer PutBuilder(q.builder)
+	defer q.release()
 	if q.err != nil {
 		return 0, q.err
 	}
@@ -1193,6 +2856,10 @@ func (q *Query) Update(value any) (int64, error) {
 				return &Result{Error: fmt.Errorf("model metadata is required for map update")}, fmt.Errorf("model metadata is required for map update")
 			}
 			m = query.model
+			if data = withoutReadOnly(m, data); len(data) == 0 {
+				return &Result{RowsAffected: 0}, nil
+			}
+			data = withNulls(data)
 		} else {
 			m, err = model.GetModel(value)
 			if err != nil {
@@ -1207,15 +2874,33 @@ func (q *Query) Update(value any) (int64, error) {
 				}
 			}
 
-			cols, vals := getModelValues(m, value, true)
+			cols, vals, err := getModelValues(m, value, true, query.location())
+			if err != nil {
+				return &Result{Error: err}, err
+			}
 			data = make(map[string]any)
 			for i, col := range cols {
 				data[col] = vals[i]
 			}
 		}
 
+		for col, v := range data {
+			if f, ok := m.FieldMap[col]; ok {
+				if err := checkEnum(f, v); err != nil {
+					return &Result{Error: err}, err
+				}
+			}
+		}
+
+		query.applyScopes(m)
 		query.builder.SetTable(m.TableName)
+		if err := query.builder.Err(); err != nil {
+			return &Result{Error: err}, err
+		}
 		sqlStr, args := query.builder.BuildUpdate(data)
+		if err := query.builder.Err(); err != nil {
+			return &Result{Error: err}, err
+		}
 
 		start := time.Now()
 		res, err := query.executor.ExecContext(ctx, sqlStr, args...)
@@ -1241,7 +2926,97 @@ func (q *Query) Update(value any) (int64, error) {
 		return &Result{RowsAffected: rows}, nil
 	}
 
-	res, err := q.executeWithMiddleware(final)
+	res, err := q.executeWithMiddleware(OpUpdate, final)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected, nil
+}
+
+// Touch sets the auto_update columns of the records matching the query to the current
+// time without changing anything else. If a model instance is provided, its primary key
+// selects the record and its auto_update fields are set as well. It returns
+// ErrInvalidModel if the model has no auto_update field.
+func (q *Query) Touch(value ...any) (int64, error) {
+	defer q.release()
+	if q.err != nil {
+		return 0, q.err
+	}
+
+	final := func(ctx context.Context, query *Query) (*Result, error) {
+		m := query.model
+		if len(value) > 0 {
+			var err error
+			m, err = model.GetModel(value[0])
+			if err != nil {
+				return &Result{Error: err}, fmt.Errorf("failed to get model: %w", err)
+			}
+			if m.PKField != nil {
+				v := reflect.Indirect(reflect.ValueOf(value[0]))
+				query.builder.Where(query.db.dialect.Quote(m.PKField.Column)+" = ?", m.PKField.Accessor(v).Interface())
+			}
+		}
+		if m == nil {
+			err := fmt.Errorf("model metadata is required for touch")
+			return &Result{Error: err}, err
+		}
+
+		now := time.Now()
+		var ts any = now
+		if loc := query.location(); loc != nil {
+			ts = now.In(loc)
+		}
+		data := make(map[string]any)
+		for _, f := range m.Fields {
+			if f.AutoUpdate {
+				data[f.Column] = ts
+			}
+		}
+		if len(data) == 0 {
+			err := fmt.Errorf("%w: %s has no auto_update field", ErrInvalidModel, m.TableName)
+			return &Result{Error: err}, err
+		}
+
+		query.applyScopes(m)
+		query.builder.SetTable(m.TableName)
+		if err := query.builder.Err(); err != nil {
+			return &Result{Error: err}, err
+		}
+		sqlStr, args := query.builder.BuildUpdate(data)
+		if err := query.builder.Err(); err != nil {
+			return &Result{Error: err}, err
+		}
+
+		start := time.Now()
+		res, err := query.executor.ExecContext(ctx, sqlStr, args...)
+		query.logSQL(sqlStr, time.Since(start), args...)
+		if err != nil {
+			return &Result{Error: err}, query.handleError(fmt.Errorf("Touch execution failed: %w", err))
+		}
+
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return &Result{Error: err}, query.handleError(fmt.Errorf("failed to get rows affected: %w", err))
+		}
+		if len(value) > 0 {
+			v := reflect.Indirect(reflect.ValueOf(value[0]))
+			for _, f := range m.Fields {
+				if !f.AutoUpdate {
+					continue
+				}
+				if fVal := f.Accessor(v); fVal.CanSet() && fVal.Kind() == reflect.Ptr {
+					fVal.Set(reflect.ValueOf(&now))
+				} else if fVal.CanSet() {
+					fVal.Set(reflect.ValueOf(now))
+				}
+			}
+		}
+
+		query.handleError(nil)
+		return &Result{RowsAffected: rows}, nil
+	}
+
+	res, err := q.executeWithMiddleware(OpUpdate, final)
 	if err != nil {
 		return 0, err
 	}
@@ -1252,8 +3027,10 @@ func (q *Query) Update(value any) (int64, error) {
 // If a model instance is provided, it uses its primary key for the deletion criteria.
 // It returns the number of rows affected and any error encountered.
 // It handles BeforeDelete and AfterDelete hooks if a model instance is provided.
+// Models with a DeletedAt field, or the column set with Options.SoftDeleteColumn, are
+// soft-deleted by setting it to the current time or true, unless the query is Unscoped.
 func (q *Query) Delete(value ...any) (int64, error) {
-	defer PutBuilder(q.builder)
+	defer q.release()
 	if q.err != nil {
 		return 0, q.err
 	}
@@ -1290,8 +3067,24 @@ func (q *Query) Delete(value ...any) (int64, error) {
 			return &Result{Error: fmt.Errorf("model metadata is required for delete")}, fmt.Errorf("model metadata is required for delete")
 		}
 
+		query.applyScopes(m)
 		query.builder.SetTable(m.TableName)
-		sqlStr, args := query.builder.BuildDelete()
+		if err := query.builder.Err(); err != nil {
+			return &Result{Error: err}, err
+		}
+		var sqlStr string
+		var args []any
+		now := time.Now()
+		soft := query.softDeletes(m)
+		if soft {
+			f := query.softDeleteField(m)
+			sqlStr, args = query.builder.BuildUpdate(map[string]any{f.Column: deletedValue(f, now)})
+		} else {
+			sqlStr, args = query.builder.BuildDelete()
+		}
+		if err := query.builder.Err(); err != nil {
+			return &Result{Error: err}, err
+		}
 
 		start := time.Now()
 		res, err := query.executor.ExecContext(ctx, sqlStr, args...)
@@ -1304,6 +3097,9 @@ func (q *Query) Delete(value ...any) (int64, error) {
 		if err != nil {
 			return &Result{Error: err}, query.handleError(fmt.Errorf("failed to get rows affected: %w", err))
 		}
+		if soft && len(value) > 0 {
+			setDeletedAt(query.softDeleteField(m), value[0], now)
+		}
 
 		if len(value) > 0 && m != nil && m.HasAfterDelete {
 			if h, ok := value[0].(model.AfterDeleter); ok {
@@ -1317,9 +3113,29 @@ func (q *Query) Delete(value ...any) (int64, error) {
 		return &Result{RowsAffected: rows}, nil
 	}
 
-	res, err := q.executeWithMiddleware(final)
+	res, err := q.executeWithMiddleware(OpDelete, final)
 	if err != nil {
 		return 0, err
 	}
 	return res.RowsAffected, nil
 }
+
+// DeleteByIDs deletes the records of the query's model whose primary key is one of ids
+// in a single "DELETE ... WHERE pk IN (...)" statement, combined with any other WHERE
+// conditions. Soft-deletable models are soft-deleted as with Delete. Calling it without
+// ids is a no-op that returns 0.
+func (q *Query) DeleteByIDs(ids ...any) (int64, error) {
+	if err := q.err; err != nil {
+		q.release()
+		return 0, err
+	}
+	if q.model == nil || q.model.PKField == nil {
+		q.release()
+		return 0, fmt.Errorf("%w: DeleteByIDs requires a model with a primary key", ErrInvalidModel)
+	}
+	if len(ids) == 0 {
+		q.release()
+		return 0, nil
+	}
+	return q.WhereIn(q.db.dialect.Quote(q.model.PKField.Column), ids).Delete()
+}
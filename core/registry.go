@@ -0,0 +1,63 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+)
+
+var (
+	connMu      sync.RWMutex
+	connections = make(map[string]*DB)
+	defaultConn string
+)
+
+// Register makes db available under name to Use, so that code can fetch a named
+// connection, e.g. "primary" or "analytics", without the *DB being passed around.
+// Registering an existing name replaces its connection. The first connection registered
+// becomes the default one, see Default and SetDefault.
+func Register(name string, db *DB) {
+	connMu.Lock()
+	defer connMu.Unlock()
+	connections[name] = db
+	if defaultConn == "" {
+		defaultConn = name
+	}
+}
+
+// Unregister removes the connection registered under name. It does not close it. If it
+// was the default connection, there is no default until SetDefault is called.
+func Unregister(name string) {
+	connMu.Lock()
+	defer connMu.Unlock()
+	delete(connections, name)
+	if defaultConn == name {
+		defaultConn = ""
+	}
+}
+
+// Use returns the connection registered under name, or the default connection if name
+// is empty. It returns nil if no such connection is registered.
+func Use(name string) *DB {
+	connMu.RLock()
+	defer connMu.RUnlock()
+	if name == "" {
+		name = defaultConn
+	}
+	return connections[name]
+}
+
+// Default returns the default connection, or nil if none is registered.
+func Default() *DB {
+	return Use("")
+}
+
+// SetDefault makes the connection registered under name the default one.
+func SetDefault(name string) error {
+	connMu.Lock()
+	defer connMu.Unlock()
+	if _, ok := connections[name]; !ok {
+		return fmt.Errorf("connection %q is not registered", name)
+	}
+	defaultConn = name
+	return nil
+}
@@ -1,8 +1,11 @@
 package core
 
 import (
+	"reflect"
 	"testing"
 	"time"
+
+	"github.com/shrek82/jorm/model"
 )
 
 func TestTimeScanner(t *testing.T) {
@@ -72,3 +75,66 @@ func TestTimeScanner(t *testing.T) {
 		t.Error("Expected Valid=false for nil")
 	}
 }
+
+func TestTimeScannerLocation(t *testing.T) {
+	const naive = "2026-01-15 16:08:38"
+	tokyo := time.FixedZone("JST", 9*3600)
+
+	utcScanner := &TimeScanner{Location: time.UTC}
+	tokyoScanner := &TimeScanner{Location: tokyo}
+	if err := utcScanner.Scan(naive); err != nil {
+		t.Fatalf("Failed to scan in UTC: %v", err)
+	}
+	if err := tokyoScanner.Scan([]byte(naive)); err != nil {
+		t.Fatalf("Failed to scan in JST: %v", err)
+	}
+
+	if want := time.Date(2026, 1, 15, 16, 8, 38, 0, time.UTC); !utcScanner.Value.Equal(want) {
+		t.Errorf("Expected %v, got %v", want, utcScanner.Value)
+	}
+	if diff := utcScanner.Value.Sub(tokyoScanner.Value); diff != 9*time.Hour {
+		t.Errorf("Expected the JST instant to be 9h earlier, got %v", diff)
+	}
+	if tokyoScanner.Value.Location() != tokyo {
+		t.Errorf("Expected value in JST, got %v", tokyoScanner.Value.Location())
+	}
+
+	// Strings with an explicit offset keep it regardless of the location
+	if err := tokyoScanner.Scan("2026-01-15T16:08:38Z"); err != nil {
+		t.Fatalf("Failed to scan RFC3339: %v", err)
+	}
+	if !tokyoScanner.Value.Equal(utcScanner.Value) {
+		t.Errorf("Expected %v, got %v", utcScanner.Value, tokyoScanner.Value)
+	}
+}
+
+func TestSetFieldValueIncompatible(t *testing.T) {
+	type record struct {
+		Age int
+	}
+	m, err := model.GetModel(&record{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	field := m.FieldMap["age"]
+	plan := &scanPlan{fields: []*model.Field{field}, converters: make([]converter, 1)}
+
+	var r record
+	dest := reflect.ValueOf(&r).Elem()
+	if err := setFieldValue(dest, field, reflect.ValueOf(int64(7)), plan, 0); err != nil || r.Age != 7 {
+		t.Fatalf("Expected convertible value to be set, got %d (err %v)", r.Age, err)
+	}
+
+	plan.converters[0] = nil
+	err = setFieldValue(dest, field, reflect.ValueOf([]string{"x"}), plan, 0)
+	scanErr, ok := err.(*ScanError)
+	if !ok {
+		t.Fatalf("Expected *ScanError, got %v", err)
+	}
+	if scanErr.Column != "age" || scanErr.SrcType != "[]string" || scanErr.DstType != "int" {
+		t.Errorf("Unexpected ScanError: %+v", scanErr)
+	}
+	if r.Age != 7 {
+		t.Errorf("Incompatible value should leave the field unchanged, got %d", r.Age)
+	}
+}
@@ -0,0 +1,232 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/shrek82/jorm/model"
+)
+
+// softDeleteScope selects which rows of a soft-deletable model a query sees.
+type softDeleteScope int
+
+const (
+	scopeActive      softDeleteScope = iota // Only rows not marked as deleted (default)
+	scopeWithTrashed                        // All rows, deleted or not
+	scopeOnlyTrashed                        // Only rows marked as deleted
+)
+
+// Unscoped disables the soft delete filter so the query sees deleted rows as well, and
+// the model's default scope (see model.DefaultScoper). Delete on an unscoped query
+// removes rows permanently.
+func (q *Query) Unscoped() *Query {
+	q.scope = scopeWithTrashed
+	q.unscoped = true
+	return q
+}
+
+// WithTrashed disables the soft delete filter like Unscoped, but keeps the model's
+// default scope.
+func (q *Query) WithTrashed() *Query {
+	q.scope = scopeWithTrashed
+	return q
+}
+
+// OnlyTrashed restricts the query to soft-deleted rows (deleted_at IS NOT NULL).
+func (q *Query) OnlyTrashed() *Query {
+	q.scope = scopeOnlyTrashed
+	return q
+}
+
+// Restore clears deleted_at (or sets a boolean soft delete flag to false) on soft-deleted
+// rows so they show up in queries again. If a model instance is provided, its primary key
+// selects the row to restore and its soft delete field is reset; otherwise the current
+// WHERE conditions apply.
+// It returns the number of rows restored.
+func (q *Query) Restore(value ...any) (int64, error) {
+	defer q.release()
+	if q.err != nil {
+		return 0, q.err
+	}
+	q.OnlyTrashed()
+
+	final := func(ctx context.Context, query *Query) (*Result, error) {
+		m := query.model
+		if len(value) > 0 {
+			var err error
+			m, err = model.GetModel(value[0])
+			if err != nil {
+				return &Result{Error: err}, fmt.Errorf("failed to get model: %w", err)
+			}
+			if m.PKField != nil {
+				v := reflect.Indirect(reflect.ValueOf(value[0]))
+				query.builder.Where(query.db.dialect.Quote(m.PKField.Column)+" = ?", m.PKField.Accessor(v).Interface())
+			}
+		}
+		if m == nil {
+			err := fmt.Errorf("model metadata is required for restore")
+			return &Result{Error: err}, err
+		}
+		f := query.softDeleteField(m)
+		if f == nil {
+			err := fmt.Errorf("%w: %s has no soft delete field", ErrInvalidModel, m.TableName)
+			return &Result{Error: err}, err
+		}
+
+		query.applyScopes(m)
+		query.builder.SetTable(m.TableName)
+		if err := query.builder.Err(); err != nil {
+			return &Result{Error: err}, err
+		}
+		sqlStr, args := query.builder.BuildUpdate(map[string]any{f.Column: restoredValue(f)})
+		if err := query.builder.Err(); err != nil {
+			return &Result{Error: err}, err
+		}
+
+		start := time.Now()
+		res, err := query.executor.ExecContext(ctx, sqlStr, args...)
+		query.logSQL(sqlStr, time.Since(start), args...)
+		if err != nil {
+			return &Result{Error: err}, query.handleError(fmt.Errorf("Restore execution failed: %w", err))
+		}
+
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return &Result{Error: err}, query.handleError(fmt.Errorf("failed to get rows affected: %w", err))
+		}
+		if len(value) > 0 {
+			resetDeletedAt(f, value[0])
+		}
+
+		query.handleError(nil)
+		return &Result{RowsAffected: rows}, nil
+	}
+
+	res, err := q.executeWithMiddleware(OpUpdate, final)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected, nil
+}
+
+// applyScopes adds the model's default scope and the soft delete condition for the current
+// scope to the builder. It runs once per query and does nothing for raw SQL.
+func (q *Query) applyScopes(m *model.Model) {
+	if q.scopesApplied || q.rawSQL != "" || m == nil {
+		return
+	}
+	q.scopesApplied = true
+
+	type condition struct {
+		sql  string
+		args []any
+	}
+	var conds []condition
+	if m.DefaultScope != "" && !q.unscoped {
+		conds = append(conds, condition{sql: m.DefaultScope})
+	}
+	if f := q.softDeleteField(m); f != nil && q.scope != scopeWithTrashed {
+		column := q.db.dialect.Quote(f.Column)
+		switch {
+		case isDeletedFlag(f):
+			conds = append(conds, condition{column + " = ?", []any{q.scope == scopeOnlyTrashed}})
+		case q.scope == scopeOnlyTrashed:
+			conds = append(conds, condition{sql: column + " IS NOT NULL"})
+		default:
+			conds = append(conds, condition{sql: column + " IS NULL"})
+		}
+	}
+	if len(conds) == 0 {
+		return
+	}
+	// Group the existing conditions so that an OrWhere cannot bypass the filter
+	q.groupWhere()
+	for _, cond := range conds {
+		q.builder.Where(cond.sql, cond.args...)
+	}
+}
+
+// softDeletes reports whether Delete should mark rows of m as deleted instead of removing them.
+func (q *Query) softDeletes(m *model.Model) bool {
+	return q.softDeleteField(m) != nil && q.scope == scopeActive
+}
+
+// softDeleteField returns the field marking rows of m as deleted: the column set with
+// Options.SoftDeleteColumn if m has one of a *time.Time or bool type, else the DeletedAt field.
+// It returns nil if m is not soft-deletable.
+func (q *Query) softDeleteField(m *model.Model) *model.Field {
+	if m == nil {
+		return nil
+	}
+	if col := q.db.softDeleteColumn; col != "" {
+		if f, ok := m.FieldMap[col]; ok && (isDeletedFlag(f) || f.Type == timePtrType) {
+			return f
+		}
+	}
+	return m.SoftDeleteField
+}
+
+// isDeletedFlag reports whether the soft delete field f is a boolean flag rather than a time.
+func isDeletedFlag(f *model.Field) bool {
+	return baseType(f.Type).Kind() == reflect.Bool
+}
+
+// baseType returns the type t points to, or t itself if it is not a pointer.
+func baseType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
+
+// deletedValue returns the value Delete writes to the soft delete field f.
+func deletedValue(f *model.Field, now time.Time) any {
+	if isDeletedFlag(f) {
+		return true
+	}
+	return now
+}
+
+// restoredValue returns the value Restore writes to the soft delete field f.
+func restoredValue(f *model.Field) any {
+	if isDeletedFlag(f) {
+		return false
+	}
+	return nil
+}
+
+// setDeletedAt marks value as deleted at now in its soft delete field f, if it is addressable.
+func setDeletedAt(f *model.Field, value any, now time.Time) {
+	setSoftDeleteField(f, value, deletedValue(f, now))
+}
+
+// resetDeletedAt clears the soft delete field f of value, if it is addressable.
+func resetDeletedAt(f *model.Field, value any) {
+	setSoftDeleteField(f, value, restoredValue(f))
+}
+
+// setSoftDeleteField stores v in the soft delete field f of value, if it is addressable.
+// A nil v zeroes the field; pointer fields receive a pointer to a copy of v.
+func setSoftDeleteField(f *model.Field, value any, v any) {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return
+	}
+	fv := f.Accessor(rv.Elem())
+	if !fv.IsValid() || !fv.CanSet() {
+		return
+	}
+	if v == nil {
+		fv.Set(reflect.Zero(fv.Type()))
+		return
+	}
+	if fv.Kind() == reflect.Ptr {
+		p := reflect.New(fv.Type().Elem())
+		p.Elem().Set(reflect.ValueOf(v))
+		fv.Set(p)
+	} else {
+		fv.Set(reflect.ValueOf(v))
+	}
+}
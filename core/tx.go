@@ -4,13 +4,15 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 )
 
 // Tx represents a database transaction.
 // It implements the Executor interface and provides methods to create queries within the transaction.
 type Tx struct {
-	db    *DB
-	sqlTx *sql.Tx
+	db         *DB
+	sqlTx      *sql.Tx
+	savepoints int // Number of savepoints created by Transaction, used to name them
 }
 
 // Model starts a new query builder for the given model instance within the transaction.
@@ -39,6 +41,82 @@ func (tx *Tx) Rollback() error {
 	return nil
 }
 
+// Savepoint creates a savepoint with the given name inside the transaction.
+// Name must be a plain identifier (letters, digits and underscores).
+func (tx *Tx) Savepoint(name string) error {
+	return tx.execSavepoint(name, func(name string) string { return savepointSQL(tx.db.dialect, name) })
+}
+
+// RollbackTo undoes the changes made since the savepoint name was created, keeping the
+// transaction and the savepoint itself open.
+func (tx *Tx) RollbackTo(name string) error {
+	return tx.execSavepoint(name, func(name string) string { return rollbackToSQL(tx.db.dialect, name) })
+}
+
+// ReleaseSavepoint removes the savepoint name, keeping the changes made since it was
+// created. It is a no-op on databases that release savepoints only at commit.
+func (tx *Tx) ReleaseSavepoint(name string) error {
+	return tx.execSavepoint(name, func(name string) string { return releaseSavepointSQL(tx.db.dialect, name) })
+}
+
+// Transaction runs fn as a nested transaction inside tx using a savepoint instead of a
+// new BEGIN. If fn returns an error or panics, only its changes are rolled back and the
+// outer transaction can continue; otherwise the savepoint is released.
+func (tx *Tx) Transaction(fn func(tx *Tx) error) (err error) {
+	tx.savepoints++
+	name := fmt.Sprintf("jorm_sp_%d", tx.savepoints)
+	if err := tx.Savepoint(name); err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.RollbackTo(name)
+			panic(p)
+		} else if err != nil {
+			if rbErr := tx.RollbackTo(name); rbErr != nil {
+				err = fmt.Errorf("%w (rollback to savepoint failed: %v)", err, rbErr)
+			}
+		} else {
+			err = tx.ReleaseSavepoint(name)
+		}
+	}()
+
+	return fn(tx)
+}
+
+// execSavepoint validates name and executes the savepoint statement built by sqlFor.
+func (tx *Tx) execSavepoint(name string, sqlFor func(string) string) error {
+	if !isIdentifier(name) {
+		return fmt.Errorf("%w: invalid savepoint name %q", ErrInvalidSQL, name)
+	}
+	query := sqlFor(name)
+	if query == "" {
+		return nil
+	}
+	start := time.Now()
+	_, err := tx.sqlTx.Exec(query)
+	tx.db.logSQL(query, time.Since(start))
+	if err != nil {
+		return fmt.Errorf("%s failed: %w", query, err)
+	}
+	return nil
+}
+
+// isIdentifier reports whether s is a non-empty run of letters, digits and underscores
+// that does not start with a digit.
+func isIdentifier(s string) bool {
+	if s == "" || !isNameStart(s[0]) {
+		return false
+	}
+	for i := 1; i < len(s); i++ {
+		if !isNameStart(s[i]) && (s[i] < '0' || s[i] > '9') {
+			return false
+		}
+	}
+	return true
+}
+
 // QueryContext executes a query that returns rows, typically a SELECT.
 func (tx *Tx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
 	rows, err := tx.sqlTx.QueryContext(ctx, query, args...)
@@ -0,0 +1,83 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/shrek82/jorm/model"
+	"github.com/shrek82/jorm/validator"
+)
+
+// uniqueRule checks that no other row of table holds the field's value in column.
+type uniqueRule struct {
+	validator.BaseRule
+	db     *DB
+	table  string
+	column string
+	scope  []string
+}
+
+// UniqueIn returns a rule checking that the field's value is not yet used in column of
+// table. When validating a struct whose model has a non-zero primary key, that row is
+// excluded, so updating a record does not conflict with itself.
+func (db *DB) UniqueIn(table, column string) validator.Rule {
+	return &uniqueRule{db: db, table: table, column: column}
+}
+
+// UniqueInScope is like UniqueIn, but the value only has to be unique among rows that
+// share the validated struct's values of the scope columns, e.g.
+//
+//	"Email": {db.UniqueInScope("users", "email", "tenant_id")}
+//
+// allows the same email under different tenants. The scope values are read from the
+// fields mapped to those columns, so the rule must be validated as part of Rules.
+func (db *DB) UniqueInScope(table, column string, scope ...string) validator.Rule {
+	return &uniqueRule{db: db, table: table, column: column, scope: scope}
+}
+
+func (r *uniqueRule) Validate(v any) error {
+	return r.ValidateField(v, reflect.Value{})
+}
+
+func (r *uniqueRule) ValidateField(v any, parent reflect.Value) error {
+	if !r.ShouldValidate(v) {
+		return nil
+	}
+	d := r.db.dialect
+	q := r.db.Table(r.table).Where(d.Quote(r.column)+" = ?", v)
+
+	var m *model.Model
+	if parent.IsValid() {
+		var err error
+		if m, err = model.GetModel(parent.Interface()); err != nil {
+			return err
+		}
+	} else if len(r.scope) > 0 {
+		return fmt.Errorf("%w: UniqueInScope must be validated as part of a struct", ErrInvalidQuery)
+	}
+	for _, col := range r.scope {
+		f, ok := m.FieldMap[col]
+		if !ok {
+			return fmt.Errorf("%w: unknown scope column %s", ErrInvalidModel, col)
+		}
+		q = q.Where(d.Quote(col)+" = ?", f.Accessor(parent).Interface())
+	}
+	if m != nil && m.PKField != nil {
+		if pk := m.PKField.Accessor(parent); pk.IsValid() && !pk.IsZero() {
+			q = q.Where(d.Quote(m.PKField.Column)+" <> ?", pk.Interface())
+		}
+	}
+
+	count, err := q.Count()
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return r.FormatError(fmt.Errorf("already exists"))
+	}
+	return nil
+}
+
+func (r *uniqueRule) Msg(msg string) validator.Rule         { nr := *r; nr.SetMsg(msg); return &nr }
+func (r *uniqueRule) Optional() validator.Rule              { nr := *r; nr.SetOptional(); return &nr }
+func (r *uniqueRule) When(fn func(any) bool) validator.Rule { nr := *r; nr.SetWhen(fn); return &nr }
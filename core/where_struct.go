@@ -0,0 +1,130 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/shrek82/jorm/model"
+)
+
+// WhereStructTag is the struct tag setting the operator WhereStruct compares a field with.
+const WhereStructTag = "jormq"
+
+// whereStructOperators maps the operators of the jormq tag to their SQL comparison.
+var whereStructOperators = map[string]string{
+	"eq": "=", "ne": "<>", "gt": ">", "gte": ">=", "lt": "<", "lte": "<=",
+	"like": "LIKE", "in": "IN", "between": "BETWEEN",
+}
+
+// WhereStruct adds an AND condition for each non-zero field of value, a struct or pointer
+// to one, so search forms can be turned into filters declaratively. Columns follow the
+// jorm tags as for models and fields compare with "=" unless their jormq tag sets an
+// operator:
+//
+//	type UserSearch struct {
+//		Name   string    `jormq:"op:like"`                            // name LIKE ?
+//		MinAge int       `jorm:"column:age" jormq:"op:gte"`           // age >= ?
+//		Status []int     `jormq:"op:in"`                              // status IN (?, ?)
+//		Born   [2]string `jorm:"column:birth_date" jormq:"op:between"` // birth_date BETWEEN ? AND ?
+//	}
+//
+// The operators are eq, ne, gt, gte, lt, lte, like, in and between. A like value is used
+// as the pattern as is, see EscapeLike; in takes a slice and between a slice or array of
+// exactly two values. Nil pointers and empty slices are skipped like zero values, while a
+// pointer to a zero value filters on it. An invalid tag or value sets ErrInvalidQuery.
+func (q *Query) WhereStruct(value any) *Query {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		q.err = fmt.Errorf("%w: WhereStruct requires a struct, got %T", ErrInvalidQuery, value)
+		return q
+	}
+	if err := q.whereStruct(v); err != nil {
+		q.err = err
+	}
+	return q
+}
+
+// whereStruct adds the conditions of the fields of v, descending into embedded structs.
+func (q *Query) whereStruct(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		jormTag := sf.Tag.Get("jorm")
+		if jormTag == "-" {
+			continue
+		}
+		fv := v.Field(i)
+		if sf.Anonymous && fv.Kind() == reflect.Ptr && fv.Type().Elem().Kind() == reflect.Struct {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		}
+		if sf.Anonymous && fv.Kind() == reflect.Struct {
+			if err := q.whereStruct(fv); err != nil {
+				return err
+			}
+			continue
+		}
+		if fv.IsZero() || ((fv.Kind() == reflect.Slice || fv.Kind() == reflect.Map) && fv.Len() == 0) {
+			continue
+		}
+
+		op, err := whereStructOperator(sf)
+		if err != nil {
+			return err
+		}
+		column := model.ParseTag(jormTag).Column
+		if column == "" {
+			column = model.ColumnName(sf.Name)
+		}
+		if err := q.whereStructField(q.db.dialect.Quote(column), op, reflect.Indirect(fv), sf.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// whereStructOperator returns the operator set by the jormq tag of sf, "eq" by default.
+func whereStructOperator(sf reflect.StructField) (string, error) {
+	op := "eq"
+	for _, part := range strings.Fields(strings.ReplaceAll(sf.Tag.Get(WhereStructTag), ";", " ")) {
+		key, val, ok := strings.Cut(part, ":")
+		if !ok || key != "op" {
+			return "", fmt.Errorf("%w: WhereStruct field %s has an invalid %s tag %q", ErrInvalidQuery, sf.Name, WhereStructTag, part)
+		}
+		op = strings.ToLower(val)
+	}
+	if _, ok := whereStructOperators[op]; !ok {
+		return "", fmt.Errorf("%w: WhereStruct field %s has an unknown operator %q", ErrInvalidQuery, sf.Name, op)
+	}
+	return op, nil
+}
+
+// whereStructField adds the condition comparing column with the field value v using op.
+func (q *Query) whereStructField(column, op string, v reflect.Value, name string) error {
+	switch op {
+	case "like":
+		q.builder.Where(likeSQL(q.db.dialect, column, false), v.Interface())
+	case "in":
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return fmt.Errorf("%w: WhereStruct field %s must be a slice for in", ErrInvalidQuery, name)
+		}
+		q.builder.WhereIn(column, v.Interface())
+	case "between":
+		if (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) || v.Len() != 2 {
+			return fmt.Errorf("%w: WhereStruct field %s must hold two values for between", ErrInvalidQuery, name)
+		}
+		q.builder.Where(column+" BETWEEN ? AND ?", v.Index(0).Interface(), v.Index(1).Interface())
+	default:
+		q.builder.Where(column+" "+whereStructOperators[op]+" ?", v.Interface())
+	}
+	return nil
+}
@@ -2,7 +2,9 @@ package dialect
 
 import (
 	"database/sql"
+	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/shrek82/jorm/model"
 )
@@ -40,8 +42,267 @@ type Dialect interface {
 	CreateIndexSQL(tableName string, indexName string, columns []string, unique bool) (string, []any)
 }
 
+// The interfaces below are optional: a dialect implementing only Dialect still works,
+// and jorm falls back to standard SQL or reports the feature as unsupported for the
+// methods it lacks. The built-in dialects implement those their database supports.
+
+// PartialIndexSQLer is implemented by dialects supporting partial indexes.
+type PartialIndexSQLer interface {
+	// CreatePartialIndexSQL generates the SQL to create an index restricted to the rows
+	// matching where, or a plain index if where is empty
+	CreatePartialIndexSQL(tableName string, indexName string, columns []string, unique bool, where string) (string, []any)
+}
+
+// GroupConcatSQLer is implemented by dialects with a string aggregate, see Query.GroupConcat.
+type GroupConcatSQLer interface {
+	// GroupConcatSQL returns the aggregate expression concatenating column values with separator
+	GroupConcatSQL(column string, separator string) string
+}
+
+// TupleInSQLer is implemented by dialects with their own form of multi-column IN. Without
+// it, WhereInTuple uses "(a = ? AND b = ?) OR (a = ? AND b = ?)".
+type TupleInSQLer interface {
+	// TupleInSQL returns a condition matching columns against rowCount tuples of "?"
+	// placeholders, e.g. "(a, b) IN ((?, ?), (?, ?))"
+	TupleInSQL(columns []string, rowCount int) string
+}
+
+// JSONExtractSQLer is implemented by dialects able to read JSON columns, see Query.WhereJSON.
+type JSONExtractSQLer interface {
+	// JSONExtractSQL returns an expression reading the value at a dot-separated path
+	// (e.g. "address.city") from a JSON column
+	JSONExtractSQL(column, path string) string
+}
+
+// DateSQLer is implemented by dialects able to compare dates, see Query.WhereDate.
+type DateSQLer interface {
+	// DateSQL returns an expression reducing a datetime column to its date part, comparable
+	// with a "YYYY-MM-DD" string
+	DateSQL(column string) string
+}
+
+// LikeSQLer is implemented by dialects with their own LIKE syntax. Without it, WhereLike
+// declares the backslash escape with ESCAPE and lowers both sides for WhereILike.
+type LikeSQLer interface {
+	// LikeSQL returns a condition matching column against a "?" LIKE pattern in which
+	// a backslash escapes %, _ and itself, case-insensitively if insensitive is set
+	LikeSQL(column string, insensitive bool) string
+}
+
+// OrderByNullsSQLer is implemented by dialects with their own NULL ordering. Without it,
+// OrderByNulls sorts on a CASE expression first.
+type OrderByNullsSQLer interface {
+	// OrderByNullsSQL returns the ORDER BY terms sorting column in direction ("ASC" or
+	// "DESC") with NULLs placed first or last
+	OrderByNullsSQL(column, direction string, nullsFirst bool) string
+}
+
+// CallProcSQLer is implemented by dialects of databases with stored procedures, see DB.CallProc.
+type CallProcSQLer interface {
+	// CallProcSQL returns the statement calling the stored procedure name, already quoted,
+	// with argCount arguments, or an empty string if the database has no stored procedures
+	CallProcSQL(name string, argCount int) string
+}
+
+// LimitOffsetSQLer is implemented by dialects paging without "LIMIT ? OFFSET ?".
+type LimitOffsetSQLer interface {
+	// LimitOffsetSQL returns the paging clause for a SELECT, e.g. "LIMIT ? OFFSET ?", and its
+	// arguments. A negative limit or offset means it is not set; hasOrderBy reports whether
+	// the statement already has an ORDER BY clause
+	LimitOffsetSQL(limit, offset int, hasOrderBy bool) (string, []any)
+}
+
+// SavepointSQLer is implemented by dialects whose savepoint statements differ from the
+// standard SAVEPOINT, ROLLBACK TO SAVEPOINT and RELEASE SAVEPOINT.
+type SavepointSQLer interface {
+	// SavepointSQL returns the statement creating a savepoint inside a transaction
+	SavepointSQL(name string) string
+	// RollbackToSQL returns the statement rolling back to a savepoint
+	RollbackToSQL(name string) string
+	// ReleaseSavepointSQL returns the statement releasing a savepoint, or an empty string
+	// if the database releases savepoints only when the transaction ends
+	ReleaseSavepointSQL(name string) string
+}
+
+// UpsertSQLer is implemented by dialects of databases with an insert-or-update clause,
+// see Query.BatchUpsert.
+type UpsertSQLer interface {
+	// UpsertSQL returns the clause appended to an INSERT so that rows conflicting on
+	// conflictColumns update updateColumns instead, or an empty string if the database
+	// has no such clause
+	UpsertSQL(conflictColumns, updateColumns []string) string
+}
+
+// BatchInsertIDer is implemented by dialects of databases reporting usable IDs for
+// multi-row inserts, see Query.BatchInsertResult.
+type BatchInsertIDer interface {
+	// BatchFirstInsertID derives the ID of the first row of a multi-row INSERT of rowCount
+	// rows from the driver's LastInsertId, or returns false if the database does not
+	// report one that can be relied on for contiguous IDs
+	BatchFirstInsertID(lastInsertID, rowCount int64) (int64, bool)
+}
+
+// ColumnCommentSQLer is implemented by dialects setting column comments in a separate
+// statement, see the comment tag.
+type ColumnCommentSQLer interface {
+	// ColumnCommentSQL returns the statement setting the comment of the field's column
+	// once the column exists, or an empty string if the field has no comment, the
+	// comment is declared inline with the column or the database has no column comments
+	ColumnCommentSQL(tableName string, field *model.Field) string
+}
+
+// PlaceholderLimiter is implemented by dialects that know how many parameters the
+// database binds in one statement. Without it, statements are not checked.
+type PlaceholderLimiter interface {
+	// MaxPlaceholders returns the maximum number of bound parameters in one statement
+	MaxPlaceholders() int
+}
+
+// CapabilitiesReporter is implemented by dialects reporting their optional features.
+// Without it, DB.Capabilities reports none.
+type CapabilitiesReporter interface {
+	// Capabilities reports the optional features the database supports
+	Capabilities() DialectCapabilities
+}
+
+// DialectCapabilities lists optional database features, so that code supporting several
+// databases can check what is available instead of comparing dialect names.
+type DialectCapabilities struct {
+	Returning         bool // INSERT/UPDATE/DELETE ... RETURNING
+	Upsert            bool // UpsertSQL returns a clause, see Query.BatchUpsert
+	PartialIndexes    bool // CREATE INDEX ... WHERE, see the where tag
+	WindowFunctions   bool // ROW_NUMBER() OVER (...) and other window functions
+	RowValues         bool // (a, b) IN ((?, ?), ...) comparisons
+	ReleaseSavepoints bool // RELEASE SAVEPOINT
+	BatchInsertIDs    bool // BatchFirstInsertID derives the IDs of a multi-row INSERT
+	ColumnComments    bool // comment tags are stored in the database
+}
+
 var dialects = make(map[string]Dialect)
 
+// quoteString returns s as a single-quoted SQL string literal.
+func quoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// enumCheck returns an inline CHECK constraint restricting a column to the field's
+// enum values, or an empty string if the field has none.
+func enumCheck(quotedColumn string, field *model.Field) string {
+	if len(field.Enum) == 0 {
+		return ""
+	}
+	values := make([]string, len(field.Enum))
+	for i, v := range field.Enum {
+		values[i] = quoteString(v)
+	}
+	return fmt.Sprintf(" CHECK (%s IN (%s))", quotedColumn, strings.Join(values, ", "))
+}
+
+// defaultFunc returns a DEFAULT clause for fields whose default is a database
+// expression such as CURRENT_TIMESTAMP, or an empty string otherwise.
+func defaultFunc(field *model.Field) string {
+	if !field.DefaultFunc {
+		return ""
+	}
+	return " DEFAULT " + field.Default
+}
+
+// generatedAs returns the clause declaring a generated column computed from the field's
+// expression, followed by storage ("STORED", "VIRTUAL" or empty for the database's
+// default), or an empty string if the field has no expression.
+func generatedAs(field *model.Field, storage string) string {
+	if field.GeneratedAs == "" {
+		return ""
+	}
+	expr := field.GeneratedAs
+	if !strings.HasPrefix(expr, "(") {
+		expr = "(" + expr + ")"
+	}
+	clause := " GENERATED ALWAYS AS " + expr
+	if storage != "" {
+		clause += " " + storage
+	}
+	return clause
+}
+
+// addColumnModifiers returns the DEFAULT and NOT NULL clauses for adding a column to a
+// table that may already hold rows. Existing rows take the default, so NOT NULL is only
+// emitted together with one; a NOT NULL field without a default is added nullable.
+// Function defaults are skipped when funcDefaults is false.
+func addColumnModifiers(field *model.Field, funcDefaults bool) string {
+	if field.Default == "" || (field.DefaultFunc && !funcDefaults) {
+		return ""
+	}
+	modifiers := " DEFAULT " + field.Default
+	if field.NotNull {
+		modifiers += " NOT NULL"
+	}
+	return modifiers
+}
+
+// tupleIn builds the row constructor form "(a, b) IN ((?, ?), (?, ?))".
+func tupleIn(columns []string, rowCount int) string {
+	tuple := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ") + ")"
+	tuples := make([]string, rowCount)
+	for i := range tuples {
+		tuples[i] = tuple
+	}
+	return fmt.Sprintf("(%s) IN (%s)", strings.Join(columns, ", "), strings.Join(tuples, ", "))
+}
+
+// tupleOr builds "(a = ? AND b = ?) OR (a = ? AND b = ?)" for databases without row constructors.
+func tupleOr(columns []string, rowCount int) string {
+	conds := make([]string, len(columns))
+	for i, col := range columns {
+		conds[i] = col + " = ?"
+	}
+	tuple := "(" + strings.Join(conds, " AND ") + ")"
+	tuples := make([]string, rowCount)
+	for i := range tuples {
+		tuples[i] = tuple
+	}
+	return strings.Join(tuples, " OR ")
+}
+
+// jsonPath converts a dot-separated path such as "address.city" to "$.address.city".
+// Paths that already start with "$" are kept as is.
+func jsonPath(path string) string {
+	if strings.HasPrefix(path, "$") {
+		return path
+	}
+	return "$." + path
+}
+
+// limitOffset returns a "LIMIT ? OFFSET ?" paging clause; unset (negative) parts are left out.
+func limitOffset(limit, offset int) (string, []any) {
+	var clauses []string
+	var args []any
+	if limit >= 0 {
+		clauses = append(clauses, "LIMIT ?")
+		args = append(args, limit)
+	}
+	if offset >= 0 {
+		clauses = append(clauses, "OFFSET ?")
+		args = append(args, offset)
+	}
+	return strings.Join(clauses, " "), args
+}
+
+// offsetFetch returns an "OFFSET ? ROWS FETCH NEXT ? ROWS ONLY" paging clause. OFFSET is
+// always emitted when a limit is set since FETCH cannot appear without it.
+func offsetFetch(limit, offset int) (string, []any) {
+	if limit < 0 && offset < 0 {
+		return "", nil
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if limit < 0 {
+		return "OFFSET ? ROWS", []any{offset}
+	}
+	return "OFFSET ? ROWS FETCH NEXT ? ROWS ONLY", []any{offset, limit}
+}
+
 // Register registers a new dialect for a given driver name
 func Register(name string, d Dialect) {
 	dialects[name] = d
@@ -70,3 +331,65 @@ func init() {
 	// Register Oracle dialect
 	Register("oracle", &oracle{})
 }
+
+// likeEscape builds a LIKE condition for databases without a default escape character,
+// declaring the backslash as one, and lowering both sides for case-insensitive matching.
+func likeEscape(column string, insensitive bool) string {
+	if insensitive {
+		return "LOWER(" + column + ") LIKE LOWER(?) ESCAPE '\\'"
+	}
+	return column + " LIKE ? ESCAPE '\\'"
+}
+
+// nullsOrder returns the standard "column ASC NULLS LAST" ordering.
+func nullsOrder(column, direction string, nullsFirst bool) string {
+	if nullsFirst {
+		return column + " " + direction + " NULLS FIRST"
+	}
+	return column + " " + direction + " NULLS LAST"
+}
+
+// nullsCase emulates NULLS FIRST/LAST for databases without it by sorting on whether
+// column is NULL before sorting on column itself.
+func nullsCase(column, direction string, nullsFirst bool) string {
+	if nullsFirst {
+		return "CASE WHEN " + column + " IS NULL THEN 0 ELSE 1 END, " + column + " " + direction
+	}
+	return "CASE WHEN " + column + " IS NULL THEN 1 ELSE 0 END, " + column + " " + direction
+}
+
+// procArgs returns the placeholders of argCount procedure arguments separated by commas.
+func procArgs(d Dialect, argCount int) string {
+	placeholders := make([]string, argCount)
+	for i := range placeholders {
+		placeholders[i] = d.Placeholder(i + 1)
+	}
+	return strings.Join(placeholders, ", ")
+}
+
+// commentOn returns the standard "COMMENT ON COLUMN table.column IS '...'" statement, or
+// an empty string if the field has no comment.
+func commentOn(d Dialect, tableName string, field *model.Field) string {
+	if field.Comment == "" {
+		return ""
+	}
+	return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s", d.Quote(tableName), d.Quote(field.Column), quoteString(field.Comment))
+}
+
+// onConflict returns the ON CONFLICT clause of PostgreSQL and SQLite, which refer to the
+// rejected row as "excluded".
+func onConflict(d Dialect, conflictColumns, updateColumns []string) string {
+	quoted := make([]string, len(conflictColumns))
+	for i, c := range conflictColumns {
+		quoted[i] = d.Quote(c)
+	}
+	clause := "ON CONFLICT (" + strings.Join(quoted, ", ") + ") DO "
+	if len(updateColumns) == 0 {
+		return clause + "NOTHING"
+	}
+	sets := make([]string, len(updateColumns))
+	for i, c := range updateColumns {
+		sets[i] = d.Quote(c) + " = excluded." + d.Quote(c)
+	}
+	return clause + "UPDATE SET " + strings.Join(sets, ", ")
+}
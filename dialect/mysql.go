@@ -68,6 +68,7 @@ func (d *mysql) CreateTableSQL(m *model.Model) (string, []any) {
 			}
 		}
 		column := fmt.Sprintf("%s %s", d.Quote(field.Column), sqlType)
+		column += generatedAs(field, "")
 		if field.NotNull {
 			column += " NOT NULL"
 		}
@@ -80,6 +81,8 @@ func (d *mysql) CreateTableSQL(m *model.Model) (string, []any) {
 		if field.IsAuto {
 			column += " AUTO_INCREMENT"
 		}
+		column += inlineComment(field)
+		column += enumCheck(d.Quote(field.Column), field)
 		columns = append(columns, column)
 	}
 	sql := fmt.Sprintf("CREATE TABLE %s (%s)", d.Quote(m.TableName), strings.Join(columns, ", "))
@@ -124,13 +127,14 @@ func (d *mysql) AddColumnSQL(tableName string, field *model.Field) (string, []an
 			sqlType = fmt.Sprintf("varchar(%d)", field.Size)
 		}
 	}
-	modifiers := ""
+	modifiers := generatedAs(field, "")
 	if field.NotNull {
 		modifiers += " NOT NULL"
 	}
 	if field.Default != "" {
 		modifiers += " DEFAULT " + field.Default
 	}
+	modifiers += inlineComment(field)
 	sql := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s%s",
 		d.Quote(tableName),
 		d.Quote(field.Column),
@@ -155,6 +159,7 @@ func (d *mysql) ModifyColumnSQL(tableName string, field *model.Field) (string, [
 	if field.Default != "" {
 		modifiers += " DEFAULT " + field.Default
 	}
+	modifiers += inlineComment(field)
 	sql := fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s %s%s",
 		d.Quote(tableName),
 		d.Quote(field.Column),
@@ -214,3 +219,105 @@ func (d *mysql) CreateIndexSQL(tableName string, indexName string, columns []str
 	)
 	return sql, nil
 }
+
+func (d *mysql) GroupConcatSQL(column string, separator string) string {
+	return fmt.Sprintf("GROUP_CONCAT(%s SEPARATOR %s)", column, quoteString(separator))
+}
+
+func (d *mysql) TupleInSQL(columns []string, rowCount int) string {
+	return tupleIn(columns, rowCount)
+}
+
+func (d *mysql) JSONExtractSQL(column, path string) string {
+	return fmt.Sprintf("JSON_EXTRACT(%s, %s)", column, quoteString(jsonPath(path)))
+}
+
+// LikeSQL relies on the backslash being MySQL's default LIKE escape character.
+func (d *mysql) LikeSQL(column string, insensitive bool) string {
+	if insensitive {
+		return "LOWER(" + column + ") LIKE LOWER(?)"
+	}
+	return column + " LIKE ?"
+}
+
+func (d *mysql) OrderByNullsSQL(column, direction string, nullsFirst bool) string {
+	return nullsCase(column, direction, nullsFirst)
+}
+
+func (d *mysql) CallProcSQL(name string, argCount int) string {
+	return "CALL " + name + "(" + procArgs(d, argCount) + ")"
+}
+
+func (d *mysql) DateSQL(column string) string {
+	return fmt.Sprintf("DATE(%s)", column)
+}
+
+func (d *mysql) LimitOffsetSQL(limit, offset int, hasOrderBy bool) (string, []any) {
+	return limitOffset(limit, offset)
+}
+
+func (d *mysql) SavepointSQL(name string) string {
+	return "SAVEPOINT " + name
+}
+
+func (d *mysql) RollbackToSQL(name string) string {
+	return "ROLLBACK TO SAVEPOINT " + name
+}
+
+func (d *mysql) ReleaseSavepointSQL(name string) string {
+	return "RELEASE SAVEPOINT " + name
+}
+
+// UpsertSQL returns an ON DUPLICATE KEY UPDATE clause. MySQL detects the conflict on any
+// unique key, so conflictColumns only serve as a no-op assignment when nothing is updated.
+func (d *mysql) UpsertSQL(conflictColumns, updateColumns []string) string {
+	if len(updateColumns) == 0 {
+		if len(conflictColumns) == 0 {
+			return ""
+		}
+		c := d.Quote(conflictColumns[0])
+		return "ON DUPLICATE KEY UPDATE " + c + " = " + c
+	}
+	sets := make([]string, len(updateColumns))
+	for i, c := range updateColumns {
+		sets[i] = d.Quote(c) + " = VALUES(" + d.Quote(c) + ")"
+	}
+	return "ON DUPLICATE KEY UPDATE " + strings.Join(sets, ", ")
+}
+
+// BatchFirstInsertID relies on MySQL reporting the first ID of a multi-row INSERT, which
+// is allocated in one block for the statement.
+func (d *mysql) BatchFirstInsertID(lastInsertID, rowCount int64) (int64, bool) {
+	return lastInsertID, true
+}
+
+// ColumnCommentSQL returns an empty string as MySQL declares comments inline, see inlineComment.
+func (d *mysql) ColumnCommentSQL(tableName string, field *model.Field) string {
+	return ""
+}
+
+// MaxPlaceholders returns the limit of the prepared statement protocol, whose parameter
+// count is a 16-bit integer.
+func (d *mysql) MaxPlaceholders() int {
+	return 65535
+}
+
+// Capabilities describes MySQL 8.0; RETURNING is MariaDB only.
+func (d *mysql) Capabilities() DialectCapabilities {
+	return DialectCapabilities{
+		Upsert:            true,
+		WindowFunctions:   true,
+		RowValues:         true,
+		ReleaseSavepoints: true,
+		BatchInsertIDs:    true,
+		ColumnComments:    true,
+	}
+}
+
+// inlineComment returns the COMMENT clause of a MySQL column definition.
+func inlineComment(field *model.Field) string {
+	if field.Comment == "" {
+		return ""
+	}
+	return " COMMENT " + quoteString(field.Comment)
+}
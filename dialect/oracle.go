@@ -65,6 +65,9 @@ func (d *oracle) CreateTableSQL(m *model.Model) (string, []any) {
 		if field.IsAuto {
 			column += " GENERATED BY DEFAULT AS IDENTITY"
 		}
+		column += generatedAs(field, "VIRTUAL")
+		column += defaultFunc(field)
+		column += enumCheck(d.Quote(field.Column), field)
 		columns = append(columns, column)
 	}
 	sql := fmt.Sprintf("CREATE TABLE %s (%s)", d.Quote(m.TableName), strings.Join(columns, ", "))
@@ -104,10 +107,11 @@ func (d *oracle) GetColumnsSQL(tableName string) (string, []any) {
 }
 
 func (d *oracle) AddColumnSQL(tableName string, field *model.Field) (string, []any) {
-	sql := fmt.Sprintf("ALTER TABLE %s ADD (%s %s)",
+	sql := fmt.Sprintf("ALTER TABLE %s ADD (%s %s%s)",
 		d.Quote(tableName),
 		d.Quote(field.Column),
 		d.DataTypeOf(field.Type),
+		generatedAs(field, "VIRTUAL")+addColumnModifiers(field, true),
 	)
 	return sql, nil
 }
@@ -167,3 +171,80 @@ func (d *oracle) CreateIndexSQL(tableName string, indexName string, columns []st
 	)
 	return sql, nil
 }
+
+func (d *oracle) GroupConcatSQL(column string, separator string) string {
+	return fmt.Sprintf("LISTAGG(%s, %s) WITHIN GROUP (ORDER BY %s)", column, quoteString(separator), column)
+}
+
+func (d *oracle) TupleInSQL(columns []string, rowCount int) string {
+	return tupleIn(columns, rowCount)
+}
+
+func (d *oracle) JSONExtractSQL(column, path string) string {
+	return fmt.Sprintf("JSON_VALUE(%s, %s)", column, quoteString(jsonPath(path)))
+}
+
+func (d *oracle) LikeSQL(column string, insensitive bool) string {
+	return likeEscape(column, insensitive)
+}
+
+func (d *oracle) OrderByNullsSQL(column, direction string, nullsFirst bool) string {
+	return nullsOrder(column, direction, nullsFirst)
+}
+
+// CallProcSQL wraps the call in an anonymous block, from which a procedure returns result
+// sets with DBMS_SQL.RETURN_RESULT.
+func (d *oracle) CallProcSQL(name string, argCount int) string {
+	return "BEGIN " + name + "(" + procArgs(d, argCount) + "); END;"
+}
+
+// DateSQL formats the date as text, as comparing a DATE with a string literal depends
+// on the session's NLS_DATE_FORMAT.
+func (d *oracle) DateSQL(column string) string {
+	return fmt.Sprintf("TO_CHAR(%s, 'YYYY-MM-DD')", column)
+}
+
+// LimitOffsetSQL uses the OFFSET ... FETCH NEXT row limiting clause of Oracle 12c and later.
+func (d *oracle) LimitOffsetSQL(limit, offset int, hasOrderBy bool) (string, []any) {
+	return offsetFetch(limit, offset)
+}
+
+func (d *oracle) SavepointSQL(name string) string {
+	return "SAVEPOINT " + name
+}
+
+func (d *oracle) RollbackToSQL(name string) string {
+	return "ROLLBACK TO SAVEPOINT " + name
+}
+
+// ReleaseSavepointSQL returns an empty string: Oracle has no RELEASE SAVEPOINT and
+// frees savepoints at the end of the transaction.
+func (d *oracle) ReleaseSavepointSQL(name string) string {
+	return ""
+}
+
+// UpsertSQL returns an empty string: Oracle needs a MERGE statement to upsert.
+func (d *oracle) UpsertSQL(conflictColumns, updateColumns []string) string {
+	return ""
+}
+
+func (d *oracle) BatchFirstInsertID(lastInsertID, rowCount int64) (int64, bool) {
+	return 0, false
+}
+
+func (d *oracle) ColumnCommentSQL(tableName string, field *model.Field) string {
+	return commentOn(d, tableName, field)
+}
+
+func (d *oracle) MaxPlaceholders() int {
+	return 65535
+}
+
+// Capabilities reports no RETURNING support as Oracle's RETURNING INTO needs output binds.
+func (d *oracle) Capabilities() DialectCapabilities {
+	return DialectCapabilities{
+		WindowFunctions: true,
+		RowValues:       true,
+		ColumnComments:  true,
+	}
+}
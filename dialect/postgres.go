@@ -35,6 +35,11 @@ func (d *postgres) DataTypeOf(typ reflect.Type) string {
 		if typ.Elem().Kind() == reflect.Uint8 {
 			return "bytea"
 		}
+		// Other slices map to arrays of their element type, e.g. []int64 -> bigint[]
+		if typ.Elem().Kind() == reflect.String {
+			return "text[]"
+		}
+		return d.DataTypeOf(typ.Elem()) + "[]"
 	case reflect.Struct:
 		if typ.Name() == "Time" {
 			return "timestamp with time zone"
@@ -81,6 +86,10 @@ func (d *postgres) CreateTableSQL(m *model.Model) (string, []any) {
 				column += " GENERATED ALWAYS AS IDENTITY"
 			}
 		}
+		// PostgreSQL only supports stored generated columns
+		column += generatedAs(field, "STORED")
+		column += defaultFunc(field)
+		column += enumCheck(d.Quote(field.Column), field)
 		columns = append(columns, column)
 	}
 	sql := fmt.Sprintf("CREATE TABLE %s (%s)", d.Quote(m.TableName), strings.Join(columns, ", "))
@@ -121,10 +130,11 @@ func (d *postgres) GetColumnsSQL(tableName string) (string, []any) {
 }
 
 func (d *postgres) AddColumnSQL(tableName string, field *model.Field) (string, []any) {
-	sql := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s",
+	sql := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s%s",
 		d.Quote(tableName),
 		d.Quote(field.Column),
 		d.DataTypeOf(field.Type),
+		generatedAs(field, "STORED")+addColumnModifiers(field, true),
 	)
 	return sql, nil
 }
@@ -177,6 +187,10 @@ func (d *postgres) ParseIndexes(rows *sql.Rows) (map[string][]string, error) {
 }
 
 func (d *postgres) CreateIndexSQL(tableName string, indexName string, columns []string, unique bool) (string, []any) {
+	return d.CreatePartialIndexSQL(tableName, indexName, columns, unique, "")
+}
+
+func (d *postgres) CreatePartialIndexSQL(tableName string, indexName string, columns []string, unique bool, where string) (string, []any) {
 	uniqueStr := ""
 	if unique {
 		uniqueStr = "UNIQUE "
@@ -187,5 +201,91 @@ func (d *postgres) CreateIndexSQL(tableName string, indexName string, columns []
 		d.Quote(tableName),
 		strings.Join(columns, ", "),
 	)
+	if where != "" {
+		sql += " WHERE " + where
+	}
 	return sql, nil
 }
+
+func (d *postgres) GroupConcatSQL(column string, separator string) string {
+	return fmt.Sprintf("STRING_AGG(CAST(%s AS TEXT), %s)", column, quoteString(separator))
+}
+
+func (d *postgres) TupleInSQL(columns []string, rowCount int) string {
+	return tupleIn(columns, rowCount)
+}
+
+// JSONExtractSQL uses ->> for a single key and #>> for nested paths, both yielding text.
+func (d *postgres) JSONExtractSQL(column, path string) string {
+	keys := strings.Split(strings.TrimPrefix(strings.TrimPrefix(path, "$"), "."), ".")
+	if len(keys) == 1 {
+		return fmt.Sprintf("%s->>%s", column, quoteString(keys[0]))
+	}
+	return fmt.Sprintf("%s#>>%s", column, quoteString("{"+strings.Join(keys, ",")+"}"))
+}
+
+// LikeSQL relies on the backslash being PostgreSQL's default LIKE escape character.
+func (d *postgres) LikeSQL(column string, insensitive bool) string {
+	if insensitive {
+		return column + " ILIKE ?"
+	}
+	return column + " LIKE ?"
+}
+
+func (d *postgres) OrderByNullsSQL(column, direction string, nullsFirst bool) string {
+	return nullsOrder(column, direction, nullsFirst)
+}
+
+func (d *postgres) CallProcSQL(name string, argCount int) string {
+	return "CALL " + name + "(" + procArgs(d, argCount) + ")"
+}
+
+func (d *postgres) DateSQL(column string) string {
+	return column + "::date"
+}
+
+func (d *postgres) LimitOffsetSQL(limit, offset int, hasOrderBy bool) (string, []any) {
+	return limitOffset(limit, offset)
+}
+
+func (d *postgres) SavepointSQL(name string) string {
+	return "SAVEPOINT " + name
+}
+
+func (d *postgres) RollbackToSQL(name string) string {
+	return "ROLLBACK TO SAVEPOINT " + name
+}
+
+func (d *postgres) ReleaseSavepointSQL(name string) string {
+	return "RELEASE SAVEPOINT " + name
+}
+
+func (d *postgres) UpsertSQL(conflictColumns, updateColumns []string) string {
+	return onConflict(d, conflictColumns, updateColumns)
+}
+
+func (d *postgres) BatchFirstInsertID(lastInsertID, rowCount int64) (int64, bool) {
+	return 0, false
+}
+
+func (d *postgres) ColumnCommentSQL(tableName string, field *model.Field) string {
+	return commentOn(d, tableName, field)
+}
+
+// MaxPlaceholders returns the limit of the wire protocol, whose parameter count is a
+// 16-bit integer.
+func (d *postgres) MaxPlaceholders() int {
+	return 65535
+}
+
+func (d *postgres) Capabilities() DialectCapabilities {
+	return DialectCapabilities{
+		Returning:         true,
+		Upsert:            true,
+		PartialIndexes:    true,
+		WindowFunctions:   true,
+		RowValues:         true,
+		ReleaseSavepoints: true,
+		ColumnComments:    true,
+	}
+}
@@ -71,6 +71,9 @@ func (d *sqlite3) CreateTableSQL(m *model.Model) (string, []any) {
 		if field.IsAuto {
 			column += " AUTOINCREMENT"
 		}
+		column += generatedAs(field, "")
+		column += defaultFunc(field)
+		column += enumCheck(d.Quote(field.Column), field)
 		columns = append(columns, column)
 	}
 	sql := fmt.Sprintf("CREATE TABLE %s (%s)", d.Quote(m.TableName), strings.Join(columns, ", "))
@@ -107,11 +110,13 @@ func (d *sqlite3) GetColumnsSQL(tableName string) (string, []any) {
 	return fmt.Sprintf("PRAGMA table_info(%s)", d.Quote(tableName)), nil
 }
 
+// AddColumnSQL leaves out function defaults, which SQLite rejects in ADD COLUMN.
 func (d *sqlite3) AddColumnSQL(tableName string, field *model.Field) (string, []any) {
-	sql := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s",
+	sql := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s%s",
 		d.Quote(tableName),
 		d.Quote(field.Column),
 		d.DataTypeOf(field.Type),
+		generatedAs(field, "")+addColumnModifiers(field, false),
 	)
 	return sql, nil
 }
@@ -176,6 +181,10 @@ func (d *sqlite3) ParseIndexes(rows *sql.Rows) (map[string][]string, error) {
 }
 
 func (d *sqlite3) CreateIndexSQL(tableName string, indexName string, columns []string, unique bool) (string, []any) {
+	return d.CreatePartialIndexSQL(tableName, indexName, columns, unique, "")
+}
+
+func (d *sqlite3) CreatePartialIndexSQL(tableName string, indexName string, columns []string, unique bool, where string) (string, []any) {
 	uniqueStr := ""
 	if unique {
 		uniqueStr = "UNIQUE "
@@ -186,5 +195,88 @@ func (d *sqlite3) CreateIndexSQL(tableName string, indexName string, columns []s
 		d.Quote(tableName),
 		strings.Join(columns, ", "),
 	)
+	if where != "" {
+		sql += " WHERE " + where
+	}
 	return sql, nil
 }
+
+func (d *sqlite3) GroupConcatSQL(column string, separator string) string {
+	return fmt.Sprintf("GROUP_CONCAT(%s, %s)", column, quoteString(separator))
+}
+
+func (d *sqlite3) TupleInSQL(columns []string, rowCount int) string {
+	return tupleIn(columns, rowCount)
+}
+
+func (d *sqlite3) JSONExtractSQL(column, path string) string {
+	return fmt.Sprintf("JSON_EXTRACT(%s, %s)", column, quoteString(jsonPath(path)))
+}
+
+func (d *sqlite3) LikeSQL(column string, insensitive bool) string {
+	return likeEscape(column, insensitive)
+}
+
+// OrderByNullsSQL emulates NULLS FIRST/LAST, which SQLite only supports since 3.30.
+func (d *sqlite3) OrderByNullsSQL(column, direction string, nullsFirst bool) string {
+	return nullsCase(column, direction, nullsFirst)
+}
+
+// CallProcSQL returns an empty string as SQLite has no stored procedures.
+func (d *sqlite3) CallProcSQL(name string, argCount int) string {
+	return ""
+}
+
+func (d *sqlite3) DateSQL(column string) string {
+	return fmt.Sprintf("DATE(%s)", column)
+}
+
+func (d *sqlite3) LimitOffsetSQL(limit, offset int, hasOrderBy bool) (string, []any) {
+	return limitOffset(limit, offset)
+}
+
+func (d *sqlite3) SavepointSQL(name string) string {
+	return "SAVEPOINT " + name
+}
+
+func (d *sqlite3) RollbackToSQL(name string) string {
+	return "ROLLBACK TO SAVEPOINT " + name
+}
+
+func (d *sqlite3) ReleaseSavepointSQL(name string) string {
+	return "RELEASE SAVEPOINT " + name
+}
+
+func (d *sqlite3) UpsertSQL(conflictColumns, updateColumns []string) string {
+	return onConflict(d, conflictColumns, updateColumns)
+}
+
+// BatchFirstInsertID counts back from the rowid of the last row, which is what SQLite
+// reports for a multi-row INSERT.
+func (d *sqlite3) BatchFirstInsertID(lastInsertID, rowCount int64) (int64, bool) {
+	return lastInsertID - rowCount + 1, true
+}
+
+// ColumnCommentSQL returns an empty string as SQLite has no column comments.
+func (d *sqlite3) ColumnCommentSQL(tableName string, field *model.Field) string {
+	return ""
+}
+
+// MaxPlaceholders returns SQLITE_MAX_VARIABLE_NUMBER as of SQLite 3.32; older versions
+// allow 999.
+func (d *sqlite3) MaxPlaceholders() int {
+	return 32766
+}
+
+// Capabilities describes SQLite 3.35 or later, which added RETURNING.
+func (d *sqlite3) Capabilities() DialectCapabilities {
+	return DialectCapabilities{
+		Returning:         true,
+		Upsert:            true,
+		PartialIndexes:    true,
+		WindowFunctions:   true,
+		RowValues:         true,
+		ReleaseSavepoints: true,
+		BatchInsertIDs:    true,
+	}
+}
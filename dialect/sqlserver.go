@@ -58,6 +58,11 @@ func (d *sqlserver) InsertSQL(table string, columns []string) (string, []any) {
 func (d *sqlserver) CreateTableSQL(m *model.Model) (string, []any) {
 	var columns []string
 	for _, field := range m.Fields {
+		if field.GeneratedAs != "" {
+			// Computed columns take their type from the expression
+			columns = append(columns, d.computedColumn(field))
+			continue
+		}
 		column := fmt.Sprintf("%s %s", d.Quote(field.Column), d.DataTypeOf(field.Type))
 		if field.IsPK {
 			column += " PRIMARY KEY"
@@ -65,6 +70,8 @@ func (d *sqlserver) CreateTableSQL(m *model.Model) (string, []any) {
 		if field.IsAuto {
 			column += " IDENTITY(1,1)"
 		}
+		column += defaultFunc(field)
+		column += enumCheck(d.Quote(field.Column), field)
 		columns = append(columns, column)
 	}
 	sql := fmt.Sprintf("CREATE TABLE %s (%s)", d.Quote(m.TableName), strings.Join(columns, ", "))
@@ -104,14 +111,23 @@ func (d *sqlserver) GetColumnsSQL(tableName string) (string, []any) {
 }
 
 func (d *sqlserver) AddColumnSQL(tableName string, field *model.Field) (string, []any) {
-	sql := fmt.Sprintf("ALTER TABLE %s ADD %s %s",
+	if field.GeneratedAs != "" {
+		return fmt.Sprintf("ALTER TABLE %s ADD %s", d.Quote(tableName), d.computedColumn(field)), nil
+	}
+	sql := fmt.Sprintf("ALTER TABLE %s ADD %s %s%s",
 		d.Quote(tableName),
 		d.Quote(field.Column),
 		d.DataTypeOf(field.Type),
+		addColumnModifiers(field, true),
 	)
 	return sql, nil
 }
 
+// computedColumn returns the definition of a generated column, "name AS (expr)".
+func (d *sqlserver) computedColumn(field *model.Field) string {
+	return d.Quote(field.Column) + strings.Replace(generatedAs(field, ""), " GENERATED ALWAYS", "", 1)
+}
+
 func (d *sqlserver) ModifyColumnSQL(tableName string, field *model.Field) (string, []any) {
 	sql := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s",
 		d.Quote(tableName),
@@ -157,6 +173,10 @@ func (d *sqlserver) ParseIndexes(rows *sql.Rows) (map[string][]string, error) {
 }
 
 func (d *sqlserver) CreateIndexSQL(tableName string, indexName string, columns []string, unique bool) (string, []any) {
+	return d.CreatePartialIndexSQL(tableName, indexName, columns, unique, "")
+}
+
+func (d *sqlserver) CreatePartialIndexSQL(tableName string, indexName string, columns []string, unique bool, where string) (string, []any) {
 	uniqueStr := ""
 	if unique {
 		uniqueStr = "UNIQUE "
@@ -167,5 +187,96 @@ func (d *sqlserver) CreateIndexSQL(tableName string, indexName string, columns [
 		d.Quote(tableName),
 		strings.Join(columns, ", "),
 	)
+	if where != "" {
+		sql += " WHERE " + where
+	}
 	return sql, nil
 }
+
+func (d *sqlserver) GroupConcatSQL(column string, separator string) string {
+	return fmt.Sprintf("STRING_AGG(CAST(%s AS NVARCHAR(MAX)), %s)", column, quoteString(separator))
+}
+
+// TupleInSQL falls back to OR-ed equality groups since SQL Server has no row constructors.
+func (d *sqlserver) TupleInSQL(columns []string, rowCount int) string {
+	return tupleOr(columns, rowCount)
+}
+
+func (d *sqlserver) JSONExtractSQL(column, path string) string {
+	return fmt.Sprintf("JSON_VALUE(%s, %s)", column, quoteString(jsonPath(path)))
+}
+
+func (d *sqlserver) LikeSQL(column string, insensitive bool) string {
+	return likeEscape(column, insensitive)
+}
+
+func (d *sqlserver) OrderByNullsSQL(column, direction string, nullsFirst bool) string {
+	return nullsCase(column, direction, nullsFirst)
+}
+
+func (d *sqlserver) CallProcSQL(name string, argCount int) string {
+	if argCount == 0 {
+		return "EXEC " + name
+	}
+	return "EXEC " + name + " " + procArgs(d, argCount)
+}
+
+func (d *sqlserver) DateSQL(column string) string {
+	return fmt.Sprintf("CAST(%s AS DATE)", column)
+}
+
+// LimitOffsetSQL uses OFFSET ... FETCH NEXT, which SQL Server only accepts after an
+// ORDER BY; a no-op ORDER BY (SELECT NULL) is added when the statement has none.
+func (d *sqlserver) LimitOffsetSQL(limit, offset int, hasOrderBy bool) (string, []any) {
+	clause, args := offsetFetch(limit, offset)
+	if clause != "" && !hasOrderBy {
+		clause = "ORDER BY (SELECT NULL) " + clause
+	}
+	return clause, args
+}
+
+func (d *sqlserver) SavepointSQL(name string) string {
+	return "SAVE TRANSACTION " + name
+}
+
+func (d *sqlserver) RollbackToSQL(name string) string {
+	return "ROLLBACK TRANSACTION " + name
+}
+
+// ReleaseSavepointSQL returns an empty string: SQL Server cannot release a savepoint
+// before the transaction ends.
+func (d *sqlserver) ReleaseSavepointSQL(name string) string {
+	return ""
+}
+
+// UpsertSQL returns an empty string: SQL Server needs a MERGE statement to upsert.
+func (d *sqlserver) UpsertSQL(conflictColumns, updateColumns []string) string {
+	return ""
+}
+
+func (d *sqlserver) BatchFirstInsertID(lastInsertID, rowCount int64) (int64, bool) {
+	return 0, false
+}
+
+// ColumnCommentSQL stores the comment as the column's MS_Description extended property,
+// assuming the table is in the dbo schema.
+func (d *sqlserver) ColumnCommentSQL(tableName string, field *model.Field) string {
+	if field.Comment == "" {
+		return ""
+	}
+	return fmt.Sprintf("EXEC sp_addextendedproperty 'MS_Description', %s, 'SCHEMA', 'dbo', 'TABLE', %s, 'COLUMN', %s",
+		quoteString(field.Comment), quoteString(tableName), quoteString(field.Column))
+}
+
+func (d *sqlserver) MaxPlaceholders() int {
+	return 2100
+}
+
+// Capabilities reports no RETURNING support as SQL Server uses an OUTPUT clause instead.
+func (d *sqlserver) Capabilities() DialectCapabilities {
+	return DialectCapabilities{
+		PartialIndexes:  true,
+		WindowFunctions: true,
+		ColumnComments:  true,
+	}
+}
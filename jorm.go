@@ -12,11 +12,24 @@ type Options = core.Options
 
 var Open = core.Open
 
+// EscapeLike escapes the wildcards of user input placed in a WhereLike pattern
+var EscapeLike = core.EscapeLike
+
+// Named connections
+var (
+	Register   = core.Register
+	Unregister = core.Unregister
+	Use        = core.Use
+	Default    = core.Default
+	SetDefault = core.SetDefault
+)
+
 // Re-export validator types and functions
 type Validator = validator.Validator
 type ValidationErrors = validator.ValidationErrors
 type Rules = validator.Rules
 type Rule = validator.Rule
+type FieldRule = validator.FieldRule
 
 var (
 	Validate = validator.Validate
@@ -45,4 +58,9 @@ var (
 	Regexp   = validator.Regexp
 	Contains = validator.Contains
 	Excludes = validator.Excludes
+	RuleFunc = validator.Func
+
+	// Tag-based validation
+	RegisterRule = validator.Register
+	ValidateTags = validator.Tags
 )
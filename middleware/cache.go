@@ -0,0 +1,172 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shrek82/jorm/core"
+)
+
+// cacheKey generates the cache key shared by all cache middlewares for a query.
+func cacheKey(ctx context.Context, query *core.Query) string {
+	sqlStr, args := query.GetSelectSQL()
+	key := fmt.Sprintf("jorm:cache:%s:%v", sqlStr, args)
+	// Keep results of differently tagged requests (e.g. tenants) apart
+	if tags := core.QueryTags(ctx); len(tags) > 0 {
+		names := make([]string, 0, len(tags))
+		for k := range tags {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		for _, k := range names {
+			key += fmt.Sprintf(":%s=%v", k, tags[k])
+		}
+	}
+	return key
+}
+
+// isWrite reports whether query changes rows of its table, after which the cache
+// middlewares drop the results cached for that table. Raw statements are not tracked.
+func isWrite(query *core.Query) bool {
+	switch query.Operation() {
+	case core.OpInsert, core.OpUpdate, core.OpDelete:
+		return true
+	}
+	return false
+}
+
+// defaultCacheTTL returns the TTL used by Cache() without arguments:
+// the configured default if set, else 24h.
+func defaultCacheTTL(defaultTTL time.Duration) time.Duration {
+	if defaultTTL > 0 {
+		return defaultTTL
+	}
+	return 24 * time.Hour
+}
+
+// jitterTTL randomizes ttl by up to ±fraction (e.g. 0.2 => ±20%) so entries written
+// together don't expire at the same instant. A fraction <= 0 returns ttl unchanged.
+func jitterTTL(ttl time.Duration, fraction float64) time.Duration {
+	if fraction <= 0 || ttl <= 0 {
+		return ttl
+	}
+	if fraction > 1 {
+		fraction = 1
+	}
+	delta := (rand.Float64()*2 - 1) * fraction * float64(ttl)
+	return ttl + time.Duration(delta)
+}
+
+// warmQuery executes a model query into a new slice of the model type and returns
+// the cache key and the JSON-encoded result, ready to be stored by a cache middleware.
+func warmQuery(ctx context.Context, query *core.Query) (string, []byte, error) {
+	m := query.GetModel()
+	if m == nil {
+		return "", nil, fmt.Errorf("cache warm requires a model query (use db.Model)")
+	}
+
+	// The key must be computed before execution since Find releases the builder.
+	key := cacheKey(ctx, query)
+
+	dest := reflect.New(reflect.SliceOf(m.OriginalType)).Interface()
+	if err := query.WithContext(ctx).Find(dest); err != nil {
+		return "", nil, fmt.Errorf("cache warm failed: %w", err)
+	}
+
+	data, err := json.Marshal(dest)
+	if err != nil {
+		return "", nil, fmt.Errorf("cache warm failed to encode result: %w", err)
+	}
+	return key, data, nil
+}
+
+// decodeCached unmarshals cached data into query.Dest.
+// It decodes into a temporary object first to avoid corrupting Dest on failure
+// and reports whether Dest was populated.
+func decodeCached(query *core.Query, data []byte) bool {
+	if query.Dest == nil {
+		return false
+	}
+
+	// Aggregate results (Count, Sum) are parsed directly so that the numeric type
+	// is preserved and large values don't lose precision via float64.
+	switch dest := query.Dest.(type) {
+	case *int64:
+		n, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+		if err != nil {
+			return false
+		}
+		*dest = n
+		return true
+	case *float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(string(data)), 64)
+		if err != nil {
+			return false
+		}
+		*dest = f
+		return true
+	}
+
+	destType := reflect.TypeOf(query.Dest)
+	if destType.Kind() != reflect.Ptr {
+		return false
+	}
+	temp := reflect.New(destType.Elem()).Interface()
+	if err := json.Unmarshal(data, temp); err != nil {
+		return false
+	}
+	reflect.ValueOf(query.Dest).Elem().Set(reflect.ValueOf(temp).Elem())
+	return true
+}
+
+// flightCall is an in-flight or completed flightGroup.do call.
+type flightCall struct {
+	wg   sync.WaitGroup
+	data []byte
+	res  *core.Result
+	err  error
+}
+
+// flightGroup deduplicates concurrent calls with the same key so that only one
+// executes while the others wait and share its result (singleflight).
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// do executes fn for key, making sure only one execution is in flight at a time.
+// Duplicate callers wait for the original to complete and receive the same results;
+// shared reports whether the results were produced by another caller.
+func (g *flightGroup) do(key string, fn func() ([]byte, *core.Result, error)) (data []byte, res *core.Result, err error, shared bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		c.wg.Wait()
+		return c.data, c.res, c.err, true
+	}
+	c := &flightCall{}
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	defer func() {
+		c.wg.Done()
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+	}()
+
+	c.data, c.res, c.err = fn()
+	return c.data, c.res, c.err, false
+}
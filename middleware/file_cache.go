@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"reflect"
 	"time"
 
 	"github.com/shrek82/jorm/core"
@@ -16,9 +15,13 @@ import (
 
 // FileCacheMiddleware caches query results in the file system.
 // To use it, add a duration to the context with key "jorm_cache_ttl".
+// Inserts, updates and deletes run through it remove the files cached for their table.
 type FileCacheMiddleware struct {
 	CacheDir   string
 	DefaultTTL time.Duration
+	// JitterFraction randomizes each entry's TTL by ±fraction (e.g. 0.2 => ±20%)
+	// to avoid synchronized expiry. Defaults to 0 (no jitter).
+	JitterFraction float64
 }
 
 func NewFileCache(cacheDir string, defaultTTL ...time.Duration) *FileCacheMiddleware {
@@ -56,6 +59,13 @@ type fileCacheEntry struct {
 }
 
 func (m *FileCacheMiddleware) Process(ctx context.Context, query *core.Query, next core.QueryFunc) (*core.Result, error) {
+	if isWrite(query) {
+		// Drop the table's results even if the write failed part way
+		res, err := next(ctx, query)
+		m.invalidate(query.TableName())
+		return res, err
+	}
+
 	// Check if caching is enabled for this query
 	ttl := m.DefaultTTL
 	ttlVal := ctx.Value("jorm_cache_ttl")
@@ -72,11 +82,7 @@ func (m *FileCacheMiddleware) Process(ctx context.Context, query *core.Query, ne
 				shouldCache = true
 			} else if t == -2 {
 				// Cache() -> use default if set, else 24h
-				if m.DefaultTTL > 0 {
-					ttl = m.DefaultTTL
-				} else {
-					ttl = 24 * time.Hour // Default fallback
-				}
+				ttl = defaultCacheTTL(m.DefaultTTL)
 				shouldCache = true
 			} else if t > 0 {
 				ttl = t
@@ -90,32 +96,18 @@ func (m *FileCacheMiddleware) Process(ctx context.Context, query *core.Query, ne
 	}
 
 	// Generate cache key
-	sqlStr, args := query.GetSelectSQL()
-	key := fmt.Sprintf("jorm:cache:%s:%v", sqlStr, args)
-	hash := md5.Sum([]byte(key))
-	filename := filepath.Join(m.CacheDir, hex.EncodeToString(hash[:])+".json")
+	filename := m.filename(query.TableName(), cacheKey(ctx, query))
 
 	// Try to get from cache
 	if data, err := os.ReadFile(filename); err == nil {
 		var entry fileCacheEntry
 		if err := json.Unmarshal(data, &entry); err == nil {
 			if time.Now().Before(entry.ExpiresAt) {
-				if query.Dest != nil {
-					// Unmarshal into a temporary object to avoid corrupting Dest on failure
-					destType := reflect.TypeOf(query.Dest)
-					if destType.Kind() == reflect.Ptr {
-						temp := reflect.New(destType.Elem()).Interface()
-						if err := json.Unmarshal(entry.Data, temp); err != nil {
-							// Failed to unmarshal, ignore cache
-						} else {
-							// Success, copy to Dest
-							reflect.ValueOf(query.Dest).Elem().Set(reflect.ValueOf(temp).Elem())
-							return &core.Result{
-								Data:         query.Dest,
-								RowsAffected: 0,
-							}, nil
-						}
-					}
+				if decodeCached(query, entry.Data) {
+					return &core.Result{
+						Data:         query.Dest,
+						RowsAffected: 0,
+					}, nil
 				}
 			} else {
 				// Expired, remove file
@@ -134,15 +126,58 @@ func (m *FileCacheMiddleware) Process(ctx context.Context, query *core.Query, ne
 	if res.Data != nil {
 		data, err := json.Marshal(res.Data)
 		if err == nil {
-			entry := fileCacheEntry{
-				Data:      data,
-				ExpiresAt: time.Now().Add(ttl),
-			}
-			if entryData, err := json.Marshal(entry); err == nil {
-				os.WriteFile(filename, entryData, 0644)
-			}
+			m.store(filename, data, ttl)
 		}
 	}
 
 	return res, nil
 }
+
+// filename returns the cache file path for a cache key of a query on table. The name
+// starts with tablePrefix(table), so invalidate can find the files of a table.
+func (m *FileCacheMiddleware) filename(table, key string) string {
+	hash := md5.Sum([]byte(key))
+	return filepath.Join(m.CacheDir, tablePrefix(table)+hex.EncodeToString(hash[:])+".json")
+}
+
+// tablePrefix returns the file name prefix of the entries cached for table, hashed as
+// table names may hold characters that are not valid in file names.
+func tablePrefix(table string) string {
+	hash := md5.Sum([]byte(table))
+	return hex.EncodeToString(hash[:8]) + "-"
+}
+
+// invalidate removes the files cached for table.
+func (m *FileCacheMiddleware) invalidate(table string) {
+	files, _ := filepath.Glob(filepath.Join(m.CacheDir, tablePrefix(table)+"*.json"))
+	for _, f := range files {
+		os.Remove(f)
+	}
+}
+
+func (m *FileCacheMiddleware) store(filename string, data []byte, ttl time.Duration) error {
+	entry := fileCacheEntry{
+		Data:      data,
+		ExpiresAt: time.Now().Add(jitterTTL(ttl, m.JitterFraction)),
+	}
+	entryData, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, entryData, 0644)
+}
+
+// Warm executes query and stores its result under the same key a cached Find would use,
+// with the default TTL. It bypasses the per-query Cache() gate, so hot queries can be
+// pre-populated at startup. The query must be built with db.Model.
+func (m *FileCacheMiddleware) Warm(ctx context.Context, query *core.Query) error {
+	table := query.TableName()
+	key, data, err := warmQuery(ctx, query)
+	if err != nil {
+		return err
+	}
+	if err := m.store(m.filename(table, key), data, defaultCacheTTL(m.DefaultTTL)); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	return nil
+}
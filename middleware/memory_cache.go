@@ -1,10 +1,9 @@
 package middleware
 
 import (
+	"container/list"
 	"context"
 	"encoding/json"
-	"fmt"
-	"reflect"
 	"sync"
 	"time"
 
@@ -13,11 +12,20 @@ import (
 
 // MemoryCacheMiddleware caches query results in memory.
 // To use it, add a duration to the context with key "jorm_cache_ttl".
+// Inserts, updates and deletes run through it drop the entries cached for their table.
 type MemoryCacheMiddleware struct {
-	items      map[string]memoryCacheEntry
+	items      map[string]*list.Element // Values are *memoryCacheItem
+	order      *list.List               // Access order, front = most recently used
 	mu         sync.RWMutex
 	stopClean  chan struct{}
+	flight     flightGroup
 	DefaultTTL time.Duration
+	// JitterFraction randomizes each entry's TTL by ±fraction (e.g. 0.2 => ±20%)
+	// to avoid synchronized expiry. Defaults to 0 (no jitter).
+	JitterFraction float64
+	// MaxEntries caps the number of cached entries. When exceeded, the least recently
+	// used entry is evicted. Defaults to 0 (unbounded, only TTL cleanup applies).
+	MaxEntries int
 }
 
 type memoryCacheEntry struct {
@@ -25,13 +33,20 @@ type memoryCacheEntry struct {
 	ExpiresAt time.Time
 }
 
+type memoryCacheItem struct {
+	key   string
+	table string // Table of the cached query, see invalidate
+	entry memoryCacheEntry
+}
+
 func NewMemoryCache(defaultTTL ...time.Duration) *MemoryCacheMiddleware {
 	ttl := 5 * time.Minute
 	if len(defaultTTL) > 0 {
 		ttl = defaultTTL[0]
 	}
 	return &MemoryCacheMiddleware{
-		items:      make(map[string]memoryCacheEntry),
+		items:      make(map[string]*list.Element),
+		order:      list.New(),
 		stopClean:  make(chan struct{}),
 		DefaultTTL: ttl,
 	}
@@ -65,19 +80,35 @@ func (m *MemoryCacheMiddleware) cleanup() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	now := time.Now()
-	for k, v := range m.items {
-		if !v.ExpiresAt.IsZero() && now.After(v.ExpiresAt) {
-			delete(m.items, k)
+	for _, el := range m.items {
+		item := el.Value.(*memoryCacheItem)
+		if !item.entry.ExpiresAt.IsZero() && now.After(item.entry.ExpiresAt) {
+			m.removeElement(el)
 		}
 	}
 }
 
+// removeElement deletes an entry from both the index and the access order.
+// The caller must hold m.mu.
+func (m *MemoryCacheMiddleware) removeElement(el *list.Element) {
+	item := el.Value.(*memoryCacheItem)
+	delete(m.items, item.key)
+	m.order.Remove(el)
+}
+
 func (m *MemoryCacheMiddleware) Shutdown() error {
 	close(m.stopClean)
 	return nil
 }
 
 func (m *MemoryCacheMiddleware) Process(ctx context.Context, query *core.Query, next core.QueryFunc) (*core.Result, error) {
+	if isWrite(query) {
+		// Drop the table's results even if the write failed part way
+		res, err := next(ctx, query)
+		m.invalidate(query.TableName())
+		return res, err
+	}
+
 	// Check if caching is enabled for this query
 	ttl := m.DefaultTTL
 	ttlVal := ctx.Value("jorm_cache_ttl")
@@ -94,11 +125,7 @@ func (m *MemoryCacheMiddleware) Process(ctx context.Context, query *core.Query,
 				shouldCache = true
 			} else if t == -2 {
 				// Cache() -> use default if set, else 24h
-				if m.DefaultTTL > 0 {
-					ttl = m.DefaultTTL
-				} else {
-					ttl = 24 * time.Hour // Default fallback
-				}
+				ttl = defaultCacheTTL(m.DefaultTTL)
 				shouldCache = true
 			} else if t > 0 {
 				ttl = t
@@ -112,59 +139,124 @@ func (m *MemoryCacheMiddleware) Process(ctx context.Context, query *core.Query,
 	}
 
 	// Generate cache key
-	sqlStr, args := query.GetSelectSQL()
-	key := fmt.Sprintf("jorm:cache:%s:%v", sqlStr, args)
+	key := cacheKey(ctx, query)
+	table := query.TableName()
 
 	// Try to get from cache
-	m.mu.RLock()
-	entry, found := m.items[key]
-	m.mu.RUnlock()
-
-	if found {
-		if entry.ExpiresAt.IsZero() || time.Now().Before(entry.ExpiresAt) {
-			if query.Dest != nil {
-				// Unmarshal into a temporary object to avoid corrupting Dest on failure
-				destType := reflect.TypeOf(query.Dest)
-				if destType.Kind() == reflect.Ptr {
-					temp := reflect.New(destType.Elem()).Interface()
-					if err := json.Unmarshal(entry.Data, temp); err != nil {
-						// Failed to unmarshal, ignore cache
-					} else {
-						// Success, copy to Dest
-						reflect.ValueOf(query.Dest).Elem().Set(reflect.ValueOf(temp).Elem())
-						return &core.Result{
-							Data:         query.Dest,
-							RowsAffected: 0,
-						}, nil
-					}
-				}
-			}
-		} else {
-			// Expired, delete (lazy delete)
-			m.mu.Lock()
-			delete(m.items, key)
-			m.mu.Unlock()
-		}
+	if data, ok := m.get(key); ok && decodeCached(query, data) {
+		return &core.Result{
+			Data:         query.Dest,
+			RowsAffected: 0,
+		}, nil
 	}
 
-	// Cache miss or failure
-	res, err := next(ctx, query)
+	// Cache miss or failure: collapse concurrent identical misses into a single DB call
+	data, res, err, shared := m.flight.do(key, func() ([]byte, *core.Result, error) {
+		// Another caller may have filled the entry between our lookup and joining the flight
+		if data, ok := m.get(key); ok {
+			return data, nil, nil
+		}
+
+		res, err := next(ctx, query)
+		if err != nil {
+			return nil, res, err
+		}
+
+		// Cache the result
+		var data []byte
+		if res.Data != nil {
+			if data, err = json.Marshal(res.Data); err == nil {
+				m.store(key, table, data, ttl)
+			} else {
+				data = nil
+			}
+		}
+		return data, res, nil
+	})
 	if err != nil {
 		return res, err
 	}
 
-	// Cache the result
-	if res.Data != nil {
-		data, err := json.Marshal(res.Data)
-		if err == nil {
-			m.mu.Lock()
-			m.items[key] = memoryCacheEntry{
-				Data:      data,
-				ExpiresAt: time.Now().Add(ttl),
-			}
-			m.mu.Unlock()
+	if shared || res == nil {
+		// The result was produced for another caller's Dest, decode our own copy
+		if data != nil && decodeCached(query, data) {
+			return &core.Result{
+				Data:         query.Dest,
+				RowsAffected: 0,
+			}, nil
 		}
+		return next(ctx, query)
 	}
 
 	return res, nil
 }
+
+// get returns the cached data for key if present and not expired, marking it as
+// most recently used. Expired entries are deleted lazily.
+func (m *MemoryCacheMiddleware) get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, found := m.items[key]
+	if !found {
+		return nil, false
+	}
+	item := el.Value.(*memoryCacheItem)
+	if item.entry.ExpiresAt.IsZero() || time.Now().Before(item.entry.ExpiresAt) {
+		m.order.MoveToFront(el)
+		return item.entry.Data, true
+	}
+
+	// Expired, delete (lazy delete)
+	m.removeElement(el)
+	return nil, false
+}
+
+func (m *MemoryCacheMiddleware) store(key, table string, data []byte, ttl time.Duration) {
+	entry := memoryCacheEntry{
+		Data:      data,
+		ExpiresAt: time.Now().Add(jitterTTL(ttl, m.JitterFraction)),
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.order == nil {
+		m.order = list.New()
+	}
+	if el, ok := m.items[key]; ok {
+		el.Value.(*memoryCacheItem).entry = entry
+		m.order.MoveToFront(el)
+	} else {
+		m.items[key] = m.order.PushFront(&memoryCacheItem{key: key, table: table, entry: entry})
+	}
+
+	// Evict least recently used entries beyond the cap
+	for m.MaxEntries > 0 && m.order.Len() > m.MaxEntries {
+		m.removeElement(m.order.Back())
+	}
+}
+
+// invalidate deletes the entries cached for table.
+func (m *MemoryCacheMiddleware) invalidate(table string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, el := range m.items {
+		if el.Value.(*memoryCacheItem).table == table {
+			m.removeElement(el)
+		}
+	}
+}
+
+// Warm executes query and stores its result under the same key a cached Find would use,
+// with the default TTL. It bypasses the per-query Cache() gate, so hot queries can be
+// pre-populated at startup. The query must be built with db.Model.
+func (m *MemoryCacheMiddleware) Warm(ctx context.Context, query *core.Query) error {
+	table := query.TableName()
+	key, data, err := warmQuery(ctx, query)
+	if err != nil {
+		return err
+	}
+	m.store(key, table, data, defaultCacheTTL(m.DefaultTTL))
+	return nil
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"reflect"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -13,6 +12,8 @@ import (
 
 // RedisCacheMiddleware caches query results in Redis.
 // To use it, add a duration to the context with key "jorm_cache_ttl".
+// Inserts, updates and deletes run through it bump a version of their table kept in
+// Redis, which is part of the cache keys, so earlier results of the table are not read.
 type RedisCacheMiddleware struct {
 	Client     *redis.Client
 	DefaultTTL time.Duration
@@ -43,6 +44,14 @@ func (m *RedisCacheMiddleware) Shutdown() error {
 }
 
 func (m *RedisCacheMiddleware) Process(ctx context.Context, query *core.Query, next core.QueryFunc) (*core.Result, error) {
+	if isWrite(query) {
+		// Bump the table's version even if the write failed part way, so the results cached
+		// for it are no longer read; they expire with their TTL
+		res, err := next(ctx, query)
+		m.Client.Incr(context.WithoutCancel(ctx), versionKey(query.TableName()))
+		return res, err
+	}
+
 	// Check if caching is enabled for this query
 	ttlVal := ctx.Value("jorm_cache_ttl")
 	if ttlVal == nil {
@@ -59,11 +68,7 @@ func (m *RedisCacheMiddleware) Process(ctx context.Context, query *core.Query, n
 			ttl = 0 // Redis 0 means permanent
 		} else if t == -2 {
 			// Cache() -> use default if set, else 24h
-			if m.DefaultTTL > 0 {
-				ttl = m.DefaultTTL
-			} else {
-				ttl = 24 * time.Hour
-			}
+			ttl = defaultCacheTTL(m.DefaultTTL)
 		} else {
 			ttl = t
 		}
@@ -71,38 +76,19 @@ func (m *RedisCacheMiddleware) Process(ctx context.Context, query *core.Query, n
 		return next(ctx, query)
 	}
 
-	// Generate cache key
-	sqlStr, args := query.GetSelectSQL()
-	if sqlStr == "" {
-		// Not a select query or SQL not ready?
-		// Try to build it if possible, but middleware is usually executed before query execution
-		// core.Query.GetSelectSQL() builds it from builder if rawSQL is empty.
-	}
-
 	// Create a simple cache key
 	// In a real app, might want to hash this
-	key := fmt.Sprintf("jorm:cache:%s:%v", sqlStr, args)
+	key := versionedKey(cacheKey(ctx, query), m.version(ctx, query.TableName()))
 
 	// Try to get from cache
 	val, err := m.Client.Get(ctx, key).Result()
 	if err == nil {
 		// Cache hit
-		if query.Dest != nil {
-			// Unmarshal into a temporary object to avoid corrupting Dest on failure
-			destType := reflect.TypeOf(query.Dest)
-			if destType.Kind() == reflect.Ptr {
-				temp := reflect.New(destType.Elem()).Interface()
-				if err := json.Unmarshal([]byte(val), temp); err != nil {
-					// Failed to unmarshal, proceed to DB
-				} else {
-					// Success, copy to Dest
-					reflect.ValueOf(query.Dest).Elem().Set(reflect.ValueOf(temp).Elem())
-					return &core.Result{
-						Data:         query.Dest,
-						RowsAffected: 0,
-					}, nil
-				}
-			}
+		if decodeCached(query, []byte(val)) {
+			return &core.Result{
+				Data:         query.Dest,
+				RowsAffected: 0,
+			}, nil
 		}
 	}
 
@@ -122,3 +108,35 @@ func (m *RedisCacheMiddleware) Process(ctx context.Context, query *core.Query, n
 
 	return res, nil
 }
+
+// versionKey returns the Redis key counting the writes to table. Shared by all clients,
+// it makes a write on one instance invalidate the entries cached by the others.
+func versionKey(table string) string {
+	return "jorm:cache:version:" + table
+}
+
+// version returns the number of writes to table, 0 if unknown.
+func (m *RedisCacheMiddleware) version(ctx context.Context, table string) int64 {
+	n, _ := m.Client.Get(ctx, versionKey(table)).Int64()
+	return n
+}
+
+// versionedKey qualifies a cache key with the version of its table.
+func versionedKey(key string, version int64) string {
+	return fmt.Sprintf("%s:v%d", key, version)
+}
+
+// Warm executes query and stores its result under the same key a cached Find would use,
+// with the default TTL. It bypasses the per-query Cache() gate, so hot queries can be
+// pre-populated at startup. The query must be built with db.Model.
+func (m *RedisCacheMiddleware) Warm(ctx context.Context, query *core.Query) error {
+	version := m.version(ctx, query.TableName())
+	key, data, err := warmQuery(ctx, query)
+	if err != nil {
+		return err
+	}
+	if err := m.Client.Set(ctx, versionedKey(key, version), data, defaultCacheTTL(m.DefaultTTL)).Err(); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	return nil
+}
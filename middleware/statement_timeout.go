@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/shrek82/jorm/core"
+)
+
+// StatementTimeoutMiddleware cancels queries running longer than Timeout, as a safety net
+// against runaway statements. Contexts with an earlier deadline keep it.
+type StatementTimeoutMiddleware struct {
+	Timeout time.Duration
+}
+
+// NewStatementTimeout creates a StatementTimeoutMiddleware cancelling queries after d.
+// A d of zero or less disables it.
+func NewStatementTimeout(d time.Duration) *StatementTimeoutMiddleware {
+	return &StatementTimeoutMiddleware{Timeout: d}
+}
+
+func (m *StatementTimeoutMiddleware) Name() string {
+	return "StatementTimeout"
+}
+
+func (m *StatementTimeoutMiddleware) Init(db *core.DB) error {
+	return nil
+}
+
+func (m *StatementTimeoutMiddleware) Shutdown() error {
+	return nil
+}
+
+func (m *StatementTimeoutMiddleware) Process(ctx context.Context, query *core.Query, next core.QueryFunc) (*core.Result, error) {
+	if m.Timeout <= 0 {
+		return next(ctx, query)
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= m.Timeout {
+		return next(ctx, query)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.Timeout)
+	res, err := next(ctx, query)
+	if res != nil {
+		if _, open := res.Data.(*sql.Rows); open {
+			// Rows returned open, e.g. by CallProc, are read after Process returns; they
+			// stay bound to the timeout, which releases the context when it fires
+			time.AfterFunc(m.Timeout, cancel)
+			return res, err
+		}
+	}
+	cancel()
+	return res, err
+}
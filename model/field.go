@@ -9,20 +9,31 @@ type Accessor func(reflect.Value) reflect.Value
 
 // Field represents a database column mapped from a struct field
 type Field struct {
-	Name       string       // Struct field name
-	Column     string       // DB column name
-	Type       reflect.Type // Field type
-	Index      int          // Struct field index for fast access
-	NestedIdx  []int        // Nested field index for embedded structs
-	IsPK       bool         // Is primary key
-	IsAuto     bool         // Is auto-increment
-	AutoTime   bool         // Set time on insert
-	AutoUpdate bool         // Set time on update
-	IsUnique   bool         // Is unique index
-	Size       int          // Varchar size
-	NotNull    bool         // Is not null
-	Default    string       // Default value
-	SQLType    string       // Custom SQL type from tag
-	Tag        string       // Raw tag string
-	Accessor   Accessor     // Pre-generated field accessor
+	Name        string       // Struct field name
+	Column      string       // DB column name
+	Type        reflect.Type // Field type
+	Index       int          // Struct field index for fast access
+	NestedIdx   []int        // Nested field index for embedded structs
+	IsPK        bool         // Is primary key
+	IsAuto      bool         // Is auto-increment
+	AutoTime    bool         // Set time on insert
+	AutoUpdate  bool         // Set time on update
+	NoAutoTime  bool         // A zero time.Time is left to the column default on insert instead of set to now
+	ReadOnly    bool         // Written on insert only, never by Update
+	Generated   bool         // Computed by the database, never written
+	GeneratedAs string       // Expression of a generated column, used by CreateTableSQL
+	Comment     string       // Column comment emitted in the DDL
+	IsUnique    bool         // Is unique index
+	Indexed     bool         // Has a non-unique index (index tag)
+	IndexName   string       // Name of the index, empty for idx_<table>_<column>
+	IndexWhere  string       // Predicate making the field's index partial
+	Size        int          // Varchar size
+	NotNull     bool         // Is not null
+	Default     string       // Default value
+	DefaultFunc bool         // Default is evaluated by the database, e.g. CURRENT_TIMESTAMP
+	SQLType     string       // Custom SQL type from tag
+	IsArray     bool         // Slice stored as a Postgres array (type:array)
+	Enum        []string     // Allowed values (enum:a,b,c)
+	Tag         string       // Raw tag string
+	Accessor    Accessor     // Pre-generated field accessor
 }
@@ -27,3 +27,9 @@ type AfterDeleter interface{ AfterDelete() error }
 // AfterFinder is the interface for the AfterFind hook.
 // It is called after a record is retrieved from the database.
 type AfterFinder interface{ AfterFind() error }
+
+// DefaultScoper is the interface for models whose queries are always filtered, e.g. to a
+// tenant. DefaultScope is called once when the model is parsed and returns a condition,
+// such as "tenant_id = 1", that is ANDed into every query on the model unless
+// Query.Unscoped is called.
+type DefaultScoper interface{ DefaultScope() string }
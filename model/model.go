@@ -1,9 +1,13 @@
 package model
 
 import (
+	"database/sql"
+	"database/sql/driver"
 	"fmt"
 	"reflect"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 	"unicode"
 )
@@ -14,6 +18,8 @@ type Model struct {
 	Fields          []*Field
 	FieldMap        map[string]*Field
 	PKField         *Field
+	SoftDeleteField *Field // The deleted_at column, nil if the model has no soft delete
+	DefaultScope    string // Condition returned by the model's DefaultScope method, see DefaultScoper
 	Relations       map[string]*Relation
 	OriginalType    reflect.Type
 	HasBeforeInsert bool
@@ -30,6 +36,16 @@ func (m *Model) GetRelation(name string) (*Relation, error) {
 	return GetRelation(m, name)
 }
 
+// Columns returns the column names of the model's fields in declaration order, with the
+// fields of embedded structs in place. Relations and ignored fields are not included.
+func (m *Model) Columns() []string {
+	columns := make([]string, len(m.Fields))
+	for i, f := range m.Fields {
+		columns[i] = f.Column
+	}
+	return columns
+}
+
 var (
 	beforeInserterType = reflect.TypeOf((*BeforeInserter)(nil)).Elem()
 	afterInserterType  = reflect.TypeOf((*AfterInserter)(nil)).Elem()
@@ -91,6 +107,10 @@ func parseModel(typ reflect.Type) (*Model, error) {
 		OriginalType: typ,
 	}
 
+	if ds, ok := val.(DefaultScoper); ok {
+		m.DefaultScope = ds.DefaultScope()
+	}
+
 	ptrType := reflect.PtrTo(typ)
 	m.HasBeforeInsert = ptrType.Implements(beforeInserterType)
 	m.HasAfterInsert = ptrType.Implements(afterInserterType)
@@ -141,15 +161,21 @@ func (m *Model) parseFields(typ reflect.Type, baseIndex []int) error {
 			continue
 		}
 
-		if structField.Type.Kind() == reflect.Slice || structField.Type.Kind() == reflect.Map {
+		isArray := structField.Type.Kind() == reflect.Slice && isArrayType(tag.Type)
+		// Types with their own sql.Scanner and driver.Valuer (decimals, JSON documents, ...)
+		// are columns whatever their kind and are passed to the driver as is
+		isValuer := isScannerValuer(structField.Type)
+		if (structField.Type.Kind() == reflect.Slice || structField.Type.Kind() == reflect.Map) && !isValuer {
 			if structField.Type.Kind() == reflect.Slice && structField.Type.Elem().Kind() == reflect.Uint8 {
 				// Allow []byte for blob/binary
+			} else if isArray {
+				// Allow slices tagged type:array (Postgres arrays)
 			} else {
 				continue
 			}
 		}
 
-		if structField.Type.Kind() == reflect.Ptr {
+		if structField.Type.Kind() == reflect.Ptr && !isValuer {
 			elemType := structField.Type.Elem()
 			if elemType.Kind() == reflect.Slice || elemType.Kind() == reflect.Map {
 				continue
@@ -162,7 +188,7 @@ func (m *Model) parseFields(typ reflect.Type, baseIndex []int) error {
 
 		columnName := tag.Column
 		if columnName == "" {
-			columnName = camelToSnake(structField.Name)
+			columnName = columnNameOf(structField.Name)
 		}
 
 		// Calculate nested index
@@ -171,21 +197,36 @@ func (m *Model) parseFields(typ reflect.Type, baseIndex []int) error {
 		index = append(index, i)
 
 		field := &Field{
-			Name:       structField.Name,
-			Column:     columnName,
-			Type:       structField.Type,
-			Index:      i,
-			NestedIdx:  index,
-			IsPK:       tag.PrimaryKey,
-			IsAuto:     tag.AutoInc,
-			AutoTime:   tag.AutoTime,
-			AutoUpdate: tag.AutoUpdate,
-			IsUnique:   tag.Unique,
-			Size:       tag.Size,
-			NotNull:    tag.NotNull,
-			Default:    tag.Default,
-			SQLType:    tag.Type,
-			Tag:        tagStr,
+			Name:        structField.Name,
+			Column:      columnName,
+			Type:        structField.Type,
+			Index:       i,
+			NestedIdx:   index,
+			IsPK:        tag.PrimaryKey,
+			IsAuto:      tag.AutoInc,
+			AutoTime:    tag.AutoTime,
+			AutoUpdate:  tag.AutoUpdate,
+			NoAutoTime:  tag.NoAutoTime,
+			ReadOnly:    tag.ReadOnly,
+			Generated:   tag.Generated,
+			GeneratedAs: tag.GeneratedAs,
+			Comment:     tag.Comment,
+			Indexed:     tag.Indexed,
+			IndexName:   tag.IndexName,
+			IndexWhere:  tag.IndexWhere,
+			IsUnique:    tag.Unique,
+			Size:        tag.Size,
+			NotNull:     tag.NotNull,
+			Default:     tag.Default,
+			DefaultFunc: tag.DefaultFunc,
+			SQLType:     tag.Type,
+			IsArray:     isArray,
+			Enum:        tag.Enum,
+			Tag:         tagStr,
+		}
+		if isArray && strings.EqualFold(tag.Type, "array") {
+			// Let the dialect derive the element type, e.g. []int64 -> bigint[]
+			field.SQLType = ""
 		}
 		field.Accessor = m.createAccessor(field.NestedIdx)
 
@@ -199,10 +240,38 @@ func (m *Model) parseFields(typ reflect.Type, baseIndex []int) error {
 		if field.IsPK {
 			m.PKField = field
 		}
+		if isSoftDeleteField(field) {
+			m.SoftDeleteField = field
+		}
 	}
 	return nil
 }
 
+// isSoftDeleteField reports whether f is a DeletedAt field or deleted_at column of type
+// *time.Time. A time.Time field cannot hold the NULL of active rows, as zero times are
+// filled with the current time on insert, so it is a plain column.
+func isSoftDeleteField(f *Field) bool {
+	if f.Name != "DeletedAt" && !strings.EqualFold(f.Column, "deleted_at") {
+		return false
+	}
+	return f.Type == reflect.TypeOf((*time.Time)(nil))
+}
+
+var (
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	valuerType  = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+)
+
+// isScannerValuer reports whether t (or the type it points to) reads itself with
+// sql.Scanner and writes itself with driver.Valuer.
+func isScannerValuer(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	ptr := reflect.PointerTo(t)
+	return ptr.Implements(scannerType) && (t.Implements(valuerType) || ptr.Implements(valuerType))
+}
+
 func (m *Model) createAccessor(nestedIdx []int) Accessor {
 	return func(dest reflect.Value) reflect.Value {
 		f := dest
@@ -245,6 +314,76 @@ func isRelationField(typ reflect.Type) bool {
 	return false
 }
 
+// ColumnCase selects how column names are derived from struct field names that have
+// no column tag.
+type ColumnCase int32
+
+const (
+	// SnakeCase derives user_name from UserName. It is the default.
+	SnakeCase ColumnCase = iota
+	// UpperSnakeCase derives USER_NAME from UserName.
+	UpperSnakeCase
+	// CamelCase derives userName from UserName.
+	CamelCase
+	// FieldNameCase uses the field name unchanged, e.g. UserName.
+	FieldNameCase
+)
+
+var columnCase atomic.Int32
+
+// SetColumnCase sets the naming strategy for columns without a column tag. Cached model
+// metadata is discarded so later lookups derive columns with the new strategy; it is
+// meant to be called once at startup, before any queries run.
+func SetColumnCase(c ColumnCase) {
+	if ColumnCase(columnCase.Swap(int32(c))) == c {
+		return
+	}
+	modelCache.Range(func(key, _ any) bool {
+		modelCache.Delete(key)
+		return true
+	})
+	InvalidateRelationCache()
+}
+
+// GetColumnCase returns the current column naming strategy.
+func GetColumnCase() ColumnCase {
+	return ColumnCase(columnCase.Load())
+}
+
+// ColumnName returns the column a struct field named fieldName maps to when it has no
+// column tag, according to the column case.
+func ColumnName(fieldName string) string {
+	return columnNameOf(fieldName)
+}
+
+// columnNameOf derives the column name of a field according to the column case.
+func columnNameOf(fieldName string) string {
+	switch GetColumnCase() {
+	case UpperSnakeCase:
+		return strings.ToUpper(camelToSnake(fieldName))
+	case CamelCase:
+		snake := camelToSnake(fieldName)
+		var sb strings.Builder
+		upper := false
+		for _, r := range snake {
+			if r == '_' {
+				upper = true
+				continue
+			}
+			if upper {
+				r = unicode.ToUpper(r)
+				upper = false
+			}
+			sb.WriteRune(r)
+		}
+		return sb.String()
+	case FieldNameCase:
+		return fieldName
+	default:
+		return camelToSnake(fieldName)
+	}
+}
+
 func camelToSnake(s string) string {
 	if s == "ID" {
 		return "id"
@@ -263,6 +402,12 @@ func camelToSnake(s string) string {
 	return string(res)
 }
 
+// isArrayType reports whether a type tag marks a slice as an array column,
+// either generically (type:array) or with an explicit SQL type (type:text[]).
+func isArrayType(sqlType string) bool {
+	return strings.EqualFold(sqlType, "array") || strings.HasSuffix(sqlType, "[]")
+}
+
 func validateField(f *Field) error {
 	// Check AutoTime/AutoUpdate
 	if f.AutoTime || f.AutoUpdate {
@@ -275,6 +420,14 @@ func validateField(f *Field) error {
 		}
 	}
 
+	if f.NoAutoTime && (f.AutoTime || f.AutoUpdate) {
+		return fmt.Errorf("field %s has both no_auto_time and auto_time/auto_update tags", f.Name)
+	}
+
+	if f.Generated && (f.IsPK || f.IsAuto || f.Default != "") {
+		return fmt.Errorf("field %s is generated and cannot be a primary key or have a default", f.Name)
+	}
+
 	// Check IsAuto (Auto Increment)
 	if f.IsAuto {
 		t := f.Type
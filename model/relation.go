@@ -25,6 +25,7 @@ type Relation struct {
 	JoinTable  string       // 多对多中间表名
 	JoinFK     string       // 中间表外键（指向主表）
 	JoinRef    string       // 中间表引用键（指向关联表）
+	OrderBy    string       // 预加载默认排序（has_one/has_many）
 }
 
 type RelationConfig struct {
@@ -105,9 +106,10 @@ func parseRelationFromField(typ reflect.Type, field reflect.StructField) (*Relat
 	}
 
 	relation := &Relation{
-		Name:  field.Name,
-		Type:  relationType,
-		Model: nil,
+		Name:    field.Name,
+		Type:    relationType,
+		Model:   nil,
+		OrderBy: tag.Order,
 	}
 
 	switch relationType {
@@ -171,9 +173,10 @@ func parseRelation(m *Model, field *Field) (*Relation, error) {
 	}
 
 	relation := &Relation{
-		Name:  field.Name,
-		Type:  relationType,
-		Model: nil,
+		Name:    field.Name,
+		Type:    relationType,
+		Model:   nil,
+		OrderBy: tag.Order,
 	}
 
 	switch relationType {
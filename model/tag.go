@@ -14,9 +14,18 @@ type Tag struct {
 	Unique       bool
 	NotNull      bool
 	Default      string
+	DefaultFunc  bool // Default is a SQL expression such as CURRENT_TIMESTAMP, not a literal
 	Fk           string
 	AutoTime     bool
 	AutoUpdate   bool
+	NoAutoTime   bool
+	ReadOnly     bool
+	Generated    bool
+	GeneratedAs  string // Expression of a generated column, e.g. "(price * qty)"
+	Comment      string // Column comment, e.g. "comment:'Login email'"
+	Indexed      bool
+	IndexName    string // Name of the index, fields sharing it form a composite index
+	IndexWhere   string // Predicate of a partial index, e.g. "where:deleted_at IS NULL"
 	RelationType string
 	ForeignKey   string
 	References   string
@@ -24,6 +33,8 @@ type Tag struct {
 	JoinFK       string
 	JoinRef      string
 	Type         string
+	Enum         []string
+	Order        string // Default ORDER BY for preloaded relations, e.g. "created_at DESC"
 }
 
 // ParseTag parses the "jorm" tag string
@@ -33,10 +44,14 @@ func ParseTag(tagStr string) *Tag {
 		return tag
 	}
 
-	// Support space, semicolon, comma as separators (but keep comma in parens)
+	// Support space, semicolon, comma as separators (but keep comma in parens, in
+	// quoted strings and in enum value lists such as "enum:a,b,c"; an enum list ends
+	// at a comma followed by another option, as in "enum:a,b,notnull")
 	var sb strings.Builder
 	inParen := false
-	for _, r := range tagStr {
+	inQuote := false
+	segStart := 0
+	for i, r := range tagStr {
 		switch r {
 		case '(':
 			inParen = true
@@ -44,12 +59,20 @@ func ParseTag(tagStr string) *Tag {
 		case ')':
 			inParen = false
 			sb.WriteRune(r)
+		case '\'':
+			inQuote = !inQuote
+			sb.WriteRune(r)
 		case ';', ',':
-			if inParen {
+			inEnum := r == ',' && strings.HasPrefix(strings.ToLower(sb.String()[segStart:]), "enum:")
+			if inParen || inQuote || (inEnum && !isTagKey(nextTagPart(tagStr[i+1:]))) {
 				sb.WriteRune(r)
 			} else {
 				sb.WriteRune(' ')
+				segStart = sb.Len()
 			}
+		case ' ':
+			sb.WriteRune(r)
+			segStart = sb.Len()
 		default:
 			sb.WriteRune(r)
 		}
@@ -57,8 +80,8 @@ func ParseTag(tagStr string) *Tag {
 	tagStr = sb.String()
 	parts := strings.Fields(tagStr) // Use Fields to split by whitespace and ignore empty strings
 
-	for _, part := range parts {
-		part = strings.TrimSpace(part)
+	for i := 0; i < len(parts); i++ {
+		part := strings.TrimSpace(parts[i])
 		if part == "" {
 			continue
 		}
@@ -112,6 +135,7 @@ func ParseTag(tagStr string) *Tag {
 			}
 		case "default":
 			tag.Default = strings.TrimSpace(subParts[0])
+			tag.DefaultFunc = isDefaultFunc(tag.Default)
 		case "fk":
 			tag.Fk = strings.TrimSpace(subParts[0])
 			tag.ForeignKey = strings.TrimSpace(subParts[0])
@@ -119,8 +143,49 @@ func ParseTag(tagStr string) *Tag {
 			tag.AutoTime = true
 		case "auto_update":
 			tag.AutoUpdate = true
+		case "no_auto_time":
+			tag.NoAutoTime = true
+		case "readonly":
+			tag.ReadOnly = true
+		case "generated":
+			tag.Generated = true
+			tag.GeneratedAs = strings.TrimSpace(val)
+			// "generated:(first || ' ' || last)" is split at the spaces, join the
+			// expression back up to its closing parenthesis
+			for depth := strings.Count(val, "(") - strings.Count(val, ")"); depth > 0 && i+1 < len(parts); {
+				i++
+				tag.GeneratedAs += " " + parts[i]
+				depth += strings.Count(parts[i], "(") - strings.Count(parts[i], ")")
+			}
+		case "comment":
+			tag.Comment = val
+			// "comment:'Login email'" is split at the spaces, join the quoted text back
+			if strings.HasPrefix(val, "'") {
+				for (len(tag.Comment) < 2 || !strings.HasSuffix(tag.Comment, "'")) && i+1 < len(parts) {
+					i++
+					tag.Comment += " " + parts[i]
+				}
+				tag.Comment = strings.TrimSuffix(strings.TrimPrefix(tag.Comment, "'"), "'")
+			}
+		case "index":
+			tag.Indexed = true
+			tag.IndexName = strings.TrimSpace(subParts[0])
+		case "where":
+			tag.IndexWhere = val
+			// "where:deleted_at IS NULL" is split at the spaces, take the rest of the
+			// predicate back up to the next tag option
+			for i+1 < len(parts) && !isTagKey(parts[i+1]) {
+				i++
+				tag.IndexWhere += " " + parts[i]
+			}
 		case "type":
 			tag.Type = strings.TrimSpace(subParts[0])
+		case "enum":
+			for _, v := range strings.Split(subParts[0], ",") {
+				if v = strings.TrimSpace(v); v != "" {
+					tag.Enum = append(tag.Enum, v)
+				}
+			}
 		case "many2many", "many_to_many":
 			tag.RelationType = "many_to_many"
 			if val != "" {
@@ -140,7 +205,58 @@ func ParseTag(tagStr string) *Tag {
 			tag.JoinRef = strings.TrimSpace(subParts[0])
 		case "relation":
 			tag.RelationType = strings.TrimSpace(subParts[0])
+		case "order":
+			tag.Order = strings.TrimSpace(subParts[0])
+			// "order:created_at DESC" is split at the space, take the direction back
+			if i+1 < len(parts) && (strings.EqualFold(parts[i+1], "ASC") || strings.EqualFold(parts[i+1], "DESC")) {
+				tag.Order += " " + strings.ToUpper(parts[i+1])
+				i++
+			}
 		}
 	}
 	return tag
 }
+
+// tagKeys are the options of a jorm tag, used to find the end of a value spanning spaces.
+var tagKeys = map[string]bool{
+	"column": true, "pk": true, "auto": true, "unique": true, "notnull": true, "size": true,
+	"default": true, "fk": true, "auto_time": true, "auto_update": true, "no_auto_time": true,
+	"readonly": true, "generated": true, "comment": true, "index": true, "where": true,
+	"type": true, "enum": true, "many2many": true, "many_to_many": true, "has_one": true,
+	"has_many": true, "belongs_to": true, "foreignkey": true, "references": true,
+	"join_table": true, "join_fk": true, "join_ref": true, "relation": true, "order": true,
+}
+
+// nextTagPart returns the start of rest up to the next separator.
+func nextTagPart(rest string) string {
+	if end := strings.IndexAny(rest, " ;,"); end >= 0 {
+		return rest[:end]
+	}
+	return rest
+}
+
+// isTagKey reports whether part of a split tag starts a new option, e.g. "size:100" or "notnull".
+func isTagKey(part string) bool {
+	key, _, _ := strings.Cut(part, ":")
+	return tagKeys[strings.ToLower(key)]
+}
+
+// defaultKeywords are SQL default values evaluated by the database rather than literals.
+var defaultKeywords = map[string]bool{
+	"CURRENT_TIMESTAMP": true,
+	"CURRENT_DATE":      true,
+	"CURRENT_TIME":      true,
+	"LOCALTIMESTAMP":    true,
+	"LOCALTIME":         true,
+	"SYSDATE":           true,
+	"SYSTIMESTAMP":      true,
+}
+
+// isDefaultFunc reports whether a default value is a SQL keyword or function call
+// (e.g. CURRENT_TIMESTAMP, now(), (datetime('now'))) rather than a literal.
+func isDefaultFunc(v string) bool {
+	if defaultKeywords[strings.ToUpper(v)] {
+		return true
+	}
+	return strings.HasSuffix(v, ")") && !strings.HasPrefix(v, "'")
+}
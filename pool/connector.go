@@ -0,0 +1,133 @@
+package pool
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+)
+
+// NewConnector wraps base so that onConnect runs on every connection it opens, before the
+// connection is handed to the pool. If onConnect fails the connection is closed and the
+// error is returned to the caller that needed the connection.
+func NewConnector(base driver.Connector, onConnect func(ctx context.Context, conn *sql.Conn) error) driver.Connector {
+	return &initConnector{base: base, onConnect: onConnect}
+}
+
+// DSNConnector returns a driver.Connector opening connections with d and dsn, for drivers
+// that do not implement driver.DriverContext.
+func DSNConnector(d driver.Driver, dsn string) driver.Connector {
+	return dsnConnector{driver: d, dsn: dsn}
+}
+
+type dsnConnector struct {
+	driver driver.Driver
+	dsn    string
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+type initConnector struct {
+	base      driver.Connector
+	onConnect func(ctx context.Context, conn *sql.Conn) error
+}
+
+func (c *initConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.base.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.initConn(ctx, conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("OnConnect failed: %w", err)
+	}
+	return conn, nil
+}
+
+func (c *initConnector) Driver() driver.Driver {
+	return c.base.Driver()
+}
+
+// initConn runs onConnect on conn. The hook receives a *sql.Conn of a single-connection
+// sql.DB lent conn, which it cannot close.
+func (c *initConnector) initConn(ctx context.Context, conn driver.Conn) error {
+	db := sql.OpenDB(&lentConnector{conn: &lentConn{Conn: conn}, driver: c.base.Driver()})
+	defer db.Close()
+	sc, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer sc.Close()
+	return c.onConnect(ctx, sc)
+}
+
+// lentConnector hands out a single connection.
+type lentConnector struct {
+	conn   *lentConn
+	driver driver.Driver
+}
+
+func (c *lentConnector) Connect(context.Context) (driver.Conn, error) {
+	if c.conn == nil {
+		return nil, errors.New("OnConnect must use the connection it was given")
+	}
+	conn := c.conn
+	c.conn = nil
+	return conn, nil
+}
+
+func (c *lentConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// lentConn forwards to a connection owned by the pool, leaving it open on Close. The
+// optional driver interfaces are forwarded when the connection implements them.
+type lentConn struct {
+	driver.Conn
+}
+
+func (c *lentConn) Close() error {
+	return nil
+}
+
+func (c *lentConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return p.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c *lentConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if e, ok := c.Conn.(driver.ExecerContext); ok {
+		return e.ExecContext(ctx, query, args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (c *lentConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if q, ok := c.Conn.(driver.QueryerContext); ok {
+		return q.QueryContext(ctx, query, args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (c *lentConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return b.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+func (c *lentConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if n, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return n.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
@@ -12,11 +12,17 @@ type Pool interface {
 	SetMaxOpenConns(n int)
 	SetMaxIdleConns(n int)
 	SetConnMaxLifetime(d time.Duration)
+	SetConnMaxIdleTime(d time.Duration)
+	// Stats returns the pool statistics, including the configured MaxOpenConnections.
+	Stats() sql.DBStats
 	Ping() error
+	PingContext(ctx context.Context) error
 	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
 	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
 	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
 	Begin() (*sql.Tx, error)
+	// SQLDB returns the underlying *sql.DB.
+	SQLDB() *sql.DB
 }
 
 // StdPool is an implementation of Pool using the standard library's *sql.DB.
@@ -28,3 +34,8 @@ type StdPool struct {
 func NewStdPool(db *sql.DB) *StdPool {
 	return &StdPool{db}
 }
+
+// SQLDB returns the wrapped *sql.DB.
+func (p *StdPool) SQLDB() *sql.DB {
+	return p.DB
+}
@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
@@ -55,6 +56,57 @@ func TestBuilder(t *testing.T) {
 		b.Joins("INNER JOIN users; DROP TABLE users; --")
 	})
 
+	t.Run("ExpandSliceArg", func(t *testing.T) {
+		b := core.NewBuilder(d)
+		b.SetTable("users").Where("id IN (?)", []int{1, 2, 3}).Where("name = ?", "Ann").Where("age IN (?)", []int{})
+		sql, args := b.BuildSelect()
+
+		expectedSQL := "SELECT * FROM `users` WHERE (id IN (?, ?, ?)) AND (name = ?) AND (age IN (NULL))"
+		if sql != expectedSQL {
+			t.Errorf("Expected SQL: %s\nGot: %s", expectedSQL, sql)
+		}
+		if len(args) != 4 || args[0] != 1 || args[2] != 3 || args[3] != "Ann" {
+			t.Errorf("Invalid args: %v", args)
+		}
+
+		pg, _ := dialect.Get("postgres")
+		b = core.NewBuilder(pg)
+		b.SetTable("users").Where("id IN (?)", []int64{7, 8}).Where("data = ?", []byte("raw"))
+		sql, args = b.BuildSelect()
+		expectedSQL = `SELECT * FROM "users" WHERE (id IN ($1, $2)) AND (data = $3)`
+		if sql != expectedSQL {
+			t.Errorf("Expected SQL: %s\nGot: %s", expectedSQL, sql)
+		}
+		if len(args) != 3 {
+			t.Errorf("Expected []byte to stay a single arg, got %v", args)
+		}
+	})
+
+	t.Run("Err", func(t *testing.T) {
+		b := core.NewBuilder(d)
+		if err := b.Err(); !errors.Is(err, core.ErrNoTable) {
+			t.Errorf("Expected ErrNoTable, got %v", err)
+		}
+
+		b.SetTable("users")
+		if err := b.Err(); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+
+		b.WhereIn("id", 1)
+		if err := b.Err(); !errors.Is(err, core.ErrInvalidWhereIn) {
+			t.Errorf("Expected ErrInvalidWhereIn for scalar, got %v", err)
+		}
+
+		sub := core.NewBuilder(d)
+		sub.WhereIn("id", nil)
+		group := core.NewBuilder(d)
+		group.SetTable("users").WhereGroup(sub)
+		if err := group.Err(); !errors.Is(err, core.ErrInvalidWhereIn) {
+			t.Errorf("Expected ErrInvalidWhereIn from group, got %v", err)
+		}
+	})
+
 	t.Run("WhereIn", func(t *testing.T) {
 		b := core.NewBuilder(d)
 		b.SetTable("users").WhereIn("id", []int{1, 2, 3})
@@ -68,6 +120,132 @@ func TestBuilder(t *testing.T) {
 		}
 	})
 
+	t.Run("WhereGroup", func(t *testing.T) {
+		sub := core.NewBuilder(d)
+		sub.Where("b = ?", 2).OrWhere("c = ?", 3)
+
+		b := core.NewBuilder(d)
+		b.SetTable("users").Where("a = ?", 1).WhereGroup(sub).Where("d = ?", 4)
+		sql, args := b.BuildSelect()
+
+		expectedSQL := "SELECT * FROM `users` WHERE (a = ?) AND ((b = ?) OR (c = ?)) AND (d = ?)"
+		if sql != expectedSQL {
+			t.Errorf("Expected SQL: %s\nGot: %s", expectedSQL, sql)
+		}
+		if len(args) != 4 || args[0] != 1 || args[1] != 2 || args[2] != 3 || args[3] != 4 {
+			t.Errorf("Invalid args: %v", args)
+		}
+	})
+
+	t.Run("WhereExists", func(t *testing.T) {
+		pg, _ := dialect.Get("postgres")
+		sub := core.NewBuilder(pg)
+		sub.SetTable("orders").Select("1").Where("orders.user_id = users.id").Where("orders.amount > ?", 100)
+
+		b := core.NewBuilder(pg)
+		b.SetTable("users").Where("age > ?", 18).WhereExists(sub).Where("name <> ?", "x")
+		sql, args := b.BuildSelect()
+
+		expectedSQL := `SELECT * FROM "users" WHERE (age > $1) AND (EXISTS (SELECT 1 FROM "orders" WHERE (orders.user_id = users.id) AND (orders.amount > $2))) AND (name <> $3)`
+		if sql != expectedSQL {
+			t.Errorf("Expected SQL: %s\nGot: %s", expectedSQL, sql)
+		}
+		if len(args) != 3 || args[0] != 18 || args[1] != 100 || args[2] != "x" {
+			t.Errorf("Invalid args: %v", args)
+		}
+
+		notB := core.NewBuilder(d)
+		notB.SetTable("users").WhereNotExists(core.NewBuilder(d).SetTable("orders").Where("orders.user_id = users.id"))
+		notSQL, _ := notB.BuildSelect()
+		if notSQL != "SELECT * FROM `users` WHERE (NOT EXISTS (SELECT * FROM `orders` WHERE (orders.user_id = users.id)))" {
+			t.Errorf("Invalid NOT EXISTS SQL: %s", notSQL)
+		}
+	})
+
+	t.Run("FromSubQuery", func(t *testing.T) {
+		pg, _ := dialect.Get("postgres")
+		sub := core.NewBuilder(pg)
+		sub.SetTable("orders").Select("user_id", "SUM(amount) AS total").Where("status = ?", "paid").GroupBy("user_id")
+
+		b := core.NewBuilder(pg)
+		b.FromSubQuery(sub, "t").Where("t.total > ?", 100).OrderBy("t.total DESC").Limit(5)
+		sql, args := b.BuildSelect()
+
+		expectedSQL := `SELECT * FROM (SELECT user_id, SUM(amount) AS total FROM "orders" WHERE (status = $1) GROUP BY user_id) t WHERE (t.total > $2) ORDER BY t.total DESC LIMIT $3`
+		if sql != expectedSQL {
+			t.Errorf("Expected SQL: %s\nGot: %s", expectedSQL, sql)
+		}
+		if len(args) != 3 || args[0] != "paid" || args[1] != 100 || args[2] != 5 {
+			t.Errorf("Invalid args: %v", args)
+		}
+		if err := b.Err(); err != nil {
+			t.Errorf("Expected a derived table to satisfy the table check, got %v", err)
+		}
+	})
+
+	t.Run("SelectRaw", func(t *testing.T) {
+		pg, _ := dialect.Get("postgres")
+		b := core.NewBuilder(pg)
+		b.SetTable("users").Select("id").SelectRaw("COALESCE(name, ?) AS name", "unknown").
+			Joins("JOIN orders o ON o.user_id = users.id AND o.status = ?", "paid").
+			Where("age > ?", 18).Limit(10)
+		sql, args := b.BuildSelect()
+
+		expectedSQL := `SELECT id, COALESCE(name, $1) AS name FROM "users" JOIN orders o ON o.user_id = users.id AND o.status = $2 WHERE (age > $3) LIMIT $4`
+		if sql != expectedSQL {
+			t.Errorf("Expected SQL: %s\nGot: %s", expectedSQL, sql)
+		}
+		if len(args) != 4 || args[0] != "unknown" || args[1] != "paid" || args[2] != 18 || args[3] != 10 {
+			t.Errorf("Invalid args: %v", args)
+		}
+
+		clone := b.Clone()
+		if cloneSQL, cloneArgs := clone.BuildSelect(); cloneSQL != expectedSQL || len(cloneArgs) != 4 {
+			t.Errorf("Expected the clone to keep select args, got %s %v", cloneSQL, cloneArgs)
+		}
+	})
+
+	t.Run("WhereInTuple", func(t *testing.T) {
+		columns := []string{"user_id", "role_id"}
+		rows := [][]any{{1, 2}, {3, 4}}
+
+		b := core.NewBuilder(d)
+		b.SetTable("user_roles").WhereInTuple(columns, rows)
+		sql, args := b.BuildSelect()
+		if sql != "SELECT * FROM `user_roles` WHERE ((user_id, role_id) IN ((?, ?), (?, ?)))" {
+			t.Errorf("Invalid sqlite tuple IN SQL: %s", sql)
+		}
+		if len(args) != 4 || args[0] != 1 || args[1] != 2 || args[2] != 3 || args[3] != 4 {
+			t.Errorf("Invalid args: %v", args)
+		}
+
+		pg, _ := dialect.Get("postgres")
+		pgB := core.NewBuilder(pg)
+		pgB.SetTable("user_roles").Where("active = ?", true).WhereInTuple(columns, rows)
+		pgSQL, _ := pgB.BuildSelect()
+		if pgSQL != `SELECT * FROM "user_roles" WHERE (active = $1) AND ((user_id, role_id) IN (($2, $3), ($4, $5)))` {
+			t.Errorf("Invalid postgres tuple IN SQL: %s", pgSQL)
+		}
+
+		ms, _ := dialect.Get("sqlserver")
+		msB := core.NewBuilder(ms)
+		msB.SetTable("user_roles").WhereInTuple(columns, rows)
+		msSQL, msArgs := msB.BuildSelect()
+		if !strings.Contains(msSQL, "WHERE ((user_id = @p1 AND role_id = @p2) OR (user_id = @p3 AND role_id = @p4))") {
+			t.Errorf("Invalid sqlserver tuple fallback SQL: %s", msSQL)
+		}
+		if len(msArgs) != 4 {
+			t.Errorf("Invalid sqlserver args: %v", msArgs)
+		}
+
+		empty := core.NewBuilder(d)
+		empty.SetTable("user_roles").WhereInTuple(columns, nil)
+		emptySQL, emptyArgs := empty.BuildSelect()
+		if emptySQL != "SELECT * FROM `user_roles` WHERE (1 = 0)" || len(emptyArgs) != 0 {
+			t.Errorf("Invalid empty tuple IN SQL: %s %v", emptySQL, emptyArgs)
+		}
+	})
+
 	t.Run("Update", func(t *testing.T) {
 		b := core.NewBuilder(d)
 		b.SetTable("users").Where("id = ?", 1)
@@ -101,4 +279,53 @@ func TestBuilder(t *testing.T) {
 			t.Errorf("Invalid args: %v", args)
 		}
 	})
+
+	t.Run("QuoteTable", func(t *testing.T) {
+		b := core.NewBuilder(d)
+		b.SetTable("main.users").Where("id = ?", 1)
+		if sql, _ := b.BuildSelect(); sql != "SELECT * FROM `main.users` WHERE (id = ?)" {
+			t.Errorf("Expected the table to be quoted by default, got %s", sql)
+		}
+
+		b = core.NewBuilder(d)
+		b.SetTable("`main`.`users` u").QuoteTable(false).Select("u.id").Where("u.id = ?", 1)
+		if sql, _ := b.BuildSelect(); sql != "SELECT u.id FROM `main`.`users` u WHERE (u.id = ?)" {
+			t.Errorf("Expected the table to be written as is, got %s", sql)
+		}
+		if sql, _ := b.Clone().BuildSelect(); !strings.Contains(sql, "FROM `main`.`users` u ") {
+			t.Errorf("Expected Clone to keep the quoting mode, got %s", sql)
+		}
+		if sql, _ := b.BuildUpdate(map[string]any{"name": "x"}); sql != "UPDATE `main`.`users` u SET `name` = ? WHERE (u.id = ?)" {
+			t.Errorf("Unexpected UPDATE: %s", sql)
+		}
+		if sql, _ := b.BuildDelete(); sql != "DELETE FROM `main`.`users` u WHERE (u.id = ?)" {
+			t.Errorf("Unexpected DELETE: %s", sql)
+		}
+		if sql, _ := b.QuoteTable(true).BuildDelete(); sql != "DELETE FROM ``main`.`users` u` WHERE (u.id = ?)" {
+			t.Errorf("Expected quoting to be restored, got %s", sql)
+		}
+	})
+	t.Run("TooManyParameters", func(t *testing.T) {
+		ids := make([]int, d.(dialect.PlaceholderLimiter).MaxPlaceholders()+1)
+		b := core.NewBuilder(d)
+		b.SetTable("users").WhereIn("id", ids)
+		if err := b.Err(); err != nil {
+			t.Fatalf("Expected no error before building, got %v", err)
+		}
+		b.BuildSelect()
+		if err := b.Err(); !errors.Is(err, core.ErrTooManyParameters) {
+			t.Errorf("Expected ErrTooManyParameters from BuildSelect, got %v", err)
+		}
+
+		b = core.NewBuilder(d)
+		b.SetTable("users").WhereIn("id", ids[:len(ids)-1])
+		b.BuildDelete()
+		if err := b.Err(); err != nil {
+			t.Errorf("Expected the limit itself to be accepted, got %v", err)
+		}
+		b.Where("name = ?", "x").BuildUpdate(map[string]any{"age": 1})
+		if err := b.Err(); !errors.Is(err, core.ErrTooManyParameters) {
+			t.Errorf("Expected ErrTooManyParameters from BuildUpdate, got %v", err)
+		}
+	})
 }
@@ -0,0 +1,345 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/shrek82/jorm/core"
+	"github.com/shrek82/jorm/middleware"
+)
+
+type CacheUser struct {
+	ID   int64  `jorm:"pk;auto"`
+	Name string `jorm:"size:100"`
+}
+
+func setupCacheDB(t *testing.T, dbFile string) *core.DB {
+	t.Helper()
+	_ = os.Remove(dbFile)
+
+	db, err := core.Open("sqlite3", dbFile, &core.Options{MaxOpenConns: 1})
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	if err := db.AutoMigrate(&CacheUser{}); err != nil {
+		db.Close()
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+	if _, err := db.Model(&CacheUser{}).Insert(&CacheUser{Name: "Alice"}); err != nil {
+		db.Close()
+		t.Fatalf("Insert failed: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+		_ = os.Remove(dbFile)
+	})
+	return db
+}
+
+func TestCacheWarm(t *testing.T) {
+	db := setupCacheDB(t, "cache_warm_test.db")
+	mem := middleware.NewMemoryCache()
+	db.Use(mem)
+
+	err := mem.Warm(context.Background(), db.Model(&CacheUser{}).Where("name = ?", "Alice"))
+	if err != nil {
+		t.Fatalf("Warm failed: %v", err)
+	}
+
+	// Change the underlying row without going through the cache
+	if _, err := db.Exec("UPDATE cache_user SET name = ? WHERE id = ?", "Bob", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	var users []CacheUser
+	if err := db.Model(&CacheUser{}).Where("name = ?", "Alice").Cache().Find(&users); err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 || users[0].Name != "Alice" {
+		t.Errorf("Expected warmed cache hit (Alice), got %v", users)
+	}
+
+	// Table queries have no model to warm into
+	if err := mem.Warm(context.Background(), db.Table("cache_user")); err == nil {
+		t.Error("Expected error warming a query without a model")
+	}
+}
+
+func TestCacheKeyQueryTags(t *testing.T) {
+	db := setupCacheDB(t, "cache_tags_test.db")
+	db.Use(middleware.NewMemoryCache())
+
+	acme := core.WithQueryTags(context.Background(), map[string]any{"tenant": "acme"})
+	globex := core.WithQueryTags(context.Background(), map[string]any{"tenant": "globex"})
+
+	var users []CacheUser
+	if err := db.Model(&CacheUser{}).WithContext(acme).Cache().Find(&users); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.Exec("UPDATE cache_user SET name = ? WHERE id = ?", "Bob", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// A different tenant must not be served the entry cached for acme
+	var other []CacheUser
+	if err := db.Model(&CacheUser{}).WithContext(globex).Cache().Find(&other); err != nil {
+		t.Fatal(err)
+	}
+	if len(other) != 1 || other[0].Name != "Bob" {
+		t.Errorf("Expected fresh result for globex, got %v", other)
+	}
+
+	var cached []CacheUser
+	if err := db.Model(&CacheUser{}).WithContext(acme).Cache().Find(&cached); err != nil {
+		t.Fatal(err)
+	}
+	if len(cached) != 1 || cached[0].Name != "Alice" {
+		t.Errorf("Expected cached result for acme, got %v", cached)
+	}
+}
+
+// countingMiddleware counts how many queries reach it and delays each one,
+// so concurrent callers overlap while the first is in flight.
+type countingMiddleware struct {
+	calls atomic.Int32
+	delay time.Duration
+}
+
+func (m *countingMiddleware) Name() string           { return "Counting" }
+func (m *countingMiddleware) Init(db *core.DB) error { return nil }
+func (m *countingMiddleware) Shutdown() error        { return nil }
+func (m *countingMiddleware) Process(ctx context.Context, query *core.Query, next core.QueryFunc) (*core.Result, error) {
+	m.calls.Add(1)
+	time.Sleep(m.delay)
+	return next(ctx, query)
+}
+
+func TestMemoryCacheSingleflight(t *testing.T) {
+	db := setupCacheDB(t, "cache_singleflight_test.db")
+	counter := &countingMiddleware{delay: 100 * time.Millisecond}
+	db.Use(middleware.NewMemoryCache(), counter)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var users []CacheUser
+			if err := db.Model(&CacheUser{}).Cache().Find(&users); err != nil {
+				errs <- err
+				return
+			}
+			if len(users) != 1 || users[0].Name != "Alice" {
+				errs <- fmt.Errorf("unexpected result: %v", users)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+	if n := counter.calls.Load(); n != 1 {
+		t.Errorf("Expected 1 underlying query, got %d", n)
+	}
+}
+
+// fixedCountMiddleware short-circuits queries with a fixed count result.
+type fixedCountMiddleware struct {
+	count int64
+}
+
+func (m *fixedCountMiddleware) Name() string           { return "FixedCount" }
+func (m *fixedCountMiddleware) Init(db *core.DB) error { return nil }
+func (m *fixedCountMiddleware) Shutdown() error        { return nil }
+func (m *fixedCountMiddleware) Process(ctx context.Context, query *core.Query, next core.QueryFunc) (*core.Result, error) {
+	return &core.Result{Data: m.count}, nil
+}
+
+func TestMemoryCacheCountPrecision(t *testing.T) {
+	db := setupCacheDB(t, "cache_count_test.db")
+
+	// 2^53 + 1 cannot be represented exactly as a float64
+	const large = int64(1<<53 + 1)
+	fixed := &fixedCountMiddleware{count: large}
+	db.Use(middleware.NewMemoryCache(), fixed)
+
+	count, err := db.Model(&CacheUser{}).Cache().Count()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != large {
+		t.Fatalf("Expected %d from source, got %d", large, count)
+	}
+
+	// Second call must be served from cache
+	fixed.count = 0
+	count, err = db.Model(&CacheUser{}).Cache().Count()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != large {
+		t.Errorf("Expected cached count %d, got %d", large, count)
+	}
+}
+
+func TestCacheTTLJitter(t *testing.T) {
+	db := setupCacheDB(t, "cache_jitter_test.db")
+	cacheDir := "./cache_jitter"
+	os.RemoveAll(cacheDir)
+	defer os.RemoveAll(cacheDir)
+
+	fileCache := middleware.NewFileCache(cacheDir, time.Hour)
+	fileCache.JitterFraction = 0.2
+	db.Use(fileCache)
+
+	// Write a burst of distinct entries
+	for i := 0; i < 10; i++ {
+		var users []CacheUser
+		if err := db.Model(&CacheUser{}).Where("id > ?", i).Cache().Find(&users); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, _ := filepath.Glob(filepath.Join(cacheDir, "*.json"))
+	if len(files) != 10 {
+		t.Fatalf("Expected 10 cache files, got %d", len(files))
+	}
+
+	var minExp, maxExp time.Time
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var entry struct {
+			ExpiresAt time.Time `json:"expires_at"`
+		}
+		if err := json.Unmarshal(data, &entry); err != nil {
+			t.Fatal(err)
+		}
+		if minExp.IsZero() || entry.ExpiresAt.Before(minExp) {
+			minExp = entry.ExpiresAt
+		}
+		if entry.ExpiresAt.After(maxExp) {
+			maxExp = entry.ExpiresAt
+		}
+		// ±20% of 1h
+		if d := time.Until(entry.ExpiresAt); d < 47*time.Minute || d > 73*time.Minute {
+			t.Errorf("Expiry %v outside jitter bounds", d)
+		}
+	}
+
+	if maxExp.Sub(minExp) < time.Second {
+		t.Errorf("Expected jittered expiry times to differ, spread was %v", maxExp.Sub(minExp))
+	}
+}
+
+func TestMemoryCacheLRU(t *testing.T) {
+	db := setupCacheDB(t, "cache_lru_test.db")
+	mem := middleware.NewMemoryCache(time.Hour)
+	mem.MaxEntries = 2
+	counter := &countingMiddleware{}
+	db.Use(mem, counter)
+
+	find := func(id int) {
+		t.Helper()
+		var users []CacheUser
+		if err := db.Model(&CacheUser{}).Where("id = ?", id).Cache().Find(&users); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	find(1) // miss: [1]
+	find(2) // miss: [2, 1]
+	find(1) // hit:  [1, 2]
+	if n := counter.calls.Load(); n != 2 {
+		t.Fatalf("Expected 2 DB queries before eviction, got %d", n)
+	}
+
+	find(3) // miss: [3, 1], evicts 2
+	find(1) // hit
+	if n := counter.calls.Load(); n != 3 {
+		t.Errorf("Expected entry 1 to remain cached, DB queries: %d", n)
+	}
+
+	find(2) // miss again, it was the least recently used
+	if n := counter.calls.Load(); n != 4 {
+		t.Errorf("Expected entry 2 to be evicted, DB queries: %d", n)
+	}
+}
+
+func TestCacheKeyFirstMap(t *testing.T) {
+	db := setupCacheDB(t, "cache_first_map_test.db")
+	counter := &countingMiddleware{}
+	db.Use(middleware.NewMemoryCache(time.Hour), counter)
+
+	findMaps := func() {
+		t.Helper()
+		var rows []map[string]any
+		if err := db.Model(&CacheUser{}).Cache().FindMaps(&rows); err != nil {
+			t.Fatal(err)
+		}
+		if len(rows) != 1 {
+			t.Fatalf("Expected 1 row, got %v", rows)
+		}
+	}
+
+	findMaps()
+	for i := 0; i < 2; i++ {
+		var row map[string]any
+		if err := db.Model(&CacheUser{}).Cache().FirstMap(&row); err != nil {
+			t.Fatal(err)
+		}
+		if row["name"] != "Alice" {
+			t.Errorf("Expected Alice, got %v", row)
+		}
+	}
+	// FirstMap has its own entry, which does not clash with the one of FindMaps
+	findMaps()
+	if n := counter.calls.Load(); n != 2 {
+		t.Errorf("Expected 2 DB queries, got %d", n)
+	}
+}
+
+func TestCacheKeyAggregates(t *testing.T) {
+	db := setupCacheDB(t, "cache_aggregates_test.db")
+	counter := &countingMiddleware{}
+	db.Use(middleware.NewMemoryCache(time.Hour), counter)
+
+	var users []CacheUser
+	if err := db.Model(&CacheUser{}).Cache().Find(&users); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		count, err := db.Model(&CacheUser{}).Cache().Count()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 1 {
+			t.Errorf("Expected count 1, got %d", count)
+		}
+		sum, err := db.Model(&CacheUser{}).Cache().Sum("id")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sum != 1 {
+			t.Errorf("Expected sum 1, got %v", sum)
+		}
+	}
+	// Find, Count and Sum each have their own entry
+	if n := counter.calls.Load(); n != 3 {
+		t.Errorf("Expected 3 DB queries, got %d", n)
+	}
+}
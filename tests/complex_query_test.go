@@ -1,7 +1,9 @@
 package tests
 
 import (
+	"errors"
 	"os"
+	"strings"
 	"testing"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -14,11 +16,23 @@ type ComplexUser struct {
 	Age  int    `jorm:"column:age"`
 }
 
+type ComplexOrder struct {
+	ID     int64   `jorm:"pk;auto"`
+	UserID int64   `jorm:"column:user_id"`
+	Amount float64 `jorm:"column:amount"`
+}
+
 type AgeGroup struct {
 	Age   int `jorm:"column:age"`
 	Count int `jorm:"column:user_count"`
 }
 
+type RankedUser struct {
+	Name string
+	Age  int
+	Rn   int64
+}
+
 func TestComplexQuery(t *testing.T) {
 	dbFile := "complex_query_test.db"
 	defer os.Remove(dbFile)
@@ -107,4 +121,430 @@ func TestComplexQuery(t *testing.T) {
 			}
 		}
 	})
+	t.Run("WhereGroup", func(t *testing.T) {
+		q := db.Table("complex_user").
+			Where("age = ?", 30).
+			WhereGroup(func(g *core.Query) {
+				g.Where("name = ?", "User3").OrWhere("name = ?", "User5")
+			}).
+			OrderBy("id")
+
+		sqlStr, args := q.GetSelectSQL()
+		expectedSQL := "SELECT * FROM `complex_user` WHERE (age = ?) AND ((name = ?) OR (name = ?)) ORDER BY id"
+		if sqlStr != expectedSQL {
+			t.Errorf("Expected SQL: %s\nGot: %s", expectedSQL, sqlStr)
+		}
+		if len(args) != 3 || args[0] != 30 || args[1] != "User3" || args[2] != "User5" {
+			t.Errorf("Invalid args order: %v", args)
+		}
+
+		var results []ComplexUser
+		if err := q.Find(&results); err != nil {
+			t.Fatalf("WhereGroup query failed: %v", err)
+		}
+		if len(results) != 2 || results[0].Name != "User3" || results[1].Name != "User5" {
+			t.Errorf("Unexpected WhereGroup results: %+v", results)
+		}
+	})
+	t.Run("WhereRaw", func(t *testing.T) {
+		plain := db.Table("complex_user").Where("age = ? AND name <> ?", 30, "User1")
+		raw := db.Table("complex_user").WhereRaw("age = ? AND name <> ?", 30, "User1")
+
+		plainSQL, plainArgs := plain.GetSelectSQL()
+		rawSQL, rawArgs := raw.GetSelectSQL()
+		if rawSQL != plainSQL {
+			t.Errorf("Expected SQL: %s\nGot: %s", plainSQL, rawSQL)
+		}
+		if len(rawArgs) != len(plainArgs) || rawArgs[0] != 30 || rawArgs[1] != "User1" {
+			t.Errorf("Invalid args: %v", rawArgs)
+		}
+
+		if plain.HasRawSQL() {
+			t.Error("Where should not mark the query as raw")
+		}
+		if !raw.HasRawSQL() {
+			t.Error("WhereRaw should mark the query as raw")
+		}
+		if !raw.Clone().HasRawSQL() {
+			t.Error("Clone should keep the raw flag")
+		}
+
+		grouped := db.Table("complex_user").WhereGroup(func(g *core.Query) {
+			g.WhereRaw("age > ?", 10)
+		})
+		if !grouped.HasRawSQL() {
+			t.Error("WhereRaw inside WhereGroup should mark the query as raw")
+		}
+	})
+
+	t.Run("CountDistinct", func(t *testing.T) {
+		count, err := db.Table("complex_user").CountDistinct("age")
+		if err != nil {
+			t.Fatalf("CountDistinct failed: %v", err)
+		}
+		if count != 3 {
+			t.Errorf("Expected 3 distinct ages, got %d", count)
+		}
+
+		count, err = db.Table("complex_user").Where("age > ?", 20).CountDistinct("age")
+		if err != nil {
+			t.Fatalf("CountDistinct with where failed: %v", err)
+		}
+		if count != 2 {
+			t.Errorf("Expected 2 distinct ages over 20, got %d", count)
+		}
+
+		count, err = db.Table("complex_user").CountDistinct("complex_user.age")
+		if err != nil {
+			t.Fatalf("CountDistinct with a qualified column failed: %v", err)
+		}
+		if count != 3 {
+			t.Errorf("Expected 3 distinct qualified ages, got %d", count)
+		}
+	})
+
+	t.Run("GroupConcat", func(t *testing.T) {
+		var names []string
+		if err := db.Table("complex_user").Where("age = ?", 30).GroupConcat("name", ",", &names); err != nil {
+			t.Fatalf("GroupConcat failed: %v", err)
+		}
+		if len(names) != 1 || names[0] != "User3,User4,User5" {
+			t.Errorf("Unexpected GroupConcat result: %v", names)
+		}
+
+		var grouped []string
+		if err := db.Table("complex_user").GroupBy("age").OrderBy("age").GroupConcat("name", " | ", &grouped); err != nil {
+			t.Fatalf("GroupConcat with GroupBy failed: %v", err)
+		}
+		expected := []string{"User1 | User2", "User3 | User4 | User5", "User6"}
+		if len(grouped) != len(expected) {
+			t.Fatalf("Expected %d groups, got %v", len(expected), grouped)
+		}
+		for i := range expected {
+			if grouped[i] != expected[i] {
+				t.Errorf("Group %d: expected %q, got %q", i, expected[i], grouped[i])
+			}
+		}
+
+		var empty []string
+		if err := db.Table("complex_user").Where("age > ?", 100).GroupConcat("name", ",", &empty); err != nil {
+			t.Fatalf("GroupConcat on empty set failed: %v", err)
+		}
+		if len(empty) != 0 {
+			t.Errorf("Expected no values for empty set, got %v", empty)
+		}
+	})
+
+	t.Run("FindMaps", func(t *testing.T) {
+		var rows []map[string]any
+		err := db.Table("complex_user").
+			Select("age", "COUNT(*) as user_count", "MAX(name) as last_name").
+			GroupBy("age").
+			OrderBy("age").
+			FindMaps(&rows)
+		if err != nil {
+			t.Fatalf("FindMaps failed: %v", err)
+		}
+		if len(rows) != 3 {
+			t.Fatalf("Expected 3 groups, got %d: %v", len(rows), rows)
+		}
+
+		expected := []struct {
+			age, count int64
+			lastName   string
+		}{{20, 2, "User2"}, {30, 3, "User5"}, {40, 1, "User6"}}
+		for i, e := range expected {
+			row := rows[i]
+			if len(row) != 3 {
+				t.Errorf("Row %d: expected 3 keys, got %v", i, row)
+			}
+			if row["age"] != e.age || row["user_count"] != e.count || row["last_name"] != e.lastName {
+				t.Errorf("Row %d: expected %+v, got %v", i, e, row)
+			}
+		}
+	})
+
+	t.Run("FirstMap", func(t *testing.T) {
+		var row map[string]any
+		if err := db.Table("complex_user").Where("name = ?", "User4").FirstMap(&row); err != nil {
+			t.Fatalf("FirstMap failed: %v", err)
+		}
+		if row["name"] != "User4" || row["age"] != int64(30) {
+			t.Errorf("Unexpected row: %v", row)
+		}
+
+		var raw map[string]any
+		if err := db.Raw("SELECT SUM(age) AS total FROM complex_user").FirstMap(&raw); err != nil {
+			t.Fatalf("FirstMap on raw query failed: %v", err)
+		}
+		if raw["total"] != int64(170) {
+			t.Errorf("Expected total 170, got %v", raw["total"])
+		}
+
+		var missing map[string]any
+		err := db.Table("complex_user").Where("name = ?", "Nobody").FirstMap(&missing)
+		if !errors.Is(err, core.ErrRecordNotFound) {
+			t.Errorf("Expected ErrRecordNotFound, got %v", err)
+		}
+	})
+
+	t.Run("SelectAs", func(t *testing.T) {
+		type AgeSummary struct {
+			Age        int
+			TotalUsers int64
+			Oldest     string `jorm:"column:max_name"`
+		}
+
+		q := db.Table("complex_user").
+			Select("age").
+			SelectAs("COUNT(*)", "totalUsers").
+			SelectAs("MAX(name)", "oldest").
+			GroupBy("age").
+			OrderBy("age")
+
+		sqlStr, _ := q.GetSelectSQL()
+		expectedSQL := "SELECT age, COUNT(*) AS `totalUsers`, MAX(name) AS `oldest` FROM `complex_user` GROUP BY age ORDER BY age"
+		if sqlStr != expectedSQL {
+			t.Errorf("Expected SQL: %s\nGot: %s", expectedSQL, sqlStr)
+		}
+
+		var results []AgeSummary
+		if err := q.Find(&results); err != nil {
+			t.Fatalf("SelectAs query failed: %v", err)
+		}
+		if len(results) != 3 {
+			t.Fatalf("Expected 3 groups, got %d", len(results))
+		}
+		if results[1].Age != 30 || results[1].TotalUsers != 3 || results[1].Oldest != "User5" {
+			t.Errorf("Unexpected aliased scan result: %+v", results[1])
+		}
+	})
+
+	t.Run("SelectAggregates", func(t *testing.T) {
+		type AgeStats struct {
+			TotalAge float64
+			Avg      float64
+			Cnt      int64
+		}
+
+		q := db.Table("complex_user").
+			SelectSum("age", "total_age").
+			SelectAvg("age", "avg").
+			SelectCount("cnt").
+			Where("age >= ?", 30)
+
+		sqlStr, _ := q.GetSelectSQL()
+		expectedSQL := "SELECT SUM(`age`) AS `total_age`, AVG(`age`) AS `avg`, COUNT(*) AS `cnt` FROM `complex_user` WHERE (age >= ?)"
+		if sqlStr != expectedSQL {
+			t.Errorf("Expected SQL: %s\nGot: %s", expectedSQL, sqlStr)
+		}
+
+		var stats []AgeStats
+		if err := q.Find(&stats); err != nil {
+			t.Fatalf("Aggregate query failed: %v", err)
+		}
+		if len(stats) != 1 || stats[0].TotalAge != 130 || stats[0].Avg != 32.5 || stats[0].Cnt != 4 {
+			t.Errorf("Unexpected aggregate scan result: %+v", stats)
+		}
+	})
+
+	t.Run("SelectRaw", func(t *testing.T) {
+		type AgeLabel struct {
+			Name  string
+			Label string
+		}
+		var labels []AgeLabel
+		err := db.Table("complex_user").Select("name").
+			SelectRaw("CASE WHEN age >= ? THEN ? ELSE ? END AS label", 30, "senior", "junior").
+			Where("name IN (?, ?)", "User1", "User6").OrderBy("name").Find(&labels)
+		if err != nil {
+			t.Fatalf("SelectRaw query failed: %v", err)
+		}
+		if len(labels) != 2 || labels[0].Label != "junior" || labels[1].Label != "senior" {
+			t.Errorf("Unexpected labels: %+v", labels)
+		}
+	})
+
+	t.Run("FromSubQuery", func(t *testing.T) {
+		groups := db.Table("complex_user").Select("age", "COUNT(*) AS user_count").
+			Where("name <> ?", "User5").GroupBy("age")
+		q := db.FromSubQuery(groups, "g").Where("g.user_count >= ?", 2).OrderBy("g.age")
+
+		sqlStr, args := q.GetSelectSQL()
+		expectedSQL := "SELECT * FROM (SELECT age, COUNT(*) AS user_count FROM `complex_user` WHERE (name <> ?) GROUP BY age) g WHERE (g.user_count >= ?) ORDER BY g.age"
+		if sqlStr != expectedSQL {
+			t.Errorf("Expected SQL: %s\nGot: %s", expectedSQL, sqlStr)
+		}
+		if len(args) != 2 || args[0] != "User5" || args[1] != 2 {
+			t.Errorf("Expected subquery args first, got %v", args)
+		}
+
+		var results []AgeGroup
+		if err := q.Find(&results); err != nil {
+			t.Fatalf("FromSubQuery query failed: %v", err)
+		}
+		if len(results) != 2 || results[0].Age != 20 || results[1].Age != 30 || results[1].Count != 2 {
+			t.Errorf("Unexpected derived table results: %+v", results)
+		}
+	})
+
+	t.Run("FromSubQueryInvalid", func(t *testing.T) {
+		groups := db.Table("complex_user").Select("age").GroupBy("age")
+		var results []AgeGroup
+		err := db.FromSubQuery(groups, "g) x; --").Find(&results)
+		if !errors.Is(err, core.ErrInvalidQuery) {
+			t.Errorf("Expected ErrInvalidQuery for a bad alias, got %v", err)
+		}
+
+		raw := db.Raw("SELECT age FROM complex_user")
+		err = db.FromSubQuery(raw, "g").Find(&results)
+		if !errors.Is(err, core.ErrInvalidQuery) {
+			t.Errorf("Expected ErrInvalidQuery for a raw subquery, got %v", err)
+		}
+	})
+
+	t.Run("WhereExists", func(t *testing.T) {
+		if err := db.AutoMigrate(&ComplexOrder{}); err != nil {
+			t.Fatalf("AutoMigrate failed: %v", err)
+		}
+		var buyers []ComplexUser
+		if err := db.Table("complex_user").Where("name IN (?, ?)", "User2", "User6").Find(&buyers); err != nil {
+			t.Fatalf("Failed to load buyers: %v", err)
+		}
+		for i, u := range buyers {
+			order := &ComplexOrder{UserID: u.ID, Amount: float64(50 * (i + 1))}
+			if _, err := db.Model(order).Insert(order); err != nil {
+				t.Fatalf("Failed to insert order: %v", err)
+			}
+		}
+
+		orders := db.Table("complex_order").Select("1").Where("complex_order.user_id = complex_user.id")
+		q := db.Table("complex_user").Where("age >= ?", 20).WhereExists(orders).OrderBy("id")
+
+		sqlStr, _ := q.GetSelectSQL()
+		expectedSQL := "SELECT * FROM `complex_user` WHERE (age >= ?) AND (EXISTS (SELECT 1 FROM `complex_order` WHERE (complex_order.user_id = complex_user.id))) ORDER BY id"
+		if sqlStr != expectedSQL {
+			t.Errorf("Expected SQL: %s\nGot: %s", expectedSQL, sqlStr)
+		}
+
+		var results []ComplexUser
+		if err := q.Find(&results); err != nil {
+			t.Fatalf("WhereExists query failed: %v", err)
+		}
+		if len(results) != 2 || results[0].Name != "User2" || results[1].Name != "User6" {
+			t.Errorf("Unexpected WhereExists results: %+v", results)
+		}
+
+		bigOrders := db.Table("complex_order").Select("1").
+			Where("complex_order.user_id = complex_user.id").
+			Where("complex_order.amount > ?", 60)
+		var withoutBig []ComplexUser
+		if err := db.Table("complex_user").WhereNotExists(bigOrders).Where("age = ?", 40).Find(&withoutBig); err != nil {
+			t.Fatalf("WhereNotExists query failed: %v", err)
+		}
+		if len(withoutBig) != 0 {
+			t.Errorf("Expected User6 to be excluded by its large order, got %+v", withoutBig)
+		}
+
+		var noOrders []ComplexUser
+		if err := db.Table("complex_user").WhereNotExists(db.Table("complex_order").Where("complex_order.user_id = complex_user.id")).Find(&noOrders); err != nil {
+			t.Fatalf("WhereNotExists query failed: %v", err)
+		}
+		if len(noOrders) != 4 {
+			t.Errorf("Expected 4 users without orders, got %d", len(noOrders))
+		}
+	})
+
+	t.Run("WhereInTuple", func(t *testing.T) {
+		var results []ComplexUser
+		err := db.Table("complex_user").
+			WhereInTuple([]string{"name", "age"}, [][]any{{"User1", 20}, {"User4", 30}, {"User6", 30}}).
+			OrderBy("id").
+			Find(&results)
+		if err != nil {
+			t.Fatalf("WhereInTuple query failed: %v", err)
+		}
+		if len(results) != 2 || results[0].Name != "User1" || results[1].Name != "User4" {
+			t.Errorf("Unexpected WhereInTuple results: %+v", results)
+		}
+
+		err = db.Table("complex_user").WhereInTuple([]string{"name", "age"}, [][]any{{"User1"}}).Find(&results)
+		if !errors.Is(err, core.ErrInvalidQuery) {
+			t.Errorf("Expected ErrInvalidQuery for short row, got %v", err)
+		}
+	})
+
+	t.Run("Scopes", func(t *testing.T) {
+		adults := func(q *core.Query) *core.Query { return q.Where("age >= ?", 18) }
+		named := func(name string) func(*core.Query) *core.Query {
+			return func(q *core.Query) *core.Query { return q.Where("name = ?", name) }
+		}
+
+		q := db.Model(&ComplexUser{}).Scopes(adults, named("User1"))
+		sqlStr, args := q.GetSelectSQL()
+		if !strings.Contains(sqlStr, "(age >= ?) AND (name = ?)") {
+			t.Errorf("Expected both scope conditions in SQL, got %s", sqlStr)
+		}
+		if len(args) != 2 || args[0] != 18 || args[1] != "User1" {
+			t.Errorf("Unexpected args: %v", args)
+		}
+
+		var results []ComplexUser
+		if err := q.Find(&results); err != nil {
+			t.Fatalf("Scopes query failed: %v", err)
+		}
+		if len(results) != 1 || results[0].Name != "User1" {
+			t.Errorf("Unexpected Scopes results: %+v", results)
+		}
+	})
+
+	t.Run("WindowFunction", func(t *testing.T) {
+		var results []RankedUser
+		err := db.Table("complex_user").
+			Select("name", "age", "ROW_NUMBER() OVER (PARTITION BY age ORDER BY name DESC) AS rn", "1 AS unmatched").
+			OrderBy("age", "rn").
+			Find(&results)
+		if err != nil {
+			t.Fatalf("Window function query failed: %v", err)
+		}
+		expected := []RankedUser{
+			{"User2", 20, 1}, {"User1", 20, 2},
+			{"User5", 30, 1}, {"User4", 30, 2}, {"User3", 30, 3},
+			{"User6", 40, 1},
+		}
+		if len(results) != len(expected) {
+			t.Fatalf("Expected %d rows, got %d", len(expected), len(results))
+		}
+		for i, r := range results {
+			if r != expected[i] {
+				t.Errorf("Row %d: expected %+v, got %+v", i, expected[i], r)
+			}
+		}
+
+		var first RankedUser
+		err = db.Raw("SELECT name, ROW_NUMBER() OVER (ORDER BY age DESC, name) AS rn FROM complex_user ORDER BY rn").First(&first)
+		if err != nil || first.Name != "User6" || first.Rn != 1 {
+			t.Errorf("Expected User6 ranked first, got %+v (%v)", first, err)
+		}
+	})
+
+	t.Run("BuilderMisuse", func(t *testing.T) {
+		var results []ComplexUser
+		err := db.Table("").Where("age > ?", 20).Find(&results)
+		if !errors.Is(err, core.ErrNoTable) {
+			t.Errorf("Expected ErrNoTable from Find, got %v", err)
+		}
+		if _, err := db.Table("").Count(); !errors.Is(err, core.ErrNoTable) {
+			t.Errorf("Expected ErrNoTable from Count, got %v", err)
+		}
+
+		err = db.Table("complex_user").WhereIn("age", 25).Find(&results)
+		if !errors.Is(err, core.ErrInvalidWhereIn) {
+			t.Errorf("Expected ErrInvalidWhereIn for scalar, got %v", err)
+		}
+		_, err = db.Model(&ComplexUser{}).WhereIn("age", nil).Delete()
+		if !errors.Is(err, core.ErrInvalidWhereIn) {
+			t.Errorf("Expected ErrInvalidWhereIn from Delete, got %v", err)
+		}
+	})
 }
@@ -1,9 +1,11 @@
 package tests
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
+	"github.com/shrek82/jorm/core"
 	"github.com/shrek82/jorm/dialect"
 	"github.com/shrek82/jorm/model"
 )
@@ -50,3 +52,500 @@ func TestMySQLCreateTable(t *testing.T) {
 		t.Logf("Checking boolean default: %s", sql)
 	}
 }
+
+type DialectArrayModel struct {
+	ID     int64    `jorm:"pk;auto"`
+	Tags   []string `jorm:"type:array"`
+	Scores []int64  `jorm:"type:array"`
+}
+
+func TestPostgresArrayColumns(t *testing.T) {
+	d, ok := dialect.Get("postgres")
+	if !ok {
+		t.Fatal("postgres dialect not registered")
+	}
+
+	m, err := model.GetModel(&DialectArrayModel{})
+	if err != nil {
+		t.Fatalf("failed to get model: %v", err)
+	}
+
+	sql, _ := d.CreateTableSQL(m)
+	if !strings.Contains(sql, `"tags" text[]`) {
+		t.Errorf("Expected tags to be text[], got SQL: %s", sql)
+	}
+	if !strings.Contains(sql, `"scores" bigint[]`) {
+		t.Errorf("Expected scores to be bigint[], got SQL: %s", sql)
+	}
+}
+
+func TestGroupConcatSQL(t *testing.T) {
+	cases := map[string]string{
+		"mysql":     "GROUP_CONCAT(name SEPARATOR ', ')",
+		"sqlite3":   "GROUP_CONCAT(name, ', ')",
+		"postgres":  "STRING_AGG(CAST(name AS TEXT), ', ')",
+		"sqlserver": "STRING_AGG(CAST(name AS NVARCHAR(MAX)), ', ')",
+		"oracle":    "LISTAGG(name, ', ') WITHIN GROUP (ORDER BY name)",
+	}
+	for name, expected := range cases {
+		d, ok := dialect.Get(name)
+		if !ok {
+			t.Fatalf("%s dialect not registered", name)
+		}
+		if got := d.(dialect.GroupConcatSQLer).GroupConcatSQL("name", ", "); got != expected {
+			t.Errorf("%s: expected %s, got %s", name, expected, got)
+		}
+	}
+
+	d, _ := dialect.Get("sqlite3")
+	if got := d.(dialect.GroupConcatSQLer).GroupConcatSQL("name", "'"); got != "GROUP_CONCAT(name, '''')" {
+		t.Errorf("Separator should be escaped, got %s", got)
+	}
+}
+
+func TestJSONExtractSQL(t *testing.T) {
+	cases := map[string][2]string{
+		"mysql":     {"JSON_EXTRACT(attrs, '$.color')", "JSON_EXTRACT(attrs, '$.address.city')"},
+		"sqlite3":   {"JSON_EXTRACT(attrs, '$.color')", "JSON_EXTRACT(attrs, '$.address.city')"},
+		"postgres":  {"attrs->>'color'", "attrs#>>'{address,city}'"},
+		"sqlserver": {"JSON_VALUE(attrs, '$.color')", "JSON_VALUE(attrs, '$.address.city')"},
+		"oracle":    {"JSON_VALUE(attrs, '$.color')", "JSON_VALUE(attrs, '$.address.city')"},
+	}
+	for name, expected := range cases {
+		d, ok := dialect.Get(name)
+		if !ok {
+			t.Fatalf("%s dialect not registered", name)
+		}
+		if got := d.(dialect.JSONExtractSQLer).JSONExtractSQL("attrs", "color"); got != expected[0] {
+			t.Errorf("%s: expected %s, got %s", name, expected[0], got)
+		}
+		if got := d.(dialect.JSONExtractSQLer).JSONExtractSQL("attrs", "$.address.city"); got != expected[1] {
+			t.Errorf("%s: expected %s, got %s", name, expected[1], got)
+		}
+	}
+}
+
+func TestLimitOffsetSQL(t *testing.T) {
+	tests := []struct {
+		dialect   string
+		paged     string // Limit(10).Offset(20) without ORDER BY
+		pagedArgs []any
+		ordered   string // OrderBy("id").Limit(10)
+		orderArgs []any
+	}{
+		{"mysql", "SELECT * FROM `users` LIMIT ? OFFSET ?", []any{10, 20},
+			"SELECT * FROM `users` ORDER BY id LIMIT ?", []any{10}},
+		{"sqlite3", "SELECT * FROM `users` LIMIT ? OFFSET ?", []any{10, 20},
+			"SELECT * FROM `users` ORDER BY id LIMIT ?", []any{10}},
+		{"postgres", `SELECT * FROM "users" LIMIT $1 OFFSET $2`, []any{10, 20},
+			`SELECT * FROM "users" ORDER BY id LIMIT $1`, []any{10}},
+		{"sqlserver", "SELECT * FROM [users] ORDER BY (SELECT NULL) OFFSET @p1 ROWS FETCH NEXT @p2 ROWS ONLY", []any{20, 10},
+			"SELECT * FROM [users] ORDER BY id OFFSET @p1 ROWS FETCH NEXT @p2 ROWS ONLY", []any{0, 10}},
+		{"oracle", `SELECT * FROM "USERS" OFFSET :1 ROWS FETCH NEXT :2 ROWS ONLY`, []any{20, 10},
+			`SELECT * FROM "USERS" ORDER BY id OFFSET :1 ROWS FETCH NEXT :2 ROWS ONLY`, []any{0, 10}},
+	}
+	equalArgs := func(got, want []any) bool {
+		if len(got) != len(want) {
+			return false
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				return false
+			}
+		}
+		return true
+	}
+	for _, tt := range tests {
+		t.Run(tt.dialect, func(t *testing.T) {
+			d, ok := dialect.Get(tt.dialect)
+			if !ok {
+				t.Fatalf("%s dialect not registered", tt.dialect)
+			}
+			sql, args := core.NewBuilder(d).SetTable("users").Limit(10).Offset(20).BuildSelect()
+			if sql != tt.paged || !equalArgs(args, tt.pagedArgs) {
+				t.Errorf("expected %s %v, got %s %v", tt.paged, tt.pagedArgs, sql, args)
+			}
+			sql, args = core.NewBuilder(d).SetTable("users").OrderBy("id").Limit(10).BuildSelect()
+			if sql != tt.ordered || !equalArgs(args, tt.orderArgs) {
+				t.Errorf("expected %s %v, got %s %v", tt.ordered, tt.orderArgs, sql, args)
+			}
+			if sql, _ := core.NewBuilder(d).SetTable("users").BuildSelect(); strings.Contains(sql, "?") || strings.Contains(sql, "ORDER BY") {
+				t.Errorf("expected no paging clause without Limit/Offset, got %s", sql)
+			}
+		})
+	}
+}
+
+func TestSavepointSQL(t *testing.T) {
+	cases := map[string][3]string{
+		"mysql":     {"SAVEPOINT sp1", "ROLLBACK TO SAVEPOINT sp1", "RELEASE SAVEPOINT sp1"},
+		"sqlite3":   {"SAVEPOINT sp1", "ROLLBACK TO SAVEPOINT sp1", "RELEASE SAVEPOINT sp1"},
+		"postgres":  {"SAVEPOINT sp1", "ROLLBACK TO SAVEPOINT sp1", "RELEASE SAVEPOINT sp1"},
+		"sqlserver": {"SAVE TRANSACTION sp1", "ROLLBACK TRANSACTION sp1", ""},
+		"oracle":    {"SAVEPOINT sp1", "ROLLBACK TO SAVEPOINT sp1", ""},
+	}
+	for name, expected := range cases {
+		d, ok := dialect.Get(name)
+		if !ok {
+			t.Fatalf("%s dialect not registered", name)
+		}
+		sp := d.(dialect.SavepointSQLer)
+		got := [3]string{sp.SavepointSQL("sp1"), sp.RollbackToSQL("sp1"), sp.ReleaseSavepointSQL("sp1")}
+		if got != expected {
+			t.Errorf("%s: expected %q, got %q", name, expected, got)
+		}
+	}
+}
+
+func TestUpsertSQL(t *testing.T) {
+	cases := map[string]string{
+		"mysql":     "ON DUPLICATE KEY UPDATE `name` = VALUES(`name`), `age` = VALUES(`age`)",
+		"sqlite3":   "ON CONFLICT (`email`) DO UPDATE SET `name` = excluded.`name`, `age` = excluded.`age`",
+		"postgres":  `ON CONFLICT ("email") DO UPDATE SET "name" = excluded."name", "age" = excluded."age"`,
+		"sqlserver": "",
+		"oracle":    "",
+	}
+	for name, expected := range cases {
+		d, ok := dialect.Get(name)
+		if !ok {
+			t.Fatalf("%s dialect not registered", name)
+		}
+		if got := d.(dialect.UpsertSQLer).UpsertSQL([]string{"email"}, []string{"name", "age"}); got != expected {
+			t.Errorf("%s: expected %q, got %q", name, expected, got)
+		}
+	}
+
+	d, _ := dialect.Get("postgres")
+	if got := d.(dialect.UpsertSQLer).UpsertSQL([]string{"email"}, nil); got != `ON CONFLICT ("email") DO NOTHING` {
+		t.Errorf("Expected DO NOTHING without update columns, got %q", got)
+	}
+}
+
+type GeneratedPerson struct {
+	ID        int64  `jorm:"pk;auto"`
+	FirstName string `jorm:"size:50"`
+	LastName  string `jorm:"size:50"`
+	FullName  string `jorm:"generated:(first_name || ' ' || last_name)"`
+}
+
+func TestGeneratedColumnSQL(t *testing.T) {
+	m, err := model.GetModel(&GeneratedPerson{})
+	if err != nil {
+		t.Fatalf("failed to get model: %v", err)
+	}
+	if f := m.FieldMap["full_name"]; !f.Generated || f.GeneratedAs != "(first_name || ' ' || last_name)" {
+		t.Fatalf("Unexpected generated field: %+v", f)
+	}
+
+	cases := map[string]string{
+		"mysql":     "`full_name` varchar(255) GENERATED ALWAYS AS (first_name || ' ' || last_name)",
+		"sqlite3":   "`full_name` text GENERATED ALWAYS AS (first_name || ' ' || last_name)",
+		"postgres":  `"full_name" varchar(255) GENERATED ALWAYS AS (first_name || ' ' || last_name) STORED`,
+		"sqlserver": "[full_name] AS (first_name || ' ' || last_name)",
+		"oracle":    `"FULL_NAME" varchar2(255) GENERATED ALWAYS AS (first_name || ' ' || last_name) VIRTUAL`,
+	}
+	for name, expected := range cases {
+		d, ok := dialect.Get(name)
+		if !ok {
+			t.Fatalf("%s dialect not registered", name)
+		}
+		sql, _ := d.CreateTableSQL(m)
+		if !strings.Contains(sql, expected) {
+			t.Errorf("%s: expected %q in %s", name, expected, sql)
+		}
+	}
+
+	if _, err := model.GetModel(&struct {
+		ID    int64 `jorm:"pk;auto"`
+		Total int   `jorm:"generated:(1+1) default:0"`
+	}{}); err == nil {
+		t.Error("Expected a generated column with a default to be rejected")
+	}
+}
+
+func TestDateSQL(t *testing.T) {
+	cases := map[string]string{
+		"mysql":     "DATE(created_at)",
+		"sqlite3":   "DATE(created_at)",
+		"postgres":  "created_at::date",
+		"sqlserver": "CAST(created_at AS DATE)",
+		"oracle":    "TO_CHAR(created_at, 'YYYY-MM-DD')",
+	}
+	for name, expected := range cases {
+		d, ok := dialect.Get(name)
+		if !ok {
+			t.Fatalf("%s dialect not registered", name)
+		}
+		if got := d.(dialect.DateSQLer).DateSQL("created_at"); got != expected {
+			t.Errorf("%s: expected %q, got %q", name, expected, got)
+		}
+	}
+}
+
+type CommentedAccount struct {
+	ID    int64  `jorm:"pk;auto"`
+	Email string `jorm:"size:100 comment:'Login email, unique per tenant' notnull"`
+	Plan  string `jorm:"comment:tier"`
+}
+
+func TestColumnCommentSQL(t *testing.T) {
+	m, err := model.GetModel(&CommentedAccount{})
+	if err != nil {
+		t.Fatalf("failed to get model: %v", err)
+	}
+	email := m.FieldMap["email"]
+	if email.Comment != "Login email, unique per tenant" || !email.NotNull || m.FieldMap["plan"].Comment != "tier" {
+		t.Fatalf("Unexpected parsed comments: %q, %q", email.Comment, m.FieldMap["plan"].Comment)
+	}
+
+	d, _ := dialect.Get("mysql")
+	sql, _ := d.CreateTableSQL(m)
+	if !strings.Contains(sql, "`email` varchar(100) NOT NULL COMMENT 'Login email, unique per tenant'") {
+		t.Errorf("Expected an inline comment, got SQL: %s", sql)
+	}
+	if sql, _ := d.AddColumnSQL("commented_account", email); !strings.HasSuffix(sql, "COMMENT 'Login email, unique per tenant'") {
+		t.Errorf("Expected an inline comment when adding the column, got SQL: %s", sql)
+	}
+
+	cases := map[string]string{
+		"mysql":     "",
+		"sqlite3":   "",
+		"postgres":  `COMMENT ON COLUMN "commented_account"."email" IS 'Login email, unique per tenant'`,
+		"oracle":    `COMMENT ON COLUMN "COMMENTED_ACCOUNT"."EMAIL" IS 'Login email, unique per tenant'`,
+		"sqlserver": "EXEC sp_addextendedproperty 'MS_Description', 'Login email, unique per tenant', 'SCHEMA', 'dbo', 'TABLE', 'commented_account', 'COLUMN', 'email'",
+	}
+	for name, expected := range cases {
+		d, ok := dialect.Get(name)
+		if !ok {
+			t.Fatalf("%s dialect not registered", name)
+		}
+		if got := d.(dialect.ColumnCommentSQLer).ColumnCommentSQL("commented_account", email); got != expected {
+			t.Errorf("%s: expected %q, got %q", name, expected, got)
+		}
+		if got := d.(dialect.ColumnCommentSQLer).ColumnCommentSQL("commented_account", m.FieldMap["id"]); got != "" {
+			t.Errorf("%s: expected no statement without a comment, got %q", name, got)
+		}
+	}
+}
+
+func TestLikeSQL(t *testing.T) {
+	cases := map[string][2]string{
+		"mysql":     {"name LIKE ?", "LOWER(name) LIKE LOWER(?)"},
+		"postgres":  {"name LIKE ?", "name ILIKE ?"},
+		"sqlite3":   {`name LIKE ? ESCAPE '\'`, `LOWER(name) LIKE LOWER(?) ESCAPE '\'`},
+		"sqlserver": {`name LIKE ? ESCAPE '\'`, `LOWER(name) LIKE LOWER(?) ESCAPE '\'`},
+		"oracle":    {`name LIKE ? ESCAPE '\'`, `LOWER(name) LIKE LOWER(?) ESCAPE '\'`},
+	}
+	for name, expected := range cases {
+		d, ok := dialect.Get(name)
+		if !ok {
+			t.Fatalf("%s dialect not registered", name)
+		}
+		if got := d.(dialect.LikeSQLer).LikeSQL("name", false); got != expected[0] {
+			t.Errorf("%s: expected %q, got %q", name, expected[0], got)
+		}
+		if got := d.(dialect.LikeSQLer).LikeSQL("name", true); got != expected[1] {
+			t.Errorf("%s: expected %q for ILIKE, got %q", name, expected[1], got)
+		}
+	}
+}
+
+func TestPartialIndexSQL(t *testing.T) {
+	cases := map[string]string{
+		"postgres":  `CREATE INDEX "idx_active" ON "account" (email) WHERE deleted_at IS NULL`,
+		"sqlite3":   "CREATE INDEX `idx_active` ON `account` (email) WHERE deleted_at IS NULL",
+		"sqlserver": "CREATE INDEX [idx_active] ON [account] (email) WHERE deleted_at IS NULL",
+	}
+	for name, expected := range cases {
+		d, ok := dialect.Get(name)
+		if !ok {
+			t.Fatalf("%s dialect not registered", name)
+		}
+		p, ok := d.(dialect.PartialIndexSQLer)
+		if !ok {
+			t.Fatalf("%s: expected partial index support", name)
+		}
+		if got, _ := p.CreatePartialIndexSQL("account", "idx_active", []string{"email"}, false, "deleted_at IS NULL"); got != expected {
+			t.Errorf("%s: expected %q, got %q", name, expected, got)
+		}
+		if got, _ := d.CreateIndexSQL("account", "idx_active", []string{"email"}, false); strings.Contains(got, "WHERE") {
+			t.Errorf("%s: expected CreateIndexSQL to create a plain index, got %q", name, got)
+		}
+	}
+	for _, name := range []string{"mysql", "oracle"} {
+		d, _ := dialect.Get(name)
+		if _, ok := d.(dialect.PartialIndexSQLer); ok {
+			t.Errorf("%s: expected no partial index support", name)
+		}
+	}
+}
+
+func TestDialectCapabilities(t *testing.T) {
+	cases := map[string]dialect.DialectCapabilities{
+		"mysql": {Upsert: true, WindowFunctions: true, RowValues: true, ReleaseSavepoints: true,
+			BatchInsertIDs: true, ColumnComments: true},
+		"postgres": {Returning: true, Upsert: true, PartialIndexes: true, WindowFunctions: true,
+			RowValues: true, ReleaseSavepoints: true, ColumnComments: true},
+		"sqlite3": {Returning: true, Upsert: true, PartialIndexes: true, WindowFunctions: true,
+			RowValues: true, ReleaseSavepoints: true, BatchInsertIDs: true},
+		"sqlserver": {PartialIndexes: true, WindowFunctions: true, ColumnComments: true},
+		"oracle":    {WindowFunctions: true, RowValues: true, ColumnComments: true},
+	}
+	for name, expected := range cases {
+		d, ok := dialect.Get(name)
+		if !ok {
+			t.Fatalf("%s dialect not registered", name)
+		}
+		if got := d.(dialect.CapabilitiesReporter).Capabilities(); got != expected {
+			t.Errorf("%s: expected %+v, got %+v", name, expected, got)
+		}
+	}
+
+	db, err := core.Open("sqlite3", ":memory:", nil)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+	if got := db.Capabilities(); got != cases["sqlite3"] {
+		t.Errorf("Expected DB.Capabilities to report the sqlite3 set, got %+v", got)
+	}
+}
+
+func TestOrderByNullsSQL(t *testing.T) {
+	emulated := "CASE WHEN last_login IS NULL THEN 1 ELSE 0 END, last_login DESC"
+	cases := map[string]string{
+		"postgres":  "last_login DESC NULLS LAST",
+		"oracle":    "last_login DESC NULLS LAST",
+		"mysql":     emulated,
+		"sqlite3":   emulated,
+		"sqlserver": emulated,
+	}
+	for name, expected := range cases {
+		d, ok := dialect.Get(name)
+		if !ok {
+			t.Fatalf("%s dialect not registered", name)
+		}
+		if got := d.(dialect.OrderByNullsSQLer).OrderByNullsSQL("last_login", "DESC", false); got != expected {
+			t.Errorf("%s: expected %q, got %q", name, expected, got)
+		}
+	}
+	d, _ := dialect.Get("postgres")
+	if got := d.(dialect.OrderByNullsSQLer).OrderByNullsSQL("age", "ASC", true); got != "age ASC NULLS FIRST" {
+		t.Errorf("postgres: unexpected NULLS FIRST ordering %q", got)
+	}
+	d, _ = dialect.Get("mysql")
+	if got := d.(dialect.OrderByNullsSQLer).OrderByNullsSQL("age", "ASC", true); got != "CASE WHEN age IS NULL THEN 0 ELSE 1 END, age ASC" {
+		t.Errorf("mysql: unexpected NULLS FIRST ordering %q", got)
+	}
+}
+
+func TestCallProcSQL(t *testing.T) {
+	cases := map[string]string{
+		"mysql":     "CALL report(?, ?)",
+		"postgres":  "CALL report($1, $2)",
+		"sqlserver": "EXEC report @p1, @p2",
+		"oracle":    "BEGIN report(:1, :2); END;",
+		"sqlite3":   "",
+	}
+	for name, expected := range cases {
+		d, ok := dialect.Get(name)
+		if !ok {
+			t.Fatalf("%s dialect not registered", name)
+		}
+		if got := d.(dialect.CallProcSQLer).CallProcSQL("report", 2); got != expected {
+			t.Errorf("%s: expected %q, got %q", name, expected, got)
+		}
+	}
+	d, _ := dialect.Get("sqlserver")
+	if got := d.(dialect.CallProcSQLer).CallProcSQL("report", 0); got != "EXEC report" {
+		t.Errorf("sqlserver: unexpected call without arguments %q", got)
+	}
+}
+
+// baseDialect stands in for a dialect of another package implementing only dialect.Dialect:
+// embedding the interface hides the optional methods of the wrapped sqlite3 dialect.
+type baseDialect struct {
+	dialect.Dialect
+}
+
+type BaseDialectItem struct {
+	ID   int64  `jorm:"pk;auto"`
+	Name string `jorm:"size:50"`
+	Rank *int
+}
+
+func TestBaseDialectFallbacks(t *testing.T) {
+	sqlite, _ := dialect.Get("sqlite3")
+	dialect.Register("sqlite3", baseDialect{sqlite})
+	defer dialect.Register("sqlite3", sqlite)
+
+	db, err := core.Open("sqlite3", ":memory:", &core.Options{MaxOpenConns: 1})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+	if err := db.AutoMigrate(&BaseDialectItem{}); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+	one := 1
+	for _, item := range []*BaseDialectItem{{Name: "a_1"}, {Name: "b%2", Rank: &one}, {Name: "c_3"}} {
+		if _, err := db.Model(item).Insert(item); err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+	}
+	names := func(q *core.Query) []string {
+		t.Helper()
+		var items []BaseDialectItem
+		if err := q.Find(&items); err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		got := make([]string, len(items))
+		for i, item := range items {
+			got[i] = item.Name
+		}
+		return got
+	}
+
+	if got := names(db.Model(&BaseDialectItem{}).OrderBy("id").Limit(1).Offset(1)); len(got) != 1 || got[0] != "b%2" {
+		t.Errorf("Expected LIMIT/OFFSET paging, got %v", got)
+	}
+	if got := names(db.Model(&BaseDialectItem{}).WhereLike("name", "%"+core.EscapeLike("%")+"%")); len(got) != 1 || got[0] != "b%2" {
+		t.Errorf("Expected an escaped LIKE match, got %v", got)
+	}
+	if got := names(db.Model(&BaseDialectItem{}).OrderByNulls("rank", "ASC", "FIRST").OrderBy("id")); len(got) != 3 || got[2] != "b%2" {
+		t.Errorf("Expected NULL ranks first, got %v", got)
+	}
+	if got := names(db.Model(&BaseDialectItem{}).WhereInTuple([]string{"id", "name"}, [][]any{{1, "a_1"}, {3, "c_3"}})); len(got) != 2 {
+		t.Errorf("Expected two tuple matches, got %v", got)
+	}
+
+	err = db.Transaction(func(tx *core.Tx) error {
+		nested := tx.Transaction(func(tx *core.Tx) error {
+			if _, err := tx.Model(&BaseDialectItem{}).Where("id = ?", 1).Delete(); err != nil {
+				return err
+			}
+			return errors.New("roll back to the savepoint")
+		})
+		if nested == nil {
+			t.Error("Expected the nested transaction to fail")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Transaction failed: %v", err)
+	}
+	if count, _ := db.Model(&BaseDialectItem{}).Count(); count != 3 {
+		t.Errorf("Expected the savepoint rollback to keep all 3 rows, got %d", count)
+	}
+
+	var joined []string
+	if err := db.Model(&BaseDialectItem{}).GroupConcat("name", ",", &joined); !errors.Is(err, core.ErrInvalidQuery) {
+		t.Errorf("Expected ErrInvalidQuery from GroupConcat, got %v", err)
+	}
+	if err := db.Model(&BaseDialectItem{}).WhereJSON("name", "a", "=", 1).Find(&[]BaseDialectItem{}); !errors.Is(err, core.ErrInvalidQuery) {
+		t.Errorf("Expected ErrInvalidQuery from WhereJSON, got %v", err)
+	}
+	if got := db.Capabilities(); got != (dialect.DialectCapabilities{}) {
+		t.Errorf("Expected no capabilities, got %+v", got)
+	}
+}
@@ -1,11 +1,18 @@
 package tests
 
 import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/shrek82/jorm/core"
+	"github.com/shrek82/jorm/dialect"
+	"github.com/shrek82/jorm/model"
 )
 
 // HookUser supports hooks
@@ -48,6 +55,16 @@ type Product struct {
 	Category string
 }
 
+// KeyBase provides the primary key of KeyedItem through embedding.
+type KeyBase struct {
+	ID int64 `jorm:"pk;auto"`
+}
+
+type KeyedItem struct {
+	KeyBase
+	Name string `jorm:"size:100"`
+}
+
 func setupExtendedDB(t *testing.T) (*core.DB, func()) {
 	t.Helper()
 	dbFile := "extended_test.db"
@@ -117,6 +134,30 @@ func TestHooks(t *testing.T) {
 			t.Error("AfterFind hook was not called")
 		}
 	})
+
+	t.Run("RawFind", func(t *testing.T) {
+		var users []*HookUser
+		err := db.Raw("SELECT * FROM hook_users WHERE name IN (?, ?) ORDER BY id", "DefaultName", "AfterFindUser").Find(&users)
+		if err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		if len(users) != 2 || users[0].Name != "DefaultName" || users[1].Name != "AfterFindUser" {
+			t.Fatalf("Unexpected raw Find result: %+v", users)
+		}
+		for _, u := range users {
+			if !u.afterFindCalled {
+				t.Errorf("AfterFind hook was not called for %s", u.Name)
+			}
+		}
+
+		var first HookUser
+		if err := db.Raw("SELECT * FROM hook_users ORDER BY id DESC").First(&first); err != nil {
+			t.Fatalf("First failed: %v", err)
+		}
+		if first.Name != "AfterFindUser" || !first.afterFindCalled {
+			t.Errorf("Expected AfterFindUser with hook called, got %+v", first)
+		}
+	})
 }
 
 func TestEmbeddedStructs(t *testing.T) {
@@ -146,6 +187,25 @@ func TestEmbeddedStructs(t *testing.T) {
 			t.Errorf("Expected CreatedBy 'Admin', got '%s'", found.CreatedBy)
 		}
 	})
+
+	t.Run("ReloadEmbeddedPK", func(t *testing.T) {
+		if err := db.AutoMigrate(&KeyedItem{}); err != nil {
+			t.Fatalf("AutoMigrate failed: %v", err)
+		}
+		item := &KeyedItem{Name: "old"}
+		if _, err := db.Model(item).Insert(item); err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+		if _, err := db.Exec("UPDATE keyed_item SET name = ? WHERE id = ?", "new", item.ID); err != nil {
+			t.Fatalf("Raw update failed: %v", err)
+		}
+		if err := db.Model(item).Reload(item); err != nil {
+			t.Fatalf("Reload failed: %v", err)
+		}
+		if item.Name != "new" {
+			t.Errorf("Expected the reloaded name, got %+v", item)
+		}
+	})
 }
 
 func TestGroupByHaving(t *testing.T) {
@@ -241,3 +301,695 @@ func TestRawSQL(t *testing.T) {
 		}
 	})
 }
+
+type EnumOrder struct {
+	ID     int64  `jorm:"pk;auto"`
+	Status string `jorm:"size:20;enum:pending,shipped,delivered;notnull"`
+}
+
+func TestEnumField(t *testing.T) {
+	db, cleanup := setupExtendedDB(t)
+	defer cleanup()
+
+	m, err := model.GetModel(&EnumOrder{})
+	if err != nil {
+		t.Fatalf("GetModel failed: %v", err)
+	}
+	status := m.FieldMap["status"]
+	if len(status.Enum) != 3 || status.Enum[2] != "delivered" || !status.NotNull || status.Size != 20 {
+		t.Fatalf("Unexpected enum field metadata: %+v", status)
+	}
+
+	// With commas as separators the list ends at the next option
+	tag := model.ParseTag("size:20,enum:active,inactive,notnull,default:'active'")
+	if len(tag.Enum) != 2 || tag.Enum[1] != "inactive" || !tag.NotNull || tag.Size != 20 || tag.Default != "'active'" {
+		t.Errorf("Unexpected comma separated enum tag: %+v", tag)
+	}
+
+	d, _ := dialect.Get("sqlite3")
+	createSQL, _ := d.CreateTableSQL(m)
+	if !strings.Contains(createSQL, "CHECK (`status` IN ('pending', 'shipped', 'delivered'))") {
+		t.Errorf("Missing CHECK constraint: %s", createSQL)
+	}
+
+	if err := db.AutoMigrate(&EnumOrder{}); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+
+	t.Run("ValidValue", func(t *testing.T) {
+		order := &EnumOrder{Status: "shipped"}
+		if _, err := db.Model(order).Insert(order); err != nil {
+			t.Fatalf("Insert with valid enum failed: %v", err)
+		}
+		if _, err := db.Model(&EnumOrder{}).Where("id = ?", order.ID).Update(map[string]any{"status": "delivered"}); err != nil {
+			t.Fatalf("Update with valid enum failed: %v", err)
+		}
+	})
+
+	t.Run("InvalidValue", func(t *testing.T) {
+		order := &EnumOrder{Status: "lost"}
+		if _, err := db.Model(order).Insert(order); !errors.Is(err, core.ErrInvalidEnum) {
+			t.Fatalf("Expected ErrInvalidEnum on insert, got %v", err)
+		}
+		if _, err := db.Model(&EnumOrder{}).BatchInsert([]*EnumOrder{{Status: "pending"}, {Status: "lost"}}); !errors.Is(err, core.ErrInvalidEnum) {
+			t.Fatalf("Expected ErrInvalidEnum on batch insert, got %v", err)
+		}
+		if _, err := db.Model(&EnumOrder{}).Where("1 = 1").Update(map[string]any{"status": "lost"}); !errors.Is(err, core.ErrInvalidEnum) {
+			t.Fatalf("Expected ErrInvalidEnum on update, got %v", err)
+		}
+
+		// The CHECK constraint also rejects values written around the ORM
+		if _, err := db.Exec("INSERT INTO enum_order (status) VALUES ('lost')"); err == nil {
+			t.Fatal("Expected CHECK constraint to reject raw insert")
+		}
+
+		count, err := db.Model(&EnumOrder{}).Where("status = ?", "lost").Count()
+		if err != nil {
+			t.Fatalf("Count failed: %v", err)
+		}
+		if count != 0 {
+			t.Fatalf("Expected no invalid rows, got %d", count)
+		}
+	})
+}
+
+type DefaultFuncEvent struct {
+	ID        int64     `jorm:"pk;auto"`
+	Name      string    `jorm:"size:50"`
+	CreatedAt time.Time `jorm:"default:CURRENT_TIMESTAMP"`
+}
+
+func TestFunctionDefault(t *testing.T) {
+	db, cleanup := setupExtendedDB(t)
+	defer cleanup()
+
+	tag := model.ParseTag("default:current_timestamp")
+	if tag.Default != "current_timestamp" || !tag.DefaultFunc {
+		t.Errorf("Expected function default, got %+v", tag)
+	}
+	for _, literal := range []string{"0", "'pending'", "true", "'now()'"} {
+		if model.ParseTag("default:" + literal).DefaultFunc {
+			t.Errorf("%s should be a literal default", literal)
+		}
+	}
+	if !model.ParseTag("default:(datetime('now'))").DefaultFunc {
+		t.Error("Function call should be a function default")
+	}
+
+	m, err := model.GetModel(&DefaultFuncEvent{})
+	if err != nil {
+		t.Fatalf("GetModel failed: %v", err)
+	}
+	for name, expected := range map[string]string{
+		"sqlite3":  "`created_at` datetime DEFAULT CURRENT_TIMESTAMP",
+		"mysql":    "`created_at` datetime DEFAULT CURRENT_TIMESTAMP",
+		"postgres": `"created_at" timestamp with time zone DEFAULT CURRENT_TIMESTAMP`,
+	} {
+		d, _ := dialect.Get(name)
+		if createSQL, _ := d.CreateTableSQL(m); !strings.Contains(createSQL, expected) {
+			t.Errorf("%s: expected %q in %s", name, expected, createSQL)
+		}
+	}
+
+	if err := db.AutoMigrate(&DefaultFuncEvent{}); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+
+	t.Run("DatabaseFills", func(t *testing.T) {
+		event := &DefaultFuncEvent{Name: "db"}
+		q := db.Model(event)
+		if _, err := q.Insert(event); err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+		if strings.Contains(q.LastSQL, "created_at") {
+			t.Errorf("Insert should omit created_at: %s", q.LastSQL)
+		}
+		if !event.CreatedAt.IsZero() {
+			t.Errorf("Insert should not fill created_at locally, got %v", event.CreatedAt)
+		}
+
+		var got DefaultFuncEvent
+		if err := db.Model(&DefaultFuncEvent{}).Where("id = ?", event.ID).First(&got); err != nil {
+			t.Fatalf("First failed: %v", err)
+		}
+		if got.CreatedAt.IsZero() || time.Since(got.CreatedAt) > time.Hour || time.Since(got.CreatedAt) < -time.Hour {
+			t.Errorf("Expected database timestamp, got %v", got.CreatedAt)
+		}
+	})
+
+	t.Run("ExplicitValue", func(t *testing.T) {
+		at := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+		event := &DefaultFuncEvent{Name: "explicit", CreatedAt: at}
+		if _, err := db.Model(event).Insert(event); err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+		var got DefaultFuncEvent
+		if err := db.Model(&DefaultFuncEvent{}).Where("id = ?", event.ID).First(&got); err != nil {
+			t.Fatalf("First failed: %v", err)
+		}
+		if !got.CreatedAt.Equal(at) {
+			t.Errorf("Expected explicit %v, got %v", at, got.CreatedAt)
+		}
+	})
+
+	t.Run("BatchInsert", func(t *testing.T) {
+		events := []*DefaultFuncEvent{{Name: "batch1"}, {Name: "batch2"}}
+		q := db.Model(&DefaultFuncEvent{})
+		if _, err := q.BatchInsert(events); err != nil {
+			t.Fatalf("BatchInsert failed: %v", err)
+		}
+		if strings.Contains(q.LastSQL, "created_at") {
+			t.Errorf("BatchInsert should omit created_at: %s", q.LastSQL)
+		}
+
+		var got []DefaultFuncEvent
+		if err := db.Model(&DefaultFuncEvent{}).WhereIn("name", []string{"batch1", "batch2"}).Find(&got); err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		if len(got) != 2 {
+			t.Fatalf("Expected 2 events, got %d", len(got))
+		}
+		for _, e := range got {
+			if e.CreatedAt.IsZero() || time.Since(e.CreatedAt) > time.Hour || time.Since(e.CreatedAt) < -time.Hour {
+				t.Errorf("Expected database timestamp for %s, got %v", e.Name, e.CreatedAt)
+			}
+		}
+	})
+}
+
+type ReadOnlyAccount struct {
+	ID      int64  `jorm:"pk;auto"`
+	OwnerID int64  `jorm:"column:owner_id;readonly"`
+	Name    string `jorm:"size:50"`
+}
+
+func TestReadOnlyField(t *testing.T) {
+	db, cleanup := setupExtendedDB(t)
+	defer cleanup()
+
+	if err := db.AutoMigrate(&ReadOnlyAccount{}); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+
+	account := &ReadOnlyAccount{OwnerID: 7, Name: "main"}
+	if _, err := db.Model(account).Insert(account); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+
+	t.Run("StructUpdate", func(t *testing.T) {
+		q := db.Model(&ReadOnlyAccount{}).Where("id = ?", account.ID)
+		if _, err := q.Update(&ReadOnlyAccount{OwnerID: 8, Name: "renamed"}); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		if strings.Contains(q.LastSQL, "owner_id") {
+			t.Errorf("readonly column should not be in SET clause: %s", q.LastSQL)
+		}
+		if !strings.Contains(q.LastSQL, "`name` = ?") {
+			t.Errorf("Expected name in SET clause: %s", q.LastSQL)
+		}
+	})
+
+	t.Run("MapUpdate", func(t *testing.T) {
+		data := map[string]any{"owner_id": 9, "name": "mapped"}
+		q := db.Model(&ReadOnlyAccount{}).Where("id = ?", account.ID)
+		if _, err := q.Update(data); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		if strings.Contains(q.LastSQL, "owner_id") {
+			t.Errorf("readonly column should not be in SET clause: %s", q.LastSQL)
+		}
+		if len(data) != 2 {
+			t.Errorf("Caller's map should not be modified, got %v", data)
+		}
+
+		rows, err := db.Model(&ReadOnlyAccount{}).Where("id = ?", account.ID).Update(map[string]any{"owner_id": 10})
+		if err != nil || rows != 0 {
+			t.Errorf("Expected readonly-only update to be a no-op, got rows=%d err=%v", rows, err)
+		}
+	})
+
+	var got ReadOnlyAccount
+	if err := db.Model(&ReadOnlyAccount{}).Where("id = ?", account.ID).First(&got); err != nil {
+		t.Fatalf("First failed: %v", err)
+	}
+	if got.OwnerID != 7 || got.Name != "mapped" {
+		t.Errorf("Unexpected row after updates: %+v", got)
+	}
+}
+
+type StrictScanRecord struct {
+	ID    int64  `jorm:"pk;auto"`
+	Label string `jorm:"size:20"`
+	Age   int    `jorm:"column:age"`
+}
+
+func (StrictScanRecord) TableName() string {
+	return "strict_scan_record"
+}
+
+func TestStrictScan(t *testing.T) {
+	db, cleanup := setupExtendedDB(t)
+	defer cleanup()
+
+	// The age column holds text, so it cannot be scanned into the int field
+	if _, err := db.Exec("CREATE TABLE strict_scan_record (id INTEGER PRIMARY KEY AUTOINCREMENT, label TEXT, age TEXT)"); err != nil {
+		t.Fatalf("Create table failed: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO strict_scan_record (label, age) VALUES (?, ?)", "bad", "twenty"); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+
+	db.StrictScan(true)
+	defer db.StrictScan(false)
+
+	var records []StrictScanRecord
+	err := db.Model(&StrictScanRecord{}).Find(&records)
+	var scanErr *core.ScanError
+	if !errors.As(err, &scanErr) {
+		t.Fatalf("Expected ScanError, got %v", err)
+	}
+	if scanErr.Column != "age" || scanErr.Field != "Age" || scanErr.DstType != "int" || scanErr.SrcType != "string" {
+		t.Errorf("Unexpected ScanError: %+v", scanErr)
+	}
+	if !strings.Contains(err.Error(), `cannot scan column "age" (string) into field Age (int)`) {
+		t.Errorf("Error should describe column and types: %v", err)
+	}
+
+	var record StrictScanRecord
+	if err := db.Model(&StrictScanRecord{}).First(&record); !errors.As(err, &scanErr) {
+		t.Errorf("Expected ScanError from First, got %v", err)
+	}
+
+	// Valid rows still scan in strict mode
+	if _, err := db.Exec("UPDATE strict_scan_record SET age = ?", "20"); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if err := db.Model(&StrictScanRecord{}).First(&record); err != nil || record.Age != 20 {
+		t.Errorf("Expected age 20, got %d (err %v)", record.Age, err)
+	}
+}
+
+type JSONDocument struct {
+	ID    int64  `jorm:"pk auto"`
+	Attrs string `jorm:"type:text"`
+}
+
+func TestWhereJSON(t *testing.T) {
+	db, cleanup := setupExtendedDB(t)
+	defer cleanup()
+
+	if err := db.AutoMigrate(&JSONDocument{}); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+	for _, attrs := range []string{
+		`{"color": "red", "address": {"city": "Paris"}}`,
+		`{"color": "blue", "address": {"city": "Berlin"}}`,
+		`{"color": "red", "address": {"city": "Berlin"}}`,
+	} {
+		if _, err := db.Model(&JSONDocument{}).Insert(&JSONDocument{Attrs: attrs}); err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+	}
+
+	var docs []JSONDocument
+	err := db.Model(&JSONDocument{}).
+		WhereJSON("attrs", "color", "=", "red").
+		WhereJSON("attrs", "address.city", "=", "Berlin").
+		Find(&docs)
+	if err != nil {
+		t.Fatalf("WhereJSON query failed: %v", err)
+	}
+	if len(docs) != 1 || docs[0].ID != 3 {
+		t.Errorf("Expected document 3, got %+v", docs)
+	}
+
+	err = db.Model(&JSONDocument{}).WhereJSON("attrs", "color", "; DROP", "red").Find(&docs)
+	if !errors.Is(err, core.ErrInvalidQuery) {
+		t.Errorf("Expected ErrInvalidQuery for unsupported operator, got %v", err)
+	}
+}
+
+// Decimal is a minimal high-precision number kept as its decimal string, standing in for
+// types such as shopspring/decimal.Decimal.
+type Decimal struct {
+	digits string
+}
+
+func (d *Decimal) Scan(value any) error {
+	switch v := value.(type) {
+	case string:
+		d.digits = v
+	case []byte:
+		d.digits = string(v)
+	case nil:
+		d.digits = ""
+	default:
+		return fmt.Errorf("cannot scan %T into Decimal", value)
+	}
+	return nil
+}
+
+func (d Decimal) Value() (driver.Value, error) {
+	return d.digits, nil
+}
+
+// Labels is a slice stored as one comma-separated column through Scanner/Valuer.
+type Labels []string
+
+func (l *Labels) Scan(value any) error {
+	s, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into Labels", value)
+	}
+	*l = strings.Split(s, ",")
+	return nil
+}
+
+func (l Labels) Value() (driver.Value, error) {
+	return strings.Join(l, ","), nil
+}
+
+type LedgerEntry struct {
+	ID     int64    `jorm:"pk;auto"`
+	Amount Decimal  `jorm:"type:text"`
+	Fee    *Decimal `jorm:"type:text"`
+	Labels Labels   `jorm:"type:text"`
+}
+
+func TestScannerValuerFields(t *testing.T) {
+	db, cleanup := setupExtendedDB(t)
+	defer cleanup()
+
+	m, err := model.GetModel(&LedgerEntry{})
+	if err != nil {
+		t.Fatalf("GetModel failed: %v", err)
+	}
+	for _, col := range []string{"amount", "fee", "labels"} {
+		if _, ok := m.FieldMap[col]; !ok {
+			t.Errorf("Expected %s to be a column", col)
+		}
+	}
+
+	if err := db.AutoMigrate(&LedgerEntry{}); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+	const amount = "12345678901234567.123456789"
+	entry := &LedgerEntry{Amount: Decimal{amount}, Fee: &Decimal{"0.10"}, Labels: Labels{"a", "b"}}
+	if _, err := db.Model(entry).Insert(entry); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+
+	var got LedgerEntry
+	if err := db.Model(&LedgerEntry{}).Where("id = ?", entry.ID).First(&got); err != nil {
+		t.Fatalf("First failed: %v", err)
+	}
+	if got.Amount.digits != amount {
+		t.Errorf("Expected amount %s without rounding, got %s", amount, got.Amount.digits)
+	}
+	if got.Fee == nil || got.Fee.digits != "0.10" {
+		t.Errorf("Expected fee 0.10, got %v", got.Fee)
+	}
+	if strings.Join(got.Labels, "|") != "a|b" {
+		t.Errorf("Expected labels [a b], got %v", got.Labels)
+	}
+
+	var byAmount []LedgerEntry
+	if err := db.Model(&LedgerEntry{}).Where("amount = ?", Decimal{amount}).Find(&byAmount); err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if len(byAmount) != 1 {
+		t.Errorf("Expected to find the entry by its Valuer, got %d rows", len(byAmount))
+	}
+}
+
+type OptionalTimeEvent struct {
+	ID       int64 `jorm:"pk;auto"`
+	Name     string
+	StartsAt time.Time
+	EndsAt   time.Time `jorm:"no_auto_time"`
+}
+
+func TestNoAutoTime(t *testing.T) {
+	db, cleanup := setupExtendedDB(t)
+	defer cleanup()
+	if err := db.AutoMigrate(&OptionalTimeEvent{}); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+
+	e := &OptionalTimeEvent{Name: "open"}
+	if _, err := db.Model(e).Insert(e); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+	if e.StartsAt.IsZero() {
+		t.Error("Expected an untagged zero time to be filled on insert")
+	}
+	if !e.EndsAt.IsZero() {
+		t.Errorf("Expected no_auto_time field to stay zero, got %v", e.EndsAt)
+	}
+
+	var nulls int64
+	if err := db.Raw("SELECT COUNT(*) FROM optional_time_event WHERE ends_at IS NULL").Value(&nulls); err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if nulls != 1 {
+		t.Errorf("Expected ends_at to be stored as NULL, got %d NULL rows", nulls)
+	}
+
+	var found OptionalTimeEvent
+	if err := db.Model(&OptionalTimeEvent{}).Where("id = ?", e.ID).First(&found); err != nil {
+		t.Fatalf("First failed: %v", err)
+	}
+	if !found.EndsAt.IsZero() || found.StartsAt.IsZero() {
+		t.Errorf("Unexpected times after reload: %+v", found)
+	}
+
+	end := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	closed := &OptionalTimeEvent{Name: "closed", EndsAt: end}
+	if _, err := db.Model(closed).Insert(closed); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+	found = OptionalTimeEvent{}
+	if err := db.Model(&OptionalTimeEvent{}).Where("id = ?", closed.ID).First(&found); err != nil {
+		t.Fatalf("First failed: %v", err)
+	}
+	if !found.EndsAt.Equal(end) {
+		t.Errorf("Expected a set no_auto_time field to be written, got %v", found.EndsAt)
+	}
+
+	batch := []*OptionalTimeEvent{{Name: "batch open"}, {Name: "batch open 2"}}
+	q := db.Model(&OptionalTimeEvent{})
+	if _, err := q.BatchInsert(batch); err != nil {
+		t.Fatalf("BatchInsert failed: %v", err)
+	}
+	if strings.Contains(q.LastSQL, "ends_at") {
+		t.Errorf("BatchInsert should omit ends_at: %s", q.LastSQL)
+	}
+	mixed := []*OptionalTimeEvent{{Name: "mixed open"}, {Name: "mixed closed", EndsAt: end}}
+	if _, err := db.Model(&OptionalTimeEvent{}).BatchInsert(mixed); err != nil {
+		t.Fatalf("BatchInsert failed: %v", err)
+	}
+	if !mixed[0].EndsAt.IsZero() {
+		t.Errorf("Expected BatchInsert to keep the no_auto_time field zero, got %v", mixed[0].EndsAt)
+	}
+	if err := db.Raw("SELECT COUNT(*) FROM optional_time_event WHERE ends_at IS NULL").Value(&nulls); err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if nulls != 4 {
+		t.Errorf("Expected 4 NULL ends_at after BatchInsert, got %d", nulls)
+	}
+
+	type Conflicting struct {
+		ID        int64     `jorm:"pk;auto"`
+		CreatedAt time.Time `jorm:"auto_time no_auto_time"`
+	}
+	if _, err := model.GetModel(&Conflicting{}); err == nil {
+		t.Error("Expected an error for auto_time combined with no_auto_time")
+	}
+}
+
+type ExportRow struct {
+	ID        int64     `jorm:"pk;auto"`
+	Name      string    `jorm:"size:100"`
+	Note      *string   `jorm:"size:100"`
+	Payload   []byte    `jorm:"type:blob"`
+	CreatedAt time.Time `jorm:"notnull"`
+}
+
+func TestWriteCSV(t *testing.T) {
+	db, cleanup := setupExtendedDB(t)
+	defer cleanup()
+
+	if err := db.AutoMigrate(&ExportRow{}); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+	created := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	note := "needs, quoting"
+	rows := []*ExportRow{
+		{Name: "alpha", Note: &note, Payload: []byte("hi"), CreatedAt: created},
+		{Name: "beta", CreatedAt: created},
+	}
+	if _, err := db.Model(&ExportRow{}).BatchInsert(rows); err != nil {
+		t.Fatalf("BatchInsert failed: %v", err)
+	}
+
+	var buf strings.Builder
+	err := db.Model(&ExportRow{}).OrderBy("id").WriteCSV(&buf, core.CSVOptions{TimeLayout: "2006-01-02 15:04"})
+	if err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+	want := "id,name,note,payload,created_at\n" +
+		"1,alpha,\"needs, quoting\",aGk=,2024-03-01 12:30\n" +
+		"2,beta,,,2024-03-01 12:30\n"
+	if buf.String() != want {
+		t.Errorf("Unexpected CSV:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	t.Run("SelectedColumns", func(t *testing.T) {
+		var buf strings.Builder
+		err := db.Table("export_row").Select("name").Where("id = ?", 2).
+			WriteCSV(&buf, core.CSVOptions{Comma: ';', NoHeader: true})
+		if err != nil {
+			t.Fatalf("WriteCSV failed: %v", err)
+		}
+		if buf.String() != "beta\n" {
+			t.Errorf("Expected a single headerless row, got %q", buf.String())
+		}
+	})
+}
+
+func TestGeneratedColumn(t *testing.T) {
+	db, cleanup := setupExtendedDB(t)
+	defer cleanup()
+
+	if err := db.AutoMigrate(&GeneratedPerson{}); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+
+	person := &GeneratedPerson{FirstName: "Ada", LastName: "Lovelace", FullName: "ignored"}
+	q := db.Model(person)
+	if _, err := q.Insert(person); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+	if strings.Contains(q.LastSQL, "full_name") {
+		t.Errorf("generated column should not be inserted: %s", q.LastSQL)
+	}
+
+	q = db.Model(&GeneratedPerson{}).Where("id = ?", person.ID)
+	if _, err := q.Update(&GeneratedPerson{LastName: "King", FullName: "ignored"}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if strings.Contains(q.LastSQL, "full_name") {
+		t.Errorf("generated column should not be updated: %s", q.LastSQL)
+	}
+	if _, err := db.Model(&GeneratedPerson{}).Where("id = ?", person.ID).Update(map[string]any{"full_name": "x"}); err != nil {
+		t.Fatalf("Map update failed: %v", err)
+	}
+
+	if _, err := db.Model(&GeneratedPerson{}).BatchInsert([]*GeneratedPerson{{FirstName: "Grace", LastName: "Hopper"}}); err != nil {
+		t.Fatalf("BatchInsert failed: %v", err)
+	}
+
+	var people []GeneratedPerson
+	if err := db.Model(&GeneratedPerson{}).OrderBy("id").Find(&people); err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if len(people) != 2 || people[0].FullName != "Ada King" || people[1].FullName != "Grace Hopper" {
+		t.Errorf("Expected computed full names, got %+v", people)
+	}
+}
+
+type DatedEvent struct {
+	ID         int64     `jorm:"pk;auto"`
+	Name       string    `jorm:"size:50"`
+	HappenedAt time.Time `jorm:"notnull"`
+}
+
+func TestWhereDate(t *testing.T) {
+	db, cleanup := setupExtendedDB(t)
+	defer cleanup()
+	db.SetLocation(time.UTC)
+
+	if err := db.AutoMigrate(&DatedEvent{}); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+	events := []*DatedEvent{
+		{Name: "early", HappenedAt: time.Date(2024, 3, 1, 0, 5, 0, 0, time.UTC)},
+		{Name: "late", HappenedAt: time.Date(2024, 3, 1, 23, 55, 0, 0, time.UTC)},
+		{Name: "next", HappenedAt: time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC)},
+	}
+	if _, err := db.Model(&DatedEvent{}).BatchInsert(events); err != nil {
+		t.Fatalf("BatchInsert failed: %v", err)
+	}
+
+	day := time.Date(2024, 3, 1, 15, 0, 0, 0, time.UTC)
+	var got []DatedEvent
+	q := db.Model(&DatedEvent{}).WhereDate("happened_at", "=", day).OrderBy("id")
+	sqlStr, args := q.GetSelectSQL()
+	if !strings.Contains(sqlStr, "DATE(happened_at) = ?") || len(args) != 1 || args[0] != "2024-03-01" {
+		t.Errorf("Unexpected SQL %s with args %v", sqlStr, args)
+	}
+	if err := q.Find(&got); err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if len(got) != 2 || got[0].Name != "early" || got[1].Name != "late" {
+		t.Errorf("Expected both events of the day, got %+v", got)
+	}
+
+	count, err := db.Model(&DatedEvent{}).WhereDate("happened_at", ">", day).Count()
+	if err != nil || count != 1 {
+		t.Errorf("Expected one later event, got %d (%v)", count, err)
+	}
+
+	if err := db.Model(&DatedEvent{}).WhereDate("happened_at", "LIKE", day).Find(&got); !errors.Is(err, core.ErrInvalidQuery) {
+		t.Errorf("Expected ErrInvalidQuery for an unsupported operator, got %v", err)
+	}
+}
+
+type LikeItem struct {
+	ID   int64  `jorm:"pk;auto"`
+	Name string `jorm:"size:50"`
+}
+
+func TestWhereLike(t *testing.T) {
+	db, cleanup := setupExtendedDB(t)
+	defer cleanup()
+
+	if err := db.AutoMigrate(&LikeItem{}); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+	items := []*LikeItem{{Name: "50% off"}, {Name: "500 offers"}, {Name: "snake_case"}, {Name: "snakeXcase"}, {Name: `back\slash`}, {Name: "SALE"}}
+	if _, err := db.Model(&LikeItem{}).BatchInsert(items); err != nil {
+		t.Fatalf("BatchInsert failed: %v", err)
+	}
+	names := func(q *core.Query) []string {
+		t.Helper()
+		var found []LikeItem
+		if err := q.OrderBy("id").Find(&found); err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		var out []string
+		for _, it := range found {
+			out = append(out, it.Name)
+		}
+		return out
+	}
+
+	if got := names(db.Model(&LikeItem{}).WhereLike("name", "50%")); len(got) != 2 {
+		t.Errorf("Expected an unescaped %% to be a wildcard, got %v", got)
+	}
+	if got := names(db.Model(&LikeItem{}).WhereLike("name", core.EscapeLike("50%")+"%")); len(got) != 1 || got[0] != "50% off" {
+		t.Errorf("Expected an escaped %% to match literally, got %v", got)
+	}
+	if got := names(db.Model(&LikeItem{}).WhereLike("name", "%"+core.EscapeLike("e_c")+"%")); len(got) != 1 || got[0] != "snake_case" {
+		t.Errorf("Expected an escaped _ to match literally, got %v", got)
+	}
+	if got := names(db.Model(&LikeItem{}).WhereLike("name", core.EscapeLike(`back\slash`))); len(got) != 1 {
+		t.Errorf("Expected an escaped backslash to match literally, got %v", got)
+	}
+	if got := names(db.Model(&LikeItem{}).WhereILike("name", "sal%")); len(got) != 1 || got[0] != "SALE" {
+		t.Errorf("Expected a case-insensitive match, got %v", got)
+	}
+	if core.EscapeLike(`a%b_c\`) != `a\%b\_c\\` {
+		t.Errorf("Unexpected escaping: %s", core.EscapeLike(`a%b_c\`))
+	}
+}
@@ -1,12 +1,20 @@
 package tests
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"fmt"
+	"math"
 	"os"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
+	"github.com/shrek82/jorm"
 	"github.com/shrek82/jorm/core"
 	"github.com/shrek82/jorm/dialect"
 )
@@ -291,6 +299,71 @@ func TestIntegration(t *testing.T) {
 		}
 	})
 
+	t.Run("TransactionSavepoint", func(t *testing.T) {
+		db, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		names := func() []string {
+			var users []User
+			if err := db.Model(&User{}).OrderBy("id").Find(&users); err != nil {
+				t.Fatalf("Find failed: %v", err)
+			}
+			var out []string
+			for _, u := range users {
+				out = append(out, u.Name)
+			}
+			return out
+		}
+		insert := func(tx *core.Tx, name string) error {
+			u := &User{Name: name, Email: name + "@example.com"}
+			_, err := tx.Model(u).Insert(u)
+			return err
+		}
+
+		err := db.Transaction(func(tx *core.Tx) error {
+			if err := insert(tx, "Outer"); err != nil {
+				return err
+			}
+			innerErr := tx.Transaction(func(tx *core.Tx) error {
+				if err := insert(tx, "Inner"); err != nil {
+					return err
+				}
+				return fmt.Errorf("inner failed")
+			})
+			if innerErr == nil || innerErr.Error() != "inner failed" {
+				t.Errorf("Expected inner error, got %v", innerErr)
+			}
+			if err := tx.Transaction(func(tx *core.Tx) error { return insert(tx, "Kept") }); err != nil {
+				return err
+			}
+
+			if err := tx.Savepoint("manual"); err != nil {
+				return err
+			}
+			if err := insert(tx, "Manual"); err != nil {
+				return err
+			}
+			if err := tx.RollbackTo("manual"); err != nil {
+				return err
+			}
+			return tx.ReleaseSavepoint("manual")
+		})
+		if err != nil {
+			t.Fatalf("Transaction failed: %v", err)
+		}
+
+		if got := fmt.Sprint(names()); got != "[Outer Kept]" {
+			t.Errorf("Expected [Outer Kept] after rolling back inner savepoints, got %s", got)
+		}
+
+		err = db.Transaction(func(tx *core.Tx) error {
+			return tx.Savepoint("bad; DROP TABLE user")
+		})
+		if !errors.Is(err, core.ErrInvalidSQL) {
+			t.Errorf("Expected ErrInvalidSQL for an invalid savepoint name, got %v", err)
+		}
+	})
+
 	t.Run("BatchInsert", func(t *testing.T) {
 		db, cleanup := setupTestDB(t)
 		defer cleanup()
@@ -308,465 +381,1785 @@ func TestIntegration(t *testing.T) {
 		}
 	})
 
-	t.Run("Join", func(t *testing.T) {
+	t.Run("Touch", func(t *testing.T) {
 		db, cleanup := setupTestDB(t)
 		defer cleanup()
 
-		err := db.AutoMigrate(&Order{})
-		if err != nil {
-			t.Fatalf("AutoMigrate Order failed: %v", err)
-		}
-
-		user := &User{
-			Name:  "JoinUser",
-			Email: "join@example.com",
-			Age:   30,
+		user := &User{Name: "Touchy", Email: "touch@example.com", Age: 33, Score: 9.5}
+		other := &User{Name: "Other", Email: "other@example.com", Age: 44}
+		for _, u := range []*User{user, other} {
+			if _, err := db.Model(u).Insert(u); err != nil {
+				t.Fatalf("Insert failed: %v", err)
+			}
 		}
-		userID, err := db.Model(user).Insert(user)
-		if err != nil {
-			t.Fatalf("Insert user for join failed: %v", err)
+		old := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		if _, err := db.Model(&User{}).Where("1 = 1").Update(map[string]any{"updated_at": old}); err != nil {
+			t.Fatalf("Update failed: %v", err)
 		}
 
-		order := &Order{
-			UserID: userID,
-			Amount: 100.5,
+		q := db.Model(&User{})
+		rows, err := q.Touch(user)
+		if err != nil || rows != 1 {
+			t.Fatalf("Expected one row touched, got %d (%v)", rows, err)
 		}
-		_, err = db.Model(order).Insert(order)
-		if err != nil {
-			t.Fatalf("Insert order for join failed: %v", err)
+		if !strings.HasPrefix(q.LastSQL, "UPDATE `user` SET `updated_at` = ? WHERE") {
+			t.Errorf("Expected only updated_at to be set, got %s", q.LastSQL)
 		}
-
-		type OrderWithUser struct {
-			ID       int64   `jorm:"column:id"`
-			UserID   int64   `jorm:"column:user_id"`
-			Amount   float64 `jorm:"column:amount"`
-			UserName string  `jorm:"column:user_name"`
+		if !user.UpdatedAt.After(old) {
+			t.Errorf("Expected the struct's UpdatedAt to be set, got %v", user.UpdatedAt)
 		}
 
-		var results []OrderWithUser
-		err = db.Model(&Order{}).
-			Select(
-				"`order`.id as id",
-				"`order`.user_id as user_id",
-				"`order`.amount as amount",
-				"`user`.name as user_name",
-			).
-			Joins("INNER JOIN `user` ON `user`.id = `order`.user_id").
-			Where("`user`.name = ?", "JoinUser").
-			Find(&results)
-		if err != nil {
-			t.Fatalf("Join query failed: %v", err)
+		var got, untouched User
+		if err := db.Model(&User{}).Where("id = ?", user.ID).First(&got); err != nil {
+			t.Fatalf("First failed: %v", err)
 		}
-		if len(results) != 1 {
-			t.Fatalf("Expected 1 joined record, got %d", len(results))
+		if !got.UpdatedAt.After(old) || got.Name != "Touchy" || got.Age != 33 || got.Score != 9.5 || !got.CreatedAt.Equal(user.CreatedAt) {
+			t.Errorf("Expected only updated_at to change, got %+v", got)
 		}
-		if results[0].UserName != "JoinUser" {
-			t.Errorf("Expected UserName JoinUser, got %s", results[0].UserName)
+		if err := db.Model(&User{}).Where("id = ?", other.ID).First(&untouched); err != nil {
+			t.Fatalf("First failed: %v", err)
 		}
-		if results[0].Amount != 100.5 {
-			t.Errorf("Expected Amount 100.5, got %v", results[0].Amount)
+		if !untouched.UpdatedAt.Equal(old) {
+			t.Errorf("Expected the other row to keep its updated_at, got %v", untouched.UpdatedAt)
+		}
+
+		rows, err = db.Model(&User{}).Where("email = ?", "other@example.com").Touch()
+		if err != nil || rows != 1 {
+			t.Errorf("Expected the WHERE-selected row to be touched, got %d (%v)", rows, err)
+		}
+
+		if _, err := db.Model(&Order{}).Where("id = ?", 1).Touch(); !errors.Is(err, core.ErrInvalidModel) {
+			t.Errorf("Expected ErrInvalidModel for a model without auto_update, got %v", err)
 		}
 	})
 
-	t.Run("AliasMainTable", func(t *testing.T) {
+	t.Run("UpdateMapNull", func(t *testing.T) {
 		db, cleanup := setupTestDB(t)
 		defer cleanup()
 
-		user := &User{
-			Name:  "AliasUser",
-			Email: "alias@example.com",
-			Age:   28,
-		}
-		_, err := db.Model(user).Insert(user)
-		if err != nil {
-			t.Fatalf("Insert before alias main table failed: %v", err)
+		login := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		user := &User{Name: "Nullable", Email: "null@example.com", LastLogin: &login, BirthDate: &login}
+		if _, err := db.Model(user).Insert(user); err != nil {
+			t.Fatalf("Insert failed: %v", err)
 		}
 
-		type SimpleUser struct {
-			ID   int64  `jorm:"column:id"`
-			Name string `jorm:"column:name"`
+		q := db.Model(&User{}).Where("id = ?", user.ID)
+		rows, err := q.Update(map[string]any{"last_login": nil, "birth_date": (*time.Time)(nil)})
+		if err != nil || rows != 1 {
+			t.Fatalf("Expected one row updated, got %d (%v)", rows, err)
+		}
+		if !strings.Contains(q.LastSQL, "`birth_date` = ?, `last_login` = ?") {
+			t.Errorf("Expected both columns to be set, got %s", q.LastSQL)
 		}
 
-		var result SimpleUser
-		err = db.Table("user").
-			Alias("u").
-			Select("u.id as id", "u.name as name").
-			Where("u.name = ?", "AliasUser").
-			First(&result)
+		nulls, err := db.Model(&User{}).Where("id = ? AND last_login IS NULL AND birth_date IS NULL", user.ID).Count()
 		if err != nil {
-			t.Fatalf("Alias main table query failed: %v", err)
+			t.Fatalf("Count failed: %v", err)
 		}
-		if result.Name != "AliasUser" {
-			t.Errorf("Expected Name AliasUser, got %s", result.Name)
+		if nulls != 1 {
+			t.Errorf("Expected last_login and birth_date to be NULL")
+		}
+		var got User
+		if err := db.Model(&User{}).Where("id = ?", user.ID).First(&got); err != nil {
+			t.Fatalf("First failed: %v", err)
+		}
+		if got.LastLogin != nil || got.BirthDate != nil || got.Name != "Nullable" {
+			t.Errorf("Expected only the NULL columns to change, got %+v", got)
 		}
 	})
 
-	t.Run("MultipleJoin", func(t *testing.T) {
+	t.Run("QuoteTable", func(t *testing.T) {
 		db, cleanup := setupTestDB(t)
 		defer cleanup()
 
-		err := db.AutoMigrate(&Order{}, &Address{})
-		if err != nil {
-			t.Fatalf("AutoMigrate Order and Address failed: %v", err)
+		for _, u := range []*User{{Name: "Ann", Email: "ann@example.com", Age: 20}, {Name: "Bob", Email: "bob@example.com", Age: 40}} {
+			if _, err := db.Model(u).Insert(u); err != nil {
+				t.Fatalf("Insert failed: %v", err)
+			}
 		}
 
-		user := &User{
-			Name:  "JoinMultiUser",
-			Email: "joinmulti@example.com",
-			Age:   35,
-		}
-		userID, err := db.Model(user).Insert(user)
-		if err != nil {
-			t.Fatalf("Insert user for multi-join failed: %v", err)
+		var users []User
+		q := db.Table("`main`.`user` u").QuoteTable(false)
+		if err := q.Select("u.*").Where("u.age > ?", 30).Find(&users); err != nil {
+			t.Fatalf("Find on a pre-quoted table failed: %v", err)
 		}
-
-		order := &Order{
-			UserID: userID,
-			Amount: 200.5,
+		if len(users) != 1 || users[0].Name != "Bob" {
+			t.Errorf("Expected Bob, got %+v", users)
 		}
-		_, err = db.Model(order).Insert(order)
-		if err != nil {
-			t.Fatalf("Insert order for multi-join failed: %v", err)
+		if !strings.Contains(q.LastSQL, "FROM `main`.`user` u WHERE") {
+			t.Errorf("Expected the table to be written as is, got %s", q.LastSQL)
 		}
 
-		address := &Address{
-			UserID: userID,
-			City:   "Shanghai",
+		count, err := db.Table("`main`.`user`").QuoteTable(false).Where("age < ?", 30).Count()
+		if err != nil || count != 1 {
+			t.Errorf("Expected to count Ann, got %d (%v)", count, err)
 		}
-		_, err = db.Model(address).Insert(address)
-		if err != nil {
-			t.Fatalf("Insert address for multi-join failed: %v", err)
+		if _, err := db.Table("`main`.`user` u").Where("u.age > ?", 30).Count(); err == nil {
+			t.Error("Expected the quoted default to reject a pre-quoted table name")
 		}
+	})
 
-		type OrderWithUserAndAddress struct {
-			ID       int64   `jorm:"column:id"`
-			UserID   int64   `jorm:"column:user_id"`
-			Amount   float64 `jorm:"column:amount"`
-			UserName string  `jorm:"column:user_name"`
-			City     string  `jorm:"column:city"`
+	t.Run("OrderByNulls", func(t *testing.T) {
+		db, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		early := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		late := early.AddDate(0, 6, 0)
+		for _, u := range []*User{
+			{Name: "Never", Email: "never@example.com"},
+			{Name: "Early", Email: "early@example.com", LastLogin: &early},
+			{Name: "Late", Email: "late@example.com", LastLogin: &late},
+		} {
+			if _, err := db.Model(u).Insert(u); err != nil {
+				t.Fatalf("Insert failed: %v", err)
+			}
 		}
 
-		var results []OrderWithUserAndAddress
-		err = db.Model(&Order{}).
-			Select(
-				"`order`.id as id",
-				"`order`.user_id as user_id",
-				"`order`.amount as amount",
-				"`user`.name as user_name",
-				"`address`.city as city",
-			).
-			Joins("INNER JOIN `user` ON `user`.id = `order`.user_id").
-			Joins("INNER JOIN `address` ON `address`.user_id = `order`.user_id").
-			Where("`user`.name = ?", "JoinMultiUser").
-			Find(&results)
-		if err != nil {
-			t.Fatalf("Multiple join query failed: %v", err)
+		names := func(nulls, direction string) string {
+			var users []User
+			q := db.Model(&User{}).OrderByNulls("last_login", direction, nulls)
+			if err := q.Find(&users); err != nil {
+				t.Fatalf("Find failed: %v", err)
+			}
+			var out []string
+			for _, u := range users {
+				out = append(out, u.Name)
+			}
+			return strings.Join(out, ",")
 		}
-		if len(results) != 1 {
-			t.Fatalf("Expected 1 multi-joined record, got %d", len(results))
+		cases := []struct{ nulls, direction, expected string }{
+			{"LAST", "DESC", "Late,Early,Never"},
+			{"FIRST", "DESC", "Never,Late,Early"},
+			{"last", "asc", "Early,Late,Never"},
+			{"FIRST", "ASC", "Never,Early,Late"},
 		}
-		if results[0].UserName != "JoinMultiUser" {
-			t.Errorf("Expected UserName JoinMultiUser, got %s", results[0].UserName)
+		for _, c := range cases {
+			if got := names(c.nulls, c.direction); got != c.expected {
+				t.Errorf("NULLS %s %s: expected %s, got %s", c.nulls, c.direction, c.expected, got)
+			}
 		}
-		if results[0].Amount != 200.5 {
-			t.Errorf("Expected Amount 200.5, got %v", results[0].Amount)
+
+		var users []User
+		if err := db.Model(&User{}).OrderByNulls("last_login", "sideways", "LAST").Find(&users); !errors.Is(err, core.ErrInvalidQuery) {
+			t.Errorf("Expected ErrInvalidQuery for a bad direction, got %v", err)
 		}
-		if results[0].City != "Shanghai" {
-			t.Errorf("Expected City Shanghai, got %s", results[0].City)
+		if err := db.Model(&User{}).OrderByNulls("last_login", "ASC", "MIDDLE").Find(&users); !errors.Is(err, core.ErrInvalidQuery) {
+			t.Errorf("Expected ErrInvalidQuery for a bad nulls position, got %v", err)
 		}
 	})
 
-	t.Run("JoinsWithArgs", func(t *testing.T) {
+	t.Run("AllowColumns", func(t *testing.T) {
 		db, cleanup := setupTestDB(t)
 		defer cleanup()
 
-		err := db.AutoMigrate(&Order{})
-		if err != nil {
-			t.Fatalf("AutoMigrate Order failed: %v", err)
+		for _, u := range []*User{{Name: "Bea", Email: "bea@example.com", Age: 30}, {Name: "Al", Email: "al@example.com", Age: 20}} {
+			if _, err := db.Model(u).Insert(u); err != nil {
+				t.Fatalf("Insert failed: %v", err)
+			}
+		}
+		allowed := func() *core.Query {
+			return db.Model(&User{}).AllowColumns("name", "age", "created_at")
 		}
 
-		user := &User{
-			Name:  "ArgsUser",
-			Email: "args@example.com",
-			Age:   30,
+		var users []User
+		q := allowed().Select("name, age").OrderBy("AGE desc", "name")
+		if err := q.Find(&users); err != nil {
+			t.Fatalf("Find with allowed columns failed: %v", err)
+		}
+		if len(users) != 2 || users[0].Name != "Bea" || users[1].Name != "Al" {
+			t.Errorf("Expected Bea then Al, got %+v", users)
+		}
+
+		rejected := []*core.Query{
+			allowed().OrderBy("email"),
+			allowed().OrderBy("name; DROP TABLE user"),
+			allowed().OrderBy("(SELECT 1) DESC"),
+			allowed().OrderBy("age, email ASC"),
+			allowed().OrderBy("age sideways"),
+			allowed().Select("name", "password"),
+			allowed().Select("age AS email"),
+			allowed().GroupBy("email"),
+			allowed().OrderByNulls("email", "ASC", "LAST"),
+		}
+		for _, q := range rejected {
+			var users []User
+			if err := q.Find(&users); !errors.Is(err, core.ErrColumnNotAllowed) {
+				t.Errorf("Expected ErrColumnNotAllowed, got %v", err)
+			}
+			if q.LastSQL != "" {
+				t.Errorf("Expected no SQL to run, got %s", q.LastSQL)
+			}
 		}
-		userID, err := db.Model(user).Insert(user)
-		if err != nil {
-			t.Fatalf("Insert user failed: %v", err)
+
+		if err := db.Model(&User{}).OrderBy("email").Find(&users); err != nil {
+			t.Errorf("Expected queries without an allowlist to be unrestricted, got %v", err)
 		}
+	})
 
-		order := &Order{
-			UserID: userID,
-			Amount: 150.0,
+	t.Run("FirstOrTake", func(t *testing.T) {
+		db, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		user := &User{Name: "Found", Email: "found@example.com", Age: 27}
+		if _, err := db.Model(user).Insert(user); err != nil {
+			t.Fatalf("Insert failed: %v", err)
 		}
-		_, err = db.Model(order).Insert(order)
-		if err != nil {
-			t.Fatalf("Insert order failed: %v", err)
+
+		var got User
+		called := false
+		err := db.Model(&User{}).Where("email = ?", "found@example.com").FirstOr(&got, func() error {
+			called = true
+			return nil
+		})
+		if err != nil || called || got.Name != "Found" {
+			t.Errorf("Expected the record without calling fallback, got %+v (called %v, %v)", got, called, err)
 		}
 
-		type OrderWithUserArgs struct {
-			ID       int64   `jorm:"column:id"`
-			UserName string  `jorm:"column:user_name"`
-			Amount   float64 `jorm:"column:amount"`
+		var fallback User
+		err = db.Model(&User{}).Where("email = ?", "missing@example.com").FirstOr(&fallback, func() error {
+			fallback = User{Name: "Default"}
+			return nil
+		})
+		if err != nil || fallback.Name != "Default" {
+			t.Errorf("Expected the fallback to fill dest, got %+v (%v)", fallback, err)
+		}
+		errGone := errors.New("user is gone")
+		if err := db.Model(&User{}).Where("id = ?", -1).FirstOr(&fallback, func() error { return errGone }); err != errGone {
+			t.Errorf("Expected the fallback's error, got %v", err)
+		}
+		if err := db.Model(&User{}).Where("id = ?", -1).FirstOr(&fallback, nil); err != nil {
+			t.Errorf("Expected a nil fallback to ignore the missing record, got %v", err)
+		}
+		if err := db.Model(&User{}).Where("no_such_column = 1").FirstOr(&fallback, nil); err == nil || errors.Is(err, core.ErrRecordNotFound) {
+			t.Errorf("Expected other errors to be returned, got %v", err)
 		}
 
-		var results []OrderWithUserArgs
-		err = db.Model(&Order{}).
-			Select("`order`.id as id", "`user`.name as user_name", "`order`.amount as amount").
-			Joins("INNER JOIN `user` ON `user`.id = `order`.user_id AND `user`.age = ?", 30).
-			Find(&results)
-		if err != nil {
-			t.Fatalf("Joins with args failed: %v", err)
+		var taken User
+		found, err := db.Model(&User{}).Where("id = ?", user.ID).Take(&taken)
+		if !found || err != nil || taken.Email != "found@example.com" {
+			t.Errorf("Expected Take to find the record, got %v %+v (%v)", found, taken, err)
 		}
-		if len(results) != 1 {
-			t.Fatalf("Expected 1 result, got %d", len(results))
+		found, err = db.Model(&User{}).Where("id = ?", -1).Take(&taken)
+		if found || err != nil {
+			t.Errorf("Expected (false, nil) for a missing record, got (%v, %v)", found, err)
 		}
-		if results[0].UserName != "ArgsUser" {
-			t.Errorf("Expected UserName ArgsUser, got %s", results[0].UserName)
+		found, err = db.Model(&User{}).Where("no_such_column = 1").Take(&taken)
+		if found || err == nil {
+			t.Errorf("Expected Take to return query errors, got (%v, %v)", found, err)
 		}
 	})
 
-	t.Run("Sum", func(t *testing.T) {
+	t.Run("EmptyIn", func(t *testing.T) {
 		db, cleanup := setupTestDB(t)
 		defer cleanup()
 
-		users := []*User{
-			{
-				Name:  "User1",
-				Email: "user1@example.com",
-				Age:   10,
-			},
-			{
-				Name:  "User2",
-				Email: "user2@example.com",
-				Age:   20,
-			},
+		for _, u := range []*User{{Name: "One", Email: "one@example.com", Age: 1}, {Name: "Two", Email: "two@example.com", Age: 2}} {
+			if _, err := db.Model(u).Insert(u); err != nil {
+				t.Fatalf("Insert failed: %v", err)
+			}
 		}
-
-		for _, u := range users {
-			_, err := db.Model(u).Insert(u)
+		count := func(q *core.Query) int64 {
+			n, err := q.Where("age > ?", 0).Count()
 			if err != nil {
-				t.Fatalf("Insert before sum failed: %v", err)
+				t.Fatalf("Count failed: %v", err)
 			}
+			return n
 		}
 
-		sum, err := db.Model(&User{}).Sum("age")
-		if err != nil {
-			t.Fatalf("Sum failed: %v", err)
+		if n := count(db.Model(&User{}).WhereIn("age", []int{})); n != 0 {
+			t.Errorf("Expected an empty WhereIn to match nothing by default, got %d", n)
 		}
-		if sum != 30 {
-			t.Errorf("Expected sum age 30, got %v", sum)
+		if n := count(db.Model(&User{}).EmptyIn(core.EmptyInMatchNone).WhereIn("age", []int(nil))); n != 0 {
+			t.Errorf("Expected EmptyInMatchNone to match nothing, got %d", n)
+		}
+		if n := count(db.Model(&User{}).EmptyIn(core.EmptyInIgnore).WhereIn("age", []int{})); n != 2 {
+			t.Errorf("Expected EmptyInIgnore to skip the filter, got %d", n)
+		}
+		if n := count(db.Model(&User{}).EmptyIn(core.EmptyInIgnore).WhereIn("age", []int{2})); n != 1 {
+			t.Errorf("Expected EmptyInIgnore to keep non-empty filters, got %d", n)
+		}
+		if n := count(db.Model(&User{}).WhereInOrAll("age", []int{})); n != 2 {
+			t.Errorf("Expected WhereInOrAll to skip an empty filter, got %d", n)
+		}
+		if n := count(db.Model(&User{}).WhereInOrAll("age", []int{1, 3})); n != 1 {
+			t.Errorf("Expected WhereInOrAll to filter by its values, got %d", n)
 		}
-	})
 
-	t.Run("MultipleWhereAndSelect", func(t *testing.T) {
-		db, cleanup := setupTestDB(t)
+		var users []User
+		if err := db.Model(&User{}).WhereInOrAll("age", nil).Find(&users); !errors.Is(err, core.ErrInvalidWhereIn) {
+			t.Errorf("Expected ErrInvalidWhereIn for nil values, got %v", err)
+		}
+		rows, err := db.Model(&User{}).WhereIn("id", []int64{}).Delete()
+		if err != nil || rows != 0 {
+			t.Errorf("Expected a delete with an empty WhereIn to remove nothing, got %d (%v)", rows, err)
+		}
+	})
+
+	t.Run("CallProcUnsupported", func(t *testing.T) {
+		db, cleanup := setupTestDB(t)
 		defer cleanup()
 
-		user := &User{
-			Name:  "MultiUser",
-			Email: "multi@example.com",
-			Age:   40,
+		if _, err := db.CallProc(context.Background(), "report"); !errors.Is(err, core.ErrInvalidQuery) {
+			t.Errorf("expected ErrInvalidQuery on SQLite, got %v", err)
 		}
-		id, err := db.Model(user).Insert(user)
-		if err != nil {
-			t.Fatalf("Insert before multiple where/select failed: %v", err)
+		if _, err := db.CallProc(context.Background(), "report; DROP TABLE user"); !errors.Is(err, core.ErrInvalidQuery) {
+			t.Errorf("expected ErrInvalidQuery for an invalid name, got %v", err)
 		}
+	})
 
-		var result struct {
-			ID    int64  `jorm:"column:id"`
-			Name  string `jorm:"column:name"`
-			Email string `jorm:"column:email"`
+	t.Run("TooManyParameters", func(t *testing.T) {
+		db, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		ids := make([]int64, 40000)
+		for i := range ids {
+			ids[i] = int64(i + 1)
+		}
+		var users []User
+		err := db.Model(&User{}).WhereIn("id", ids).Find(&users)
+		if !errors.Is(err, core.ErrTooManyParameters) || !strings.Contains(err.Error(), "too many parameters") {
+			t.Errorf("Expected a too many parameters error from Find, got %v", err)
+		}
+		if _, err := db.Model(&User{}).WhereIn("id", ids).Delete(); !errors.Is(err, core.ErrTooManyParameters) {
+			t.Errorf("Expected ErrTooManyParameters from Delete, got %v", err)
+		}
+		if _, err := db.Model(&User{}).WhereIn("id", ids).Update(map[string]any{"age": 1}); !errors.Is(err, core.ErrTooManyParameters) {
+			t.Errorf("Expected ErrTooManyParameters from Update, got %v", err)
+		}
+		if _, err := db.Model(&User{}).WhereIn("id", ids).Count(); !errors.Is(err, core.ErrTooManyParameters) {
+			t.Errorf("Expected ErrTooManyParameters from Count, got %v", err)
+		}
+		if _, err := db.Model(&User{}).WhereIn("id", ids).Sum("age"); !errors.Is(err, core.ErrTooManyParameters) {
+			t.Errorf("Expected ErrTooManyParameters from Sum, got %v", err)
+		}
+		if _, err := db.Model(&User{}).WhereIn("id", ids).CountDistinct("age"); !errors.Is(err, core.ErrTooManyParameters) {
+			t.Errorf("Expected ErrTooManyParameters from CountDistinct, got %v", err)
+		}
+		var names []string
+		if err := db.Model(&User{}).WhereIn("id", ids).GroupConcat("name", ",", &names); !errors.Is(err, core.ErrTooManyParameters) {
+			t.Errorf("Expected ErrTooManyParameters from GroupConcat, got %v", err)
+		}
+		var maxAge sql.NullInt64
+		if err := db.Model(&User{}).Select("MAX(age)").WhereIn("id", ids).Value(&maxAge); !errors.Is(err, core.ErrTooManyParameters) {
+			t.Errorf("Expected ErrTooManyParameters from Value, got %v", err)
+		}
+		var maps []map[string]any
+		if err := db.Model(&User{}).WhereIn("id", ids).FindMaps(&maps); !errors.Is(err, core.ErrTooManyParameters) {
+			t.Errorf("Expected ErrTooManyParameters from FindMaps, got %v", err)
+		}
+	})
+
+	t.Run("FindAppend", func(t *testing.T) {
+		db, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		for _, u := range []*User{{Name: "One", Email: "one@example.com", Age: 1}, {Name: "Two", Email: "two@example.com", Age: 2}} {
+			if _, err := db.Model(u).Insert(u); err != nil {
+				t.Fatalf("Insert failed: %v", err)
+			}
 		}
 
-		query := db.Model(&User{})
-		query = query.Where("id = ?", id)
-		query = query.Where("email = ?", "multi@example.com")
-		query = query.Select("id")
-		query = query.Select("name", "email")
+		var users []User
+		for i := 0; i < 2; i++ {
+			if err := db.Model(&User{}).OrderBy("id").Find(&users); err != nil {
+				t.Fatalf("Find failed: %v", err)
+			}
+		}
+		if len(users) != 2 {
+			t.Errorf("Expected Find to replace the slice contents, got %d users", len(users))
+		}
 
-		err = query.First(&result)
-		if err != nil {
-			t.Fatalf("MultipleWhereAndSelect query failed: %v", err)
+		if err := db.Model(&User{}).Where("age = ?", 2).FindAppend(&users); err != nil {
+			t.Fatalf("FindAppend failed: %v", err)
 		}
-		if result.ID != id {
-			t.Errorf("Expected ID %d, got %d", id, result.ID)
+		if len(users) != 3 || users[0].Name != "One" || users[2].Name != "Two" {
+			t.Errorf("Expected FindAppend to keep earlier users, got %+v", users)
 		}
-		if result.Name != "MultiUser" {
-			t.Errorf("Expected Name MultiUser, got %s", result.Name)
+		var ptrs []*User
+		for i := 0; i < 2; i++ {
+			if err := db.Model(&User{}).FindAppend(&ptrs); err != nil {
+				t.Fatalf("FindAppend failed: %v", err)
+			}
 		}
-		if result.Email != "multi@example.com" {
-			t.Errorf("Expected Email multi@example.com, got %s", result.Email)
+		if len(ptrs) != 4 {
+			t.Errorf("Expected FindAppend to accumulate, got %d users", len(ptrs))
+		}
+		if err := db.Model(&User{}).FindAppend(users); err == nil {
+			t.Error("Expected FindAppend to reject a non-pointer dest")
 		}
 	})
 
-	t.Run("DeterministicUpdateSQL", func(t *testing.T) {
-		d, _ := dialect.Get("sqlite3")
-		builder := core.NewBuilder(d)
-		builder.SetTable("users")
-		builder.Where("id = ?", 1)
+	t.Run("WhereStruct", func(t *testing.T) {
+		db, cleanup := setupTestDB(t)
+		defer cleanup()
 
-		data := map[string]any{
-			"z_col": 1,
-			"a_col": 2,
-			"m_col": 3,
+		for _, u := range []*User{
+			{Name: "Alice", Email: "alice@example.com", Age: 17},
+			{Name: "Alina", Email: "alina@example.com", Age: 30},
+			{Name: "Bob", Email: "bob@example.com", Age: 40},
+		} {
+			if _, err := db.Model(u).Insert(u); err != nil {
+				t.Fatalf("Insert failed: %v", err)
+			}
 		}
 
-		sql, _ := builder.BuildUpdate(data)
-		// Expected order: a_col, m_col, z_col
-		expected := "UPDATE `users` SET `a_col` = ?, `m_col` = ?, `z_col` = ? WHERE (id = ?)"
-		if sql != expected {
-			t.Errorf("Expected deterministic SQL:\n%s\nGot:\n%s", expected, sql)
+		type UserSearch struct {
+			Name   string   `jormq:"op:like"`
+			MinAge int      `jorm:"column:age" jormq:"op:gte"`
+			Emails []string `jorm:"column:email" jormq:"op:in"`
+			Ages   []int    `jorm:"column:age" jormq:"op:between"`
+			Admin  *bool    `jorm:"column:is_admin"`
+		}
+		find := func(search UserSearch) []string {
+			t.Helper()
+			var users []User
+			if err := db.Model(&User{}).WhereStruct(search).OrderBy("id").Find(&users); err != nil {
+				t.Fatalf("Find failed: %v", err)
+			}
+			names := make([]string, len(users))
+			for i, u := range users {
+				names[i] = u.Name
+			}
+			return names
 		}
-	})
 
-	t.Run("PostgresPlaceholders", func(t *testing.T) {
-		pg, _ := dialect.Get("postgres")
-		builder := core.NewBuilder(pg)
-		builder.SetTable("users")
-		builder.Select("name", "age")
-		builder.Where("id = ?", 10)
-		builder.Where("status = ?", "active")
-		builder.Limit(5)
+		if got := find(UserSearch{Name: "Ali%", MinAge: 18}); len(got) != 1 || got[0] != "Alina" {
+			t.Errorf("Expected LIKE and >= to match Alina, got %v", got)
+		}
+		if got := find(UserSearch{}); len(got) != 3 {
+			t.Errorf("Expected zero fields to be skipped, got %v", got)
+		}
+		if got := find(UserSearch{Emails: []string{"alice@example.com", "bob@example.com"}}); len(got) != 2 || got[1] != "Bob" {
+			t.Errorf("Expected IN to match Alice and Bob, got %v", got)
+		}
+		if got := find(UserSearch{Ages: []int{20, 40}}); len(got) != 2 || got[0] != "Alina" {
+			t.Errorf("Expected BETWEEN to match Alina and Bob, got %v", got)
+		}
+		admin := false
+		if got := find(UserSearch{Admin: &admin, MinAge: 35}); len(got) != 1 || got[0] != "Bob" {
+			t.Errorf("Expected a pointer to false to filter, got %v", got)
+		}
 
-		sql, _ := builder.BuildSelect()
-		expected := `SELECT name, age FROM "users" WHERE (id = $1) AND (status = $2) LIMIT $3`
-		if sql != expected {
-			t.Errorf("Expected PG placeholders:\n%s\nGot:\n%s", expected, sql)
+		sqlStr, args := db.Model(&User{}).WhereStruct(&UserSearch{Name: "A%", MinAge: 18}).GetSelectSQL()
+		if !strings.Contains(sqlStr, "`name` LIKE ?") || !strings.Contains(sqlStr, "`age` >= ?") || len(args) != 2 {
+			t.Errorf("Unexpected WhereStruct SQL: %s %v", sqlStr, args)
 		}
 
-		// Test Update with PG placeholders
-		builder = core.NewBuilder(pg)
-		builder.SetTable("users")
-		builder.Where("id = ?", 1)
-		sql, _ = builder.BuildUpdate(map[string]any{"name": "test"})
-		expectedUpdate := `UPDATE "users" SET "name" = $1 WHERE (id = $2)`
-		if sql != expectedUpdate {
-			t.Errorf("Expected PG update placeholders:\n%s\nGot:\n%s", expectedUpdate, sql)
+		var users []User
+		err := db.Model(&User{}).WhereStruct(struct {
+			Age int `jormq:"op:near"`
+		}{Age: 1}).Find(&users)
+		if !errors.Is(err, core.ErrInvalidQuery) {
+			t.Errorf("Expected ErrInvalidQuery for an unknown operator, got %v", err)
+		}
+		err = db.Model(&User{}).WhereStruct(UserSearch{Ages: []int{1}}).Find(&users)
+		if !errors.Is(err, core.ErrInvalidQuery) {
+			t.Errorf("Expected ErrInvalidQuery for a single BETWEEN value, got %v", err)
 		}
 	})
 
-	t.Run("UpdateZeroValue", func(t *testing.T) {
+	t.Run("BatchInsertResult", func(t *testing.T) {
 		db, cleanup := setupTestDB(t)
 		defer cleanup()
 
-		user := &User{Name: "Initial", Email: "initial@example.com", Age: 20}
-		id, _ := db.Model(user).Insert(user)
-
-		// Update only Name, keep Age and Email (zero values in the struct)
-		updateUser := &User{Name: "Updated"}
-		affected, err := db.Model(&User{}).Where("id = ?", id).Update(updateUser)
+		if _, err := db.Model(&User{}).Insert(&User{Name: "First", Email: "first@example.com"}); err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+		users := []*User{
+			{Name: "A", Email: "a@example.com"},
+			{Name: "B", Email: "b@example.com"},
+			{Name: "C", Email: "c@example.com"},
+		}
+		res, err := db.Model(&User{}).BatchInsertResult(users)
 		if err != nil {
-			t.Fatal(err)
+			t.Fatalf("BatchInsertResult failed: %v", err)
 		}
-		if affected != 1 {
-			t.Errorf("Expected 1 row affected, got %d", affected)
+		if res.RowsAffected != 3 || res.FirstInsertID != 2 {
+			t.Errorf("Expected 3 rows starting at ID 2, got %+v", res)
 		}
-
-		// Verify values
-		var result User
-		db.Model(&User{}).Where("id = ?", id).First(&result)
-		if result.Name != "Updated" {
-			t.Errorf("Expected Name 'Updated', got '%s'", result.Name)
+		for i, u := range users {
+			var got User
+			if err := db.Model(&User{}).Where("id = ?", u.ID).First(&got); err != nil {
+				t.Fatalf("First failed: %v", err)
+			}
+			if u.ID != int64(i+2) || got.Email != u.Email {
+				t.Errorf("Expected %s to have ID %d, got %d (row %+v)", u.Email, i+2, u.ID, got)
+			}
 		}
-		if result.Age != 20 {
-			t.Errorf("Expected Age 20 (not overwritten by 0), got %d", result.Age)
+
+		values := []User{{Name: "D", Email: "d@example.com"}}
+		if _, err := db.Model(&User{}).BatchInsertResult(values); err != nil {
+			t.Fatalf("BatchInsertResult failed: %v", err)
 		}
-		if result.Email != "initial@example.com" {
-			t.Errorf("Expected Email 'initial@example.com' (not overwritten by ''), got '%s'", result.Email)
+		if values[0].ID != 5 {
+			t.Errorf("Expected the struct in the slice to get ID 5, got %d", values[0].ID)
 		}
 	})
 
-	t.Run("ScanPlanReuse", func(t *testing.T) {
+	t.Run("BatchUpsert", func(t *testing.T) {
 		db, cleanup := setupTestDB(t)
 		defer cleanup()
 
-		// Insert 10 users
-		for i := 1; i <= 10; i++ {
-			db.Model(&User{}).Insert(&User{
-				Name:  fmt.Sprintf("User%d", i),
-				Email: fmt.Sprintf("user%d@example.com", i),
-				Age:   i + 20,
-			})
+		var existing []*User
+		for i := 0; i < 50; i++ {
+			existing = append(existing, &User{Name: fmt.Sprintf("Old%d", i), Email: fmt.Sprintf("u%d@example.com", i), Age: 1})
+		}
+		if _, err := db.Model(&User{}).BatchInsert(existing); err != nil {
+			t.Fatalf("BatchInsert failed: %v", err)
 		}
 
-		var users []User
-		err := db.Model(&User{}).OrderBy("id ASC").Find(&users)
+		// 100 rows over more than one chunk, the first half conflicting on email
+		var users []*User
+		for i := 0; i < 100; i++ {
+			users = append(users, &User{Name: fmt.Sprintf("New%d", i), Email: fmt.Sprintf("u%d@example.com", i), Age: 2})
+		}
+		affected, err := db.Model(&User{}).BatchUpsert(users, []string{"email"})
 		if err != nil {
-			t.Fatal(err)
+			t.Fatalf("BatchUpsert failed: %v", err)
+		}
+		if affected != 100 {
+			t.Errorf("Expected 100 rows affected, got %d", affected)
 		}
 
-		if len(users) != 10 {
-			t.Fatalf("Expected 10 users, got %d", len(users))
+		count, err := db.Model(&User{}).Count()
+		if err != nil {
+			t.Fatalf("Count failed: %v", err)
+		}
+		if count != 100 {
+			t.Errorf("Expected 100 rows after upsert, got %d", count)
+		}
+		var stale int64
+		if err := db.Raw("SELECT COUNT(*) FROM user WHERE age <> 2 OR name NOT LIKE 'New%'").Value(&stale); err != nil {
+			t.Fatalf("Value failed: %v", err)
+		}
+		if stale != 0 {
+			t.Errorf("Expected every row to carry the upserted values, %d rows did not", stale)
+		}
+		var u User
+		if err := db.Model(&User{}).Where("email = ?", "u7@example.com").First(&u); err != nil {
+			t.Fatalf("First failed: %v", err)
+		}
+		if u.ID != 8 || u.Name != "New7" {
+			t.Errorf("Expected the existing row to be updated in place, got %+v", u)
 		}
 
-		for i, u := range users {
-			expectedName := fmt.Sprintf("User%d", i+1)
-			if u.Name != expectedName {
-				t.Errorf("Row %d: Expected name %s, got %s", i, expectedName, u.Name)
-			}
+		if _, err := db.Model(&User{}).BatchUpsert(users, nil); !errors.Is(err, core.ErrInvalidQuery) {
+			t.Errorf("Expected ErrInvalidQuery without conflict columns, got %v", err)
 		}
 	})
 
-	t.Run("FullTypes", func(t *testing.T) {
+	t.Run("Join", func(t *testing.T) {
 		db, cleanup := setupTestDB(t)
 		defer cleanup()
 
-		now := time.Now()
-		birthDate := now.AddDate(-20, 0, 0)
-		lastVisit := now.Add(-1 * time.Hour)
-		lastLogin := now.Add(-24 * time.Hour)
+		err := db.AutoMigrate(&Order{})
+		if err != nil {
+			t.Fatalf("AutoMigrate Order failed: %v", err)
+		}
 
 		user := &User{
-			Name:        "FullTypeUser",
-			Email:       "fulltype@example.com",
-			Age:         30,
-			IsAdmin:     true,
-			Profile:     "User Profile Text",
-			Avatar:      []byte("User Avatar Blob"),
-			Score:       99.99,
-			Status:      1,
-			LogCount:    100,
-			MediumInt:   50000,
-			VisitCount:  1000000,
-			BirthDate:   &birthDate,
-			LastVisitAt: &lastVisit,
-			LastLogin:   &lastLogin,
-			Preferences: `{"theme":"dark"}`,
+			Name:  "JoinUser",
+			Email: "join@example.com",
+			Age:   30,
 		}
-
-		id, err := db.Model(user).Insert(user)
+		userID, err := db.Model(user).Insert(user)
 		if err != nil {
-			t.Fatalf("Insert failed: %v", err)
+			t.Fatalf("Insert user for join failed: %v", err)
 		}
 
-		var got User
-		err = db.Model(&User{}).Where("id = ?", id).First(&got)
+		order := &Order{
+			UserID: userID,
+			Amount: 100.5,
+		}
+		_, err = db.Model(order).Insert(order)
 		if err != nil {
-			t.Fatalf("Find failed: %v", err)
+			t.Fatalf("Insert order for join failed: %v", err)
 		}
 
-		if got.Name != user.Name {
-			t.Errorf("Expected Name %s, got %s", user.Name, got.Name)
+		type OrderWithUser struct {
+			ID       int64   `jorm:"column:id"`
+			UserID   int64   `jorm:"column:user_id"`
+			Amount   float64 `jorm:"column:amount"`
+			UserName string  `jorm:"column:user_name"`
 		}
-		if got.IsAdmin != user.IsAdmin {
-			t.Errorf("Expected IsAdmin %v, got %v", user.IsAdmin, got.IsAdmin)
+
+		var results []OrderWithUser
+		err = db.Model(&Order{}).
+			Select(
+				"`order`.id as id",
+				"`order`.user_id as user_id",
+				"`order`.amount as amount",
+				"`user`.name as user_name",
+			).
+			Joins("INNER JOIN `user` ON `user`.id = `order`.user_id").
+			Where("`user`.name = ?", "JoinUser").
+			Find(&results)
+		if err != nil {
+			t.Fatalf("Join query failed: %v", err)
 		}
-		if string(got.Avatar) != string(user.Avatar) {
-			t.Errorf("Expected Avatar %s, got %s", user.Avatar, got.Avatar)
+		if len(results) != 1 {
+			t.Fatalf("Expected 1 joined record, got %d", len(results))
 		}
-		if got.Score != user.Score {
-			t.Errorf("Expected Score %f, got %f", user.Score, got.Score)
+		if results[0].UserName != "JoinUser" {
+			t.Errorf("Expected UserName JoinUser, got %s", results[0].UserName)
 		}
-		if got.MediumInt != user.MediumInt {
-			t.Errorf("Expected MediumInt %d, got %d", user.MediumInt, got.MediumInt)
+		if results[0].Amount != 100.5 {
+			t.Errorf("Expected Amount 100.5, got %v", results[0].Amount)
 		}
-		if got.Preferences != user.Preferences {
-			t.Errorf("Expected Preferences %s, got %s", user.Preferences, got.Preferences)
+	})
+
+	t.Run("JoinSelect", func(t *testing.T) {
+		db, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		if err := db.AutoMigrate(&Order{}); err != nil {
+			t.Fatalf("AutoMigrate Order failed: %v", err)
 		}
-		// Time comparison might be tricky due to precision, so skipping exact time check or use loose check
-		if got.BirthDate == nil {
-			t.Error("Expected BirthDate to be not nil")
+		var userID int64
+		for _, name := range []string{"Other", "JoinSelectUser"} {
+			u := &User{Name: name, Email: name + "@example.com"}
+			id, err := db.Model(u).Insert(u)
+			if err != nil {
+				t.Fatalf("Insert user failed: %v", err)
+			}
+			userID = id
 		}
-	})
+		order := &Order{UserID: userID, Amount: 42}
+		orderID, err := db.Model(order).Insert(order)
+		if err != nil {
+			t.Fatalf("Insert order failed: %v", err)
+		}
+		if orderID == userID {
+			t.Fatalf("Test requires distinct ids, both are %d", orderID)
+		}
+
+		type OrderRow struct {
+			Order Order
+			User  *User
+		}
+		var rows []OrderRow
+		err = db.Model(&Order{}).
+			JoinSelect("order", &Order{}).
+			JoinSelect("user", &User{}).
+			Joins("INNER JOIN `user` ON `user`.id = `order`.user_id").
+			Find(&rows)
+		if err != nil {
+			t.Fatalf("JoinSelect query failed: %v", err)
+		}
+		if len(rows) != 1 || rows[0].User == nil {
+			t.Fatalf("Expected 1 row with a user, got %+v", rows)
+		}
+		if rows[0].Order.ID != orderID || rows[0].User.ID != userID {
+			t.Errorf("Expected order id %d and user id %d, got %d and %d", orderID, userID, rows[0].Order.ID, rows[0].User.ID)
+		}
+		if rows[0].Order.Amount != 42 || rows[0].User.Name != "JoinSelectUser" {
+			t.Errorf("Unexpected row: order %+v, user %+v", rows[0].Order, *rows[0].User)
+		}
+	})
+
+	t.Run("AliasMainTable", func(t *testing.T) {
+		db, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		user := &User{
+			Name:  "AliasUser",
+			Email: "alias@example.com",
+			Age:   28,
+		}
+		_, err := db.Model(user).Insert(user)
+		if err != nil {
+			t.Fatalf("Insert before alias main table failed: %v", err)
+		}
+
+		type SimpleUser struct {
+			ID   int64  `jorm:"column:id"`
+			Name string `jorm:"column:name"`
+		}
+
+		var result SimpleUser
+		err = db.Table("user").
+			Alias("u").
+			Select("u.id as id", "u.name as name").
+			Where("u.name = ?", "AliasUser").
+			First(&result)
+		if err != nil {
+			t.Fatalf("Alias main table query failed: %v", err)
+		}
+		if result.Name != "AliasUser" {
+			t.Errorf("Expected Name AliasUser, got %s", result.Name)
+		}
+	})
+
+	t.Run("MultipleJoin", func(t *testing.T) {
+		db, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		err := db.AutoMigrate(&Order{}, &Address{})
+		if err != nil {
+			t.Fatalf("AutoMigrate Order and Address failed: %v", err)
+		}
+
+		user := &User{
+			Name:  "JoinMultiUser",
+			Email: "joinmulti@example.com",
+			Age:   35,
+		}
+		userID, err := db.Model(user).Insert(user)
+		if err != nil {
+			t.Fatalf("Insert user for multi-join failed: %v", err)
+		}
+
+		order := &Order{
+			UserID: userID,
+			Amount: 200.5,
+		}
+		_, err = db.Model(order).Insert(order)
+		if err != nil {
+			t.Fatalf("Insert order for multi-join failed: %v", err)
+		}
+
+		address := &Address{
+			UserID: userID,
+			City:   "Shanghai",
+		}
+		_, err = db.Model(address).Insert(address)
+		if err != nil {
+			t.Fatalf("Insert address for multi-join failed: %v", err)
+		}
+
+		type OrderWithUserAndAddress struct {
+			ID       int64   `jorm:"column:id"`
+			UserID   int64   `jorm:"column:user_id"`
+			Amount   float64 `jorm:"column:amount"`
+			UserName string  `jorm:"column:user_name"`
+			City     string  `jorm:"column:city"`
+		}
+
+		var results []OrderWithUserAndAddress
+		err = db.Model(&Order{}).
+			Select(
+				"`order`.id as id",
+				"`order`.user_id as user_id",
+				"`order`.amount as amount",
+				"`user`.name as user_name",
+				"`address`.city as city",
+			).
+			Joins("INNER JOIN `user` ON `user`.id = `order`.user_id").
+			Joins("INNER JOIN `address` ON `address`.user_id = `order`.user_id").
+			Where("`user`.name = ?", "JoinMultiUser").
+			Find(&results)
+		if err != nil {
+			t.Fatalf("Multiple join query failed: %v", err)
+		}
+		if len(results) != 1 {
+			t.Fatalf("Expected 1 multi-joined record, got %d", len(results))
+		}
+		if results[0].UserName != "JoinMultiUser" {
+			t.Errorf("Expected UserName JoinMultiUser, got %s", results[0].UserName)
+		}
+		if results[0].Amount != 200.5 {
+			t.Errorf("Expected Amount 200.5, got %v", results[0].Amount)
+		}
+		if results[0].City != "Shanghai" {
+			t.Errorf("Expected City Shanghai, got %s", results[0].City)
+		}
+	})
+
+	t.Run("JoinsWithArgs", func(t *testing.T) {
+		db, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		err := db.AutoMigrate(&Order{})
+		if err != nil {
+			t.Fatalf("AutoMigrate Order failed: %v", err)
+		}
+
+		user := &User{
+			Name:  "ArgsUser",
+			Email: "args@example.com",
+			Age:   30,
+		}
+		userID, err := db.Model(user).Insert(user)
+		if err != nil {
+			t.Fatalf("Insert user failed: %v", err)
+		}
+
+		order := &Order{
+			UserID: userID,
+			Amount: 150.0,
+		}
+		_, err = db.Model(order).Insert(order)
+		if err != nil {
+			t.Fatalf("Insert order failed: %v", err)
+		}
+
+		type OrderWithUserArgs struct {
+			ID       int64   `jorm:"column:id"`
+			UserName string  `jorm:"column:user_name"`
+			Amount   float64 `jorm:"column:amount"`
+		}
+
+		var results []OrderWithUserArgs
+		err = db.Model(&Order{}).
+			Select("`order`.id as id", "`user`.name as user_name", "`order`.amount as amount").
+			Joins("INNER JOIN `user` ON `user`.id = `order`.user_id AND `user`.age = ?", 30).
+			Find(&results)
+		if err != nil {
+			t.Fatalf("Joins with args failed: %v", err)
+		}
+		if len(results) != 1 {
+			t.Fatalf("Expected 1 result, got %d", len(results))
+		}
+		if results[0].UserName != "ArgsUser" {
+			t.Errorf("Expected UserName ArgsUser, got %s", results[0].UserName)
+		}
+	})
+
+	t.Run("Sum", func(t *testing.T) {
+		db, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		users := []*User{
+			{
+				Name:  "User1",
+				Email: "user1@example.com",
+				Age:   10,
+			},
+			{
+				Name:  "User2",
+				Email: "user2@example.com",
+				Age:   20,
+			},
+		}
+
+		for _, u := range users {
+			_, err := db.Model(u).Insert(u)
+			if err != nil {
+				t.Fatalf("Insert before sum failed: %v", err)
+			}
+		}
+
+		sum, err := db.Model(&User{}).Sum("age")
+		if err != nil {
+			t.Fatalf("Sum failed: %v", err)
+		}
+		if sum != 30 {
+			t.Errorf("Expected sum age 30, got %v", sum)
+		}
+	})
+
+	t.Run("MultipleWhereAndSelect", func(t *testing.T) {
+		db, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		user := &User{
+			Name:  "MultiUser",
+			Email: "multi@example.com",
+			Age:   40,
+		}
+		id, err := db.Model(user).Insert(user)
+		if err != nil {
+			t.Fatalf("Insert before multiple where/select failed: %v", err)
+		}
+
+		var result struct {
+			ID    int64  `jorm:"column:id"`
+			Name  string `jorm:"column:name"`
+			Email string `jorm:"column:email"`
+		}
+
+		query := db.Model(&User{})
+		query = query.Where("id = ?", id)
+		query = query.Where("email = ?", "multi@example.com")
+		query = query.Select("id")
+		query = query.Select("name", "email")
+
+		err = query.First(&result)
+		if err != nil {
+			t.Fatalf("MultipleWhereAndSelect query failed: %v", err)
+		}
+		if result.ID != id {
+			t.Errorf("Expected ID %d, got %d", id, result.ID)
+		}
+		if result.Name != "MultiUser" {
+			t.Errorf("Expected Name MultiUser, got %s", result.Name)
+		}
+		if result.Email != "multi@example.com" {
+			t.Errorf("Expected Email multi@example.com, got %s", result.Email)
+		}
+	})
+
+	t.Run("DeterministicUpdateSQL", func(t *testing.T) {
+		d, _ := dialect.Get("sqlite3")
+		builder := core.NewBuilder(d)
+		builder.SetTable("users")
+		builder.Where("id = ?", 1)
+
+		data := map[string]any{
+			"z_col": 1,
+			"a_col": 2,
+			"m_col": 3,
+		}
+
+		sql, _ := builder.BuildUpdate(data)
+		// Expected order: a_col, m_col, z_col
+		expected := "UPDATE `users` SET `a_col` = ?, `m_col` = ?, `z_col` = ? WHERE (id = ?)"
+		if sql != expected {
+			t.Errorf("Expected deterministic SQL:\n%s\nGot:\n%s", expected, sql)
+		}
+	})
+
+	t.Run("PostgresPlaceholders", func(t *testing.T) {
+		pg, _ := dialect.Get("postgres")
+		builder := core.NewBuilder(pg)
+		builder.SetTable("users")
+		builder.Select("name", "age")
+		builder.Where("id = ?", 10)
+		builder.Where("status = ?", "active")
+		builder.Limit(5)
+
+		sql, _ := builder.BuildSelect()
+		expected := `SELECT name, age FROM "users" WHERE (id = $1) AND (status = $2) LIMIT $3`
+		if sql != expected {
+			t.Errorf("Expected PG placeholders:\n%s\nGot:\n%s", expected, sql)
+		}
+
+		// Test Update with PG placeholders
+		builder = core.NewBuilder(pg)
+		builder.SetTable("users")
+		builder.Where("id = ?", 1)
+		sql, _ = builder.BuildUpdate(map[string]any{"name": "test"})
+		expectedUpdate := `UPDATE "users" SET "name" = $1 WHERE (id = $2)`
+		if sql != expectedUpdate {
+			t.Errorf("Expected PG update placeholders:\n%s\nGot:\n%s", expectedUpdate, sql)
+		}
+	})
+
+	t.Run("UpdateZeroValue", func(t *testing.T) {
+		db, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		user := &User{Name: "Initial", Email: "initial@example.com", Age: 20}
+		id, _ := db.Model(user).Insert(user)
+
+		// Update only Name, keep Age and Email (zero values in the struct)
+		updateUser := &User{Name: "Updated"}
+		affected, err := db.Model(&User{}).Where("id = ?", id).Update(updateUser)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if affected != 1 {
+			t.Errorf("Expected 1 row affected, got %d", affected)
+		}
+
+		// Verify values
+		var result User
+		db.Model(&User{}).Where("id = ?", id).First(&result)
+		if result.Name != "Updated" {
+			t.Errorf("Expected Name 'Updated', got '%s'", result.Name)
+		}
+		if result.Age != 20 {
+			t.Errorf("Expected Age 20 (not overwritten by 0), got %d", result.Age)
+		}
+		if result.Email != "initial@example.com" {
+			t.Errorf("Expected Email 'initial@example.com' (not overwritten by ''), got '%s'", result.Email)
+		}
+	})
+
+	t.Run("ScanPlanReuse", func(t *testing.T) {
+		db, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		// Insert 10 users
+		for i := 1; i <= 10; i++ {
+			db.Model(&User{}).Insert(&User{
+				Name:  fmt.Sprintf("User%d", i),
+				Email: fmt.Sprintf("user%d@example.com", i),
+				Age:   i + 20,
+			})
+		}
+
+		var users []User
+		err := db.Model(&User{}).OrderBy("id ASC").Find(&users)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(users) != 10 {
+			t.Fatalf("Expected 10 users, got %d", len(users))
+		}
+
+		for i, u := range users {
+			expectedName := fmt.Sprintf("User%d", i+1)
+			if u.Name != expectedName {
+				t.Errorf("Row %d: Expected name %s, got %s", i, expectedName, u.Name)
+			}
+		}
+	})
+
+	t.Run("FullTypes", func(t *testing.T) {
+		db, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		now := time.Now()
+		birthDate := now.AddDate(-20, 0, 0)
+		lastVisit := now.Add(-1 * time.Hour)
+		lastLogin := now.Add(-24 * time.Hour)
+
+		user := &User{
+			Name:        "FullTypeUser",
+			Email:       "fulltype@example.com",
+			Age:         30,
+			IsAdmin:     true,
+			Profile:     "User Profile Text",
+			Avatar:      []byte("User Avatar Blob"),
+			Score:       99.99,
+			Status:      1,
+			LogCount:    100,
+			MediumInt:   50000,
+			VisitCount:  1000000,
+			BirthDate:   &birthDate,
+			LastVisitAt: &lastVisit,
+			LastLogin:   &lastLogin,
+			Preferences: `{"theme":"dark"}`,
+		}
+
+		id, err := db.Model(user).Insert(user)
+		if err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+
+		var got User
+		err = db.Model(&User{}).Where("id = ?", id).First(&got)
+		if err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+
+		if got.Name != user.Name {
+			t.Errorf("Expected Name %s, got %s", user.Name, got.Name)
+		}
+		if got.IsAdmin != user.IsAdmin {
+			t.Errorf("Expected IsAdmin %v, got %v", user.IsAdmin, got.IsAdmin)
+		}
+		if string(got.Avatar) != string(user.Avatar) {
+			t.Errorf("Expected Avatar %s, got %s", user.Avatar, got.Avatar)
+		}
+		if got.Score != user.Score {
+			t.Errorf("Expected Score %f, got %f", user.Score, got.Score)
+		}
+		if got.MediumInt != user.MediumInt {
+			t.Errorf("Expected MediumInt %d, got %d", user.MediumInt, got.MediumInt)
+		}
+		if got.Preferences != user.Preferences {
+			t.Errorf("Expected Preferences %s, got %s", user.Preferences, got.Preferences)
+		}
+		// Time comparison might be tricky due to precision, so skipping exact time check or use loose check
+		if got.BirthDate == nil {
+			t.Error("Expected BirthDate to be not nil")
+		}
+	})
+
+	t.Run("Reload", func(t *testing.T) {
+		db, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		user := &User{Name: "Stale", Email: "stale@example.com", Age: 20}
+		id, err := db.Model(user).Insert(user)
+		if err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+		user.ID = id
+
+		if _, err := db.Exec("UPDATE user SET name = ?, age = ? WHERE id = ?", "Fresh", 21, id); err != nil {
+			t.Fatalf("Raw update failed: %v", err)
+		}
+		user.Profile = "local only"
+
+		if err := db.Model(user).Reload(user); err != nil {
+			t.Fatalf("Reload failed: %v", err)
+		}
+		if user.ID != id || user.Name != "Fresh" || user.Age != 21 || user.Email != "stale@example.com" {
+			t.Errorf("Unexpected reloaded user: %+v", user)
+		}
+		if user.Profile != "" {
+			t.Errorf("Expected unsaved Profile to be overwritten, got %q", user.Profile)
+		}
+
+		if err := db.Model(&User{}).Reload(&User{Name: "NoPK"}); err == nil {
+			t.Error("Expected error when reloading a zero primary key")
+		}
+		if err := db.Model(user).WhereJSON("profile", "a", "~", 1).Reload(user); !errors.Is(err, core.ErrInvalidQuery) {
+			t.Errorf("Expected the query's own error from Reload, got %v", err)
+		}
+
+		if _, err := db.Exec("DELETE FROM user WHERE id = ?", id); err != nil {
+			t.Fatalf("Raw delete failed: %v", err)
+		}
+		if err := db.Model(user).Reload(user); !errors.Is(err, core.ErrRecordNotFound) {
+			t.Errorf("Expected ErrRecordNotFound for deleted row, got %v", err)
+		}
+		if user.Name != "Fresh" {
+			t.Errorf("Failed reload should leave the struct untouched, got %+v", user)
+		}
+	})
+
+	t.Run("RawNamed", func(t *testing.T) {
+		db, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		for i, name := range []string{"Ann", "Bob", "Cid"} {
+			u := &User{Name: name, Email: name + "@example.com", Age: 20 + i*10}
+			if _, err := db.Model(u).Insert(u); err != nil {
+				t.Fatalf("Insert failed: %v", err)
+			}
+		}
+
+		q := db.RawNamed("SELECT * FROM user WHERE age > :age OR (name = :name AND age <= :age) AND email <> ':skip' ORDER BY id",
+			map[string]any{"age": 25, "name": "Ann"})
+		sqlStr, args := q.GetSelectSQL()
+		expected := "SELECT * FROM user WHERE age > ? OR (name = ? AND age <= ?) AND email <> ':skip' ORDER BY id"
+		if sqlStr != expected {
+			t.Errorf("Expected SQL: %s\nGot: %s", expected, sqlStr)
+		}
+		if fmt.Sprint(args) != "[25 Ann 25]" {
+			t.Errorf("Unexpected args: %v", args)
+		}
+
+		var users []User
+		if err := q.Scan(&users); err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		if len(users) != 3 {
+			t.Errorf("Expected 3 users, got %d", len(users))
+		}
+
+		castSQL, _ := db.RawNamed("SELECT :v::text", map[string]any{"v": 1}).GetSelectSQL()
+		if castSQL != "SELECT ?::text" {
+			t.Errorf("Expected cast to be kept, got %s", castSQL)
+		}
+
+		err := db.RawNamed("SELECT * FROM user WHERE id = :id", nil).Scan(&users)
+		if !errors.Is(err, core.ErrInvalidSQL) {
+			t.Errorf("Expected ErrInvalidSQL for missing parameter, got %v", err)
+		}
+	})
+
+	t.Run("SliceArgs", func(t *testing.T) {
+		db, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		var ids []int64
+		for _, name := range []string{"Ann", "Bob", "Cid"} {
+			u := &User{Name: name, Email: name + "@example.com", Age: 30}
+			id, err := db.Model(u).Insert(u)
+			if err != nil {
+				t.Fatalf("Insert failed: %v", err)
+			}
+			ids = append(ids, id)
+		}
+		want := []int64{ids[0], ids[2]}
+
+		var users []User
+		if err := db.Model(&User{}).Where("id IN (?)", want).OrderBy("id").Find(&users); err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		if len(users) != 2 || users[0].Name != "Ann" || users[1].Name != "Cid" {
+			t.Errorf("Expected Ann and Cid, got %+v", users)
+		}
+
+		q := db.Raw("SELECT * FROM user WHERE id IN (?) AND age = ? ORDER BY id", want, 30)
+		sqlStr, args := q.GetSelectSQL()
+		if sqlStr != "SELECT * FROM user WHERE id IN (?, ?) AND age = ? ORDER BY id" || len(args) != 3 {
+			t.Errorf("Unexpected raw expansion: %s %v", sqlStr, args)
+		}
+		users = nil
+		if err := q.Scan(&users); err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		if len(users) != 2 {
+			t.Errorf("Expected 2 users from raw query, got %d", len(users))
+		}
+
+		users = nil
+		err := db.RawNamed("SELECT * FROM user WHERE id IN (:ids) ORDER BY id", map[string]any{"ids": want}).Scan(&users)
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		if len(users) != 2 || users[1].Name != "Cid" {
+			t.Errorf("Expected Ann and Cid from named query, got %+v", users)
+		}
+	})
+
+	t.Run("Uint64Values", func(t *testing.T) {
+		db, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		type UnsignedCounter struct {
+			ID    uint64 `jorm:"pk;auto"`
+			Total uint64
+			Hits  *uint32
+		}
+		if err := db.AutoMigrate(&UnsignedCounter{}); err != nil {
+			t.Fatalf("AutoMigrate failed: %v", err)
+		}
+
+		hits := uint32(math.MaxUint32)
+		c := &UnsignedCounter{Total: math.MaxInt64, Hits: &hits}
+		id, err := db.Model(c).Insert(c)
+		if err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+		if c.ID == 0 || c.ID != uint64(id) {
+			t.Errorf("Expected unsigned PK to be set to %d, got %d", id, c.ID)
+		}
+
+		var got UnsignedCounter
+		if err := db.Model(&UnsignedCounter{}).Where("id = ?", id).First(&got); err != nil {
+			t.Fatalf("First failed: %v", err)
+		}
+		if got.Total != math.MaxInt64 || got.Hits == nil || *got.Hits != math.MaxUint32 {
+			t.Errorf("Expected values to round-trip, got total %d, hits %v", got.Total, got.Hits)
+		}
+
+		over := &UnsignedCounter{Total: math.MaxInt64 + 1}
+		if _, err := db.Model(over).Insert(over); !errors.Is(err, core.ErrValueOutOfRange) {
+			t.Errorf("Expected ErrValueOutOfRange, got %v", err)
+		}
+		got.Total = math.MaxUint64
+		if _, err := db.Model(&got).Update(&got); !errors.Is(err, core.ErrValueOutOfRange) {
+			t.Errorf("Expected ErrValueOutOfRange on update, got %v", err)
+		}
+		count, err := db.Model(&UnsignedCounter{}).Where("total = ?", int64(math.MaxInt64)).Count()
+		if err != nil || count != 1 {
+			t.Errorf("Expected the stored value to be unchanged, got count %d, %v", count, err)
+		}
+	})
+
+	t.Run("CancelledContext", func(t *testing.T) {
+		db, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		u := &User{Name: "Ann", Email: "ann@example.com"}
+		if _, err := db.Model(u).Insert(u); err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var users []User
+		var user User
+		ops := map[string]func() (*core.Query, error){
+			"Find": func() (*core.Query, error) {
+				q := db.Model(&User{}).WithContext(ctx)
+				return q, q.Find(&users)
+			},
+			"First": func() (*core.Query, error) {
+				q := db.Model(&User{}).WithContext(ctx)
+				return q, q.First(&user)
+			},
+			"Insert": func() (*core.Query, error) {
+				v := &User{Name: "Bob", Email: "bob@example.com"}
+				q := db.Model(v).WithContext(ctx)
+				_, err := q.Insert(v)
+				return q, err
+			},
+			"Update": func() (*core.Query, error) {
+				q := db.Model(&User{}).WithContext(ctx).Where("id = ?", u.ID)
+				_, err := q.Update(map[string]any{"name": "Changed"})
+				return q, err
+			},
+			"Delete": func() (*core.Query, error) {
+				q := db.Model(&User{}).WithContext(ctx).Where("id = ?", u.ID)
+				_, err := q.Delete()
+				return q, err
+			},
+			"Exec": func() (*core.Query, error) {
+				q := db.Raw("UPDATE user SET age = 1").WithContext(ctx)
+				_, err := q.ExecResult()
+				return q, err
+			},
+		}
+		for name, op := range ops {
+			q, err := op()
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("%s: expected context.Canceled, got %v", name, err)
+			}
+			if q.LastSQL != "" {
+				t.Errorf("%s: expected no SQL to be sent, got %s", name, q.LastSQL)
+			}
+		}
+
+		var got User
+		if err := db.Model(&User{}).Where("id = ?", u.ID).First(&got); err != nil {
+			t.Fatalf("First failed: %v", err)
+		}
+		if got.Name != "Ann" {
+			t.Errorf("Expected the row to be untouched, got %+v", got)
+		}
+	})
+
+	t.Run("ConsumedQuery", func(t *testing.T) {
+		db, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		u := &User{Name: "Ann", Email: "ann@example.com", Age: 30}
+		if _, err := db.Model(u).Insert(u); err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+
+		q := db.Model(&User{}).Where("age > ?", 18)
+		reused := q.Clone()
+		if count, err := q.Count(); err != nil || count != 1 {
+			t.Fatalf("Count failed: %d, %v", count, err)
+		}
+
+		var users []User
+		if err := q.Find(&users); !errors.Is(err, core.ErrQueryConsumed) {
+			t.Errorf("Expected ErrQueryConsumed from second terminal call, got %v", err)
+		}
+		if _, err := q.Where("name = ?", "Ann").Count(); !errors.Is(err, core.ErrQueryConsumed) {
+			t.Errorf("Expected ErrQueryConsumed after chaining on a consumed query, got %v", err)
+		}
+		// Building on a consumed query must not panic
+		q.GetSelectSQL()
+		if err := q.WhereInTuple([]string{"id", "age"}, [][]any{{1, 30}}).Find(&users); !errors.Is(err, core.ErrQueryConsumed) {
+			t.Errorf("Expected ErrQueryConsumed after WhereInTuple on a consumed query, got %v", err)
+		}
+
+		if err := reused.Find(&users); err != nil {
+			t.Fatalf("Find on clone failed: %v", err)
+		}
+		if len(users) != 1 {
+			t.Errorf("Expected 1 user from clone, got %d", len(users))
+		}
+	})
+
+	t.Run("Value", func(t *testing.T) {
+		db, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		for i, name := range []string{"Ann", "Bob", "Cid"} {
+			u := &User{Name: name, Email: name + "@example.com", Age: 20 + i*10}
+			if _, err := db.Model(u).Insert(u); err != nil {
+				t.Fatalf("Insert failed: %v", err)
+			}
+		}
+
+		var maxAge int64
+		if err := db.Table("user").Select("MAX(age)").Value(&maxAge); err != nil {
+			t.Fatalf("Value failed: %v", err)
+		}
+		if maxAge != 40 {
+			t.Errorf("Expected max age 40, got %d", maxAge)
+		}
+
+		var name string
+		if err := db.Model(&User{}).Select("name").Where("age = ?", 30).Value(&name); err != nil {
+			t.Fatalf("Value failed: %v", err)
+		}
+		if name != "Bob" {
+			t.Errorf("Expected Bob, got %q", name)
+		}
+
+		var count int
+		if err := db.Raw("SELECT COUNT(*) FROM user WHERE age > ?", 25).Value(&count); err != nil {
+			t.Fatalf("Raw Value failed: %v", err)
+		}
+		if count != 2 {
+			t.Errorf("Expected count 2, got %d", count)
+		}
+
+		err := db.Model(&User{}).Select("name").Where("age > ?", 100).Value(&name)
+		if !errors.Is(err, core.ErrRecordNotFound) {
+			t.Errorf("Expected ErrRecordNotFound, got %v", err)
+		}
+		err = db.Model(&User{}).Select("id", "name").Value(&name)
+		if !errors.Is(err, core.ErrInvalidQuery) {
+			t.Errorf("Expected ErrInvalidQuery for two columns, got %v", err)
+		}
+	})
+}
+
+func TestPingContext(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	if err := db.PingContext(context.Background()); err != nil {
+		t.Fatalf("PingContext on open DB failed: %v", err)
+	}
+
+	db.Close()
+	err := db.PingContext(context.Background())
+	if !errors.Is(err, core.ErrConnectionFailed) {
+		t.Fatalf("Expected ErrConnectionFailed on closed DB, got %v", err)
+	}
+
+	// The failed ping starts the cooldown, so queries fail fast without touching the pool
+	_, err = db.Model(&User{}).Count()
+	if !errors.Is(err, core.ErrConnectionFailed) || !strings.Contains(err.Error(), "cooldown") {
+		t.Errorf("Expected cooldown error after failed ping, got %v", err)
+	}
+}
+
+func TestSQLDB(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	sqlDB := db.SQLDB()
+	if sqlDB == nil {
+		t.Fatal("SQLDB returned nil")
+	}
+	if err := sqlDB.PingContext(context.Background()); err != nil {
+		t.Fatalf("PingContext failed: %v", err)
+	}
+
+	// Writes through the raw handle are visible to jorm queries
+	if _, err := sqlDB.Exec("INSERT INTO user (name, email, age, created_at) VALUES (?, ?, ?, ?)", "Raw", "raw@example.com", 42, time.Now()); err != nil {
+		t.Fatalf("raw insert failed: %v", err)
+	}
+	count, err := db.Model(&User{}).Where("email = ?", "raw@example.com").Count()
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 row inserted via SQLDB, got %d", count)
+	}
+}
+
+func TestConnectionCooldown(t *testing.T) {
+	openDB := func(t *testing.T, cooldown time.Duration) *core.DB {
+		t.Helper()
+		dbFile := "cooldown_test.db"
+		_ = os.Remove(dbFile)
+		db, err := core.Open("sqlite3", dbFile, &core.Options{
+			MaxOpenConns: 1,
+			Cooldown:     cooldown,
+			// Treat a missing table as "database unavailable" to simulate an outage
+			RetryableErrorClassifier: func(err error) bool {
+				return strings.Contains(err.Error(), "no such table")
+			},
+		})
+		if err != nil {
+			t.Fatalf("failed to open db: %v", err)
+		}
+		t.Cleanup(func() {
+			db.Close()
+			_ = os.Remove(dbFile)
+		})
+		if err := db.AutoMigrate(&User{}); err != nil {
+			t.Fatalf("AutoMigrate failed: %v", err)
+		}
+		return db
+	}
+
+	t.Run("CustomClassifier", func(t *testing.T) {
+		db := openDB(t, 200*time.Millisecond)
+
+		// Errors the classifier rejects do not trigger the cooldown
+		if _, err := db.Exec("INSERT INTO user (missing_column) VALUES (1)"); err == nil {
+			t.Fatal("expected insert into missing column to fail")
+		}
+		if _, err := db.Model(&User{}).Count(); err != nil {
+			t.Fatalf("query should not be blocked: %v", err)
+		}
+
+		if _, err := db.Exec("SELECT * FROM missing_table"); err == nil {
+			t.Fatal("expected query on missing table to fail")
+		}
+		if _, err := db.Model(&User{}).Count(); !errors.Is(err, core.ErrConnectionFailed) {
+			t.Fatalf("expected ErrConnectionFailed during cooldown, got %v", err)
+		}
+		if err := db.Transaction(func(tx *core.Tx) error { return nil }); !errors.Is(err, core.ErrConnectionFailed) {
+			t.Fatalf("expected transaction to be blocked during cooldown, got %v", err)
+		}
+
+		time.Sleep(250 * time.Millisecond)
+		if _, err := db.Model(&User{}).Count(); err != nil {
+			t.Fatalf("query should succeed after cooldown: %v", err)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		db := openDB(t, -1)
+
+		if _, err := db.Exec("SELECT * FROM missing_table"); err == nil {
+			t.Fatal("expected query on missing table to fail")
+		}
+		if _, err := db.Model(&User{}).Count(); err != nil {
+			t.Fatalf("query should not be blocked when cooldown is disabled: %v", err)
+		}
+	})
+}
+
+func TestRuntimePoolSettings(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	db.SetMaxOpenConns(5)
+	if got := db.Stats().MaxOpenConnections; got != 5 {
+		t.Errorf("Expected MaxOpenConnections 5, got %d", got)
+	}
+
+	// Without idle slots every released connection is closed
+	db.SetMaxIdleConns(0)
+	if _, err := db.Model(&User{}).Count(); err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	stats := db.Stats()
+	if stats.Idle != 0 || stats.MaxIdleClosed == 0 {
+		t.Errorf("Expected idle connections to be closed, got %+v", stats)
+	}
+
+	db.SetMaxIdleConns(2)
+	db.SetConnMaxIdleTime(time.Millisecond)
+	if _, err := db.Model(&User{}).Count(); err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	// The pool's cleaner runs at most once per second
+	deadline := time.Now().Add(3 * time.Second)
+	for db.Stats().MaxIdleTimeClosed == 0 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	if stats := db.Stats(); stats.MaxIdleTimeClosed == 0 {
+		t.Errorf("Expected the idle connection to expire, got %+v", stats)
+	}
+}
+
+// flakyExecutor fails the first failures calls with driver.ErrBadConn, like a stale
+// pooled connection, and passes the rest through to Executor.
+type flakyExecutor struct {
+	core.Executor
+	failures int
+	calls    int
+}
+
+func (e *flakyExecutor) fail() bool {
+	e.calls++
+	return e.calls <= e.failures
+}
+
+func (e *flakyExecutor) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	if e.fail() {
+		return nil, driver.ErrBadConn
+	}
+	return e.Executor.QueryContext(ctx, query, args...)
+}
+
+func (e *flakyExecutor) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	if e.fail() {
+		return nil, driver.ErrBadConn
+	}
+	return e.Executor.ExecContext(ctx, query, args...)
+}
+
+func TestBadConnRetry(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	d, _ := dialect.Get("sqlite3")
+	for _, name := range []string{"Ann", "Bob"} {
+		u := &User{Name: name, Email: name + "@example.com"}
+		if _, err := db.Model(u).Insert(u); err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+	}
+
+	t.Run("Find", func(t *testing.T) {
+		exec := &flakyExecutor{Executor: db.SQLDB(), failures: 1}
+		var users []User
+		if err := core.NewQuery(db, exec, core.NewBuilder(d)).Model(&User{}).Find(&users); err != nil {
+			t.Fatalf("Find should be retried, got %v", err)
+		}
+		if len(users) != 2 || exec.calls != 2 {
+			t.Errorf("Expected 2 users after 2 calls, got %d users after %d calls", len(users), exec.calls)
+		}
+	})
+
+	t.Run("First", func(t *testing.T) {
+		exec := &flakyExecutor{Executor: db.SQLDB(), failures: 1}
+		var u User
+		if err := core.NewQuery(db, exec, core.NewBuilder(d)).Model(&User{}).Where("name = ?", "Bob").First(&u); err != nil {
+			t.Fatalf("First should be retried, got %v", err)
+		}
+		if u.Name != "Bob" {
+			t.Errorf("Expected Bob, got %+v", u)
+		}
+	})
+
+	t.Run("RetriedOnce", func(t *testing.T) {
+		exec := &flakyExecutor{Executor: db.SQLDB(), failures: 2}
+		var users []User
+		err := core.NewQuery(db, exec, core.NewBuilder(d)).Model(&User{}).Find(&users)
+		if !errors.Is(err, driver.ErrBadConn) || exec.calls != 2 {
+			t.Errorf("Expected ErrBadConn after a single retry, got %v after %d calls", err, exec.calls)
+		}
+	})
+
+	t.Run("WritesNotRetried", func(t *testing.T) {
+		exec := &flakyExecutor{Executor: db.SQLDB(), failures: 1}
+		u := &User{Name: "Cid", Email: "cid@example.com"}
+		_, err := core.NewQuery(db, exec, core.NewBuilder(d)).Model(u).Insert(u)
+		if !errors.Is(err, driver.ErrBadConn) || exec.calls != 1 {
+			t.Errorf("Expected the insert to fail without retry, got %v after %d calls", err, exec.calls)
+		}
+	})
+}
+
+func TestInsertIdempotent(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	first := &User{Name: "Msg", Email: "msg@example.com"}
+	id, err := db.Model(first).InsertIdempotent(first, "msg-1")
+	if err != nil {
+		t.Fatalf("InsertIdempotent failed: %v", err)
+	}
+
+	// A redelivered message carries the same key
+	retry := &User{Name: "Msg", Email: "msg@example.com"}
+	retryID, err := db.Model(retry).InsertIdempotent(retry, "msg-1")
+	if err != nil {
+		t.Fatalf("InsertIdempotent retry failed: %v", err)
+	}
+	if retryID != id || retry.ID != id {
+		t.Errorf("Expected the retry to return id %d, got %d (value %d)", id, retryID, retry.ID)
+	}
+	count, err := db.Model(&User{}).Count()
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 row, got %d", count)
+	}
+
+	other := &User{Name: "Other", Email: "other@example.com"}
+	otherID, err := db.Model(other).InsertIdempotent(other, "msg-2")
+	if err != nil {
+		t.Fatalf("InsertIdempotent failed: %v", err)
+	}
+	if otherID == id {
+		t.Errorf("Expected a new row for a new key, got id %d again", otherID)
+	}
+
+	err = db.Transaction(func(tx *core.Tx) error {
+		u := &User{Name: "InTx", Email: "tx@example.com"}
+		txID, err := tx.Model(u).InsertIdempotent(u, "msg-2")
+		if err == nil && txID != otherID {
+			t.Errorf("Expected id %d inside a transaction, got %d", otherID, txID)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Transaction failed: %v", err)
+	}
+
+	if _, err := db.Model(&User{}).InsertIdempotent(&User{}, ""); !errors.Is(err, core.ErrInvalidQuery) {
+		t.Errorf("Expected ErrInvalidQuery for an empty key, got %v", err)
+	}
+}
+
+func TestConnectionRegistry(t *testing.T) {
+	open := func(file string) *core.DB {
+		_ = os.Remove(file)
+		db, err := jorm.Open("sqlite3", file, nil)
+		if err != nil {
+			t.Fatalf("failed to open db: %v", err)
+		}
+		t.Cleanup(func() {
+			db.Close()
+			_ = os.Remove(file)
+		})
+		return db
+	}
+	primary := open("registry_primary.db")
+	analytics := open("registry_analytics.db")
+
+	jorm.Register("primary", primary)
+	jorm.Register("analytics", analytics)
+	defer jorm.Unregister("primary")
+	defer jorm.Unregister("analytics")
+
+	if jorm.Use("primary") != primary || jorm.Use("analytics") != analytics {
+		t.Error("Expected each name to return its own connection")
+	}
+	if jorm.Use("missing") != nil {
+		t.Error("Expected nil for an unregistered name")
+	}
+	if jorm.Default() != primary || jorm.Use("") != primary {
+		t.Error("Expected the first registered connection to be the default")
+	}
+
+	if err := jorm.SetDefault("analytics"); err != nil {
+		t.Fatalf("SetDefault failed: %v", err)
+	}
+	if jorm.Default() != analytics {
+		t.Error("Expected SetDefault to change the default connection")
+	}
+	if err := jorm.SetDefault("missing"); err == nil {
+		t.Error("Expected SetDefault to fail for an unregistered name")
+	}
+
+	// Connections are independent databases
+	if err := jorm.Use("analytics").AutoMigrate(&Order{}); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+	if exists, _ := jorm.Use("primary").HasTable("order"); exists {
+		t.Error("Expected the table to exist on the analytics connection only")
+	}
+
+	jorm.Unregister("analytics")
+	if jorm.Default() != nil {
+		t.Error("Expected no default after unregistering it")
+	}
+}
+
+func TestOnConnect(t *testing.T) {
+	dbFile := "on_connect_test.db"
+	_ = os.Remove(dbFile)
+	defer os.Remove(dbFile)
+
+	var calls atomic.Int32
+	db, err := core.Open("sqlite3", dbFile, &core.Options{
+		MaxOpenConns: 2,
+		OnConnect: func(ctx context.Context, conn *sql.Conn) error {
+			calls.Add(1)
+			_, err := conn.ExecContext(ctx, "PRAGMA cache_size = -4321")
+			return err
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	var cacheSize int64
+	if err := db.Raw("PRAGMA cache_size").Value(&cacheSize); err != nil {
+		t.Fatalf("failed to read cache_size: %v", err)
+	}
+	if cacheSize != -4321 {
+		t.Errorf("Expected OnConnect to set cache_size to -4321, got %d", cacheSize)
+	}
+	if calls.Load() == 0 {
+		t.Error("Expected OnConnect to run")
+	}
+
+	// Every connection runs the hook, including ones opened while another is busy
+	err = db.Transaction(func(tx *core.Tx) error {
+		return db.Raw("PRAGMA cache_size").Value(&cacheSize)
+	})
+	if err != nil {
+		t.Fatalf("failed to read cache_size on a second connection: %v", err)
+	}
+	if cacheSize != -4321 || calls.Load() < 2 {
+		t.Errorf("Expected the second connection to be initialized, got cache_size %d after %d calls", cacheSize, calls.Load())
+	}
+
+	_, err = core.Open("sqlite3", dbFile, &core.Options{
+		OnConnect: func(ctx context.Context, conn *sql.Conn) error {
+			return errors.New("init failed")
+		},
+	})
+	if err == nil || !strings.Contains(err.Error(), "init failed") {
+		t.Errorf("Expected Open to report the OnConnect error, got %v", err)
+	}
 }
@@ -3,6 +3,7 @@ package tests
 import (
 	"bytes"
 	"context"
+	"errors"
 	"os"
 	"strings"
 	"testing"
@@ -134,3 +135,264 @@ func TestMiddleware(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+// recordingMiddleware appends its name to a shared log each time it processes a query.
+type recordingMiddleware struct {
+	name string
+	log  *[]string
+}
+
+func (m *recordingMiddleware) Name() string           { return m.name }
+func (m *recordingMiddleware) Init(db *core.DB) error { return nil }
+func (m *recordingMiddleware) Shutdown() error        { return nil }
+func (m *recordingMiddleware) Process(ctx context.Context, query *core.Query, next core.QueryFunc) (*core.Result, error) {
+	*m.log = append(*m.log, m.name)
+	return next(ctx, query)
+}
+
+func TestMiddlewareOrdering(t *testing.T) {
+	db, err := core.Open("sqlite3", ":memory:", nil)
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)"); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+
+	var log []string
+	db.Use(&recordingMiddleware{name: "A", log: &log})
+	db.Use(&recordingMiddleware{name: "B", log: &log})
+	db.UseFirst(&recordingMiddleware{name: "First", log: &log})
+
+	if _, err := db.Table("users").Count(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(log, ",") != "First,A,B" {
+		t.Errorf("Expected execution order First,A,B, got %v", log)
+	}
+
+	if !db.RemoveMiddleware("A") {
+		t.Error("Expected RemoveMiddleware(A) to return true")
+	}
+	if db.RemoveMiddleware("missing") {
+		t.Error("Expected RemoveMiddleware(missing) to return false")
+	}
+
+	names := make([]string, 0)
+	for _, m := range db.Middlewares() {
+		names = append(names, m.Name())
+	}
+	if strings.Join(names, ",") != "First,B" {
+		t.Errorf("Expected middlewares First,B, got %v", names)
+	}
+
+	// The snapshot is a copy, changing it leaves the registered middlewares alone
+	snapshot := db.Middlewares()
+	snapshot[0] = &recordingMiddleware{name: "Replaced", log: &log}
+	if first := db.Middlewares()[0].Name(); first != "First" {
+		t.Errorf("Expected the snapshot to be a copy, first middleware is %s", first)
+	}
+
+	log = nil
+	if _, err := db.Table("users").Count(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(log, ",") != "First,B" {
+		t.Errorf("Expected execution order First,B after removal, got %v", log)
+	}
+}
+
+type tenantKey struct{}
+
+// tenantMiddleware records the tenant carried by the context of each query it processes.
+type tenantMiddleware struct {
+	tenants []any
+}
+
+func (m *tenantMiddleware) Name() string           { return "tenant" }
+func (m *tenantMiddleware) Init(db *core.DB) error { return nil }
+func (m *tenantMiddleware) Shutdown() error        { return nil }
+func (m *tenantMiddleware) Process(ctx context.Context, query *core.Query, next core.QueryFunc) (*core.Result, error) {
+	m.tenants = append(m.tenants, ctx.Value(tenantKey{}))
+	return next(ctx, query)
+}
+
+func TestDBWithContext(t *testing.T) {
+	db, err := core.Open("sqlite3", ":memory:", &core.Options{MaxOpenConns: 1})
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)"); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	mw := &tenantMiddleware{}
+	db.Use(mw)
+
+	tenantDB := db.WithContext(context.WithValue(context.Background(), tenantKey{}, "acme"))
+	if _, err := tenantDB.Table("users").Count(); err != nil {
+		t.Fatal(err)
+	}
+	err = tenantDB.Transaction(func(tx *core.Tx) error {
+		_, err := tx.Table("users").Count()
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Table("users").Count(); err != nil {
+		t.Fatal(err)
+	}
+	if len(mw.tenants) != 3 || mw.tenants[0] != "acme" || mw.tenants[1] != "acme" || mw.tenants[2] != nil {
+		t.Errorf("Expected tenants [acme acme <nil>], got %v", mw.tenants)
+	}
+
+	t.Run("QueryContextOverrides", func(t *testing.T) {
+		mw.tenants = nil
+		ctx := context.WithValue(context.Background(), tenantKey{}, "other")
+		if _, err := tenantDB.Table("users").WithContext(ctx).Count(); err != nil {
+			t.Fatal(err)
+		}
+		if len(mw.tenants) != 1 || mw.tenants[0] != "other" {
+			t.Errorf("Expected tenant other, got %v", mw.tenants)
+		}
+	})
+
+	t.Run("CancelledBase", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := db.WithContext(ctx).Table("users").Count(); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+		if _, err := db.Table("users").Count(); err != nil {
+			t.Errorf("Expected the shared DB to be unaffected, got %v", err)
+		}
+	})
+}
+
+// operationMiddleware records the operation and model table of each query it processes.
+type operationMiddleware struct {
+	ops []string
+}
+
+func (m *operationMiddleware) Name() string           { return "operation" }
+func (m *operationMiddleware) Init(db *core.DB) error { return nil }
+func (m *operationMiddleware) Shutdown() error        { return nil }
+func (m *operationMiddleware) Process(ctx context.Context, query *core.Query, next core.QueryFunc) (*core.Result, error) {
+	op := query.Operation().String()
+	if mdl := query.GetModel(); mdl != nil {
+		op += ":" + mdl.TableName
+	}
+	m.ops = append(m.ops, op)
+	return next(ctx, query)
+}
+
+func TestMiddlewareOperation(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	mw := &operationMiddleware{}
+	db.Use(mw)
+
+	if q := db.Model(&User{}); q.Operation() != core.OpUnknown {
+		t.Errorf("Expected OpUnknown before execution, got %v", q.Operation())
+	}
+
+	u := &User{Name: "Op", Email: "op@example.com"}
+	if _, err := db.Model(u).Insert(u); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+	var users []User
+	if err := db.Model(&User{}).Find(&users); err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if _, err := db.Model(&User{}).Where("id = ?", u.ID).Update(map[string]any{"age": 5}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if _, err := db.Table("user").Count(); err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if _, err := db.Exec("UPDATE user SET age = 6"); err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+	if _, err := db.Model(u).Delete(u); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	want := "insert:user,select:user,update:user,select,raw,delete:user"
+	if got := strings.Join(mw.ops, ","); got != want {
+		t.Errorf("Expected operations %s, got %s", want, got)
+	}
+}
+
+func TestStatementTimeout(t *testing.T) {
+	db, err := core.Open("sqlite3", ":memory:", nil)
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+	db.Use(middleware.NewStatementTimeout(300 * time.Millisecond))
+
+	// Counting a billion generated rows blocks far longer than the timeout
+	const slow = "WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c WHERE x < 1000000000) SELECT COUNT(*) FROM c"
+	var n int64
+	start := time.Now()
+	err = db.Raw(slow).Value(&n)
+	elapsed := time.Since(start)
+	if err == nil {
+		t.Fatal("Expected the blocked query to be cancelled")
+	}
+	if elapsed > 2*time.Second {
+		t.Errorf("Expected the query to be cancelled at the timeout, took %v", elapsed)
+	}
+
+	// A shorter deadline on the context wins
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	if err := db.Raw(slow).WithContext(ctx).Value(&n); err == nil {
+		t.Fatal("Expected the blocked query to be cancelled")
+	}
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Errorf("Expected the context deadline to apply, took %v", elapsed)
+	}
+
+	if err := db.Raw("SELECT 42").Value(&n); err != nil || n != 42 {
+		t.Errorf("Expected a fast query to succeed, got %d (%v)", n, err)
+	}
+}
+
+func TestStatementTimeoutPreload(t *testing.T) {
+	db := setupPreloadDB(t)
+	defer db.Close()
+	defer cleanupPreloadDB(db)
+	db.Use(middleware.NewStatementTimeout(5 * time.Second))
+
+	user := &PreloadUser{Name: "Tim", Email: "tim@example.com"}
+	userID, err := db.Model(user).Insert(user)
+	if err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+	order := &PreloadOrder{UserID: userID, Amount: 10}
+	if _, err := db.Model(order).Insert(order); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+
+	// Preloads run after the middleware chain returned and cancelled its context
+	var users []PreloadUser
+	if err := db.Model(&PreloadUser{}).Preload("Orders").Find(&users); err != nil {
+		t.Fatalf("Find with preload failed: %v", err)
+	}
+	if len(users) != 1 || len(users[0].Orders) != 1 {
+		t.Errorf("Expected 1 user with 1 order, got %+v", users)
+	}
+	var found PreloadUser
+	if err := db.Model(&PreloadUser{}).Preload("Orders").First(&found); err != nil {
+		t.Fatalf("First with preload failed: %v", err)
+	}
+	if len(found.Orders) != 1 {
+		t.Errorf("Expected 1 preloaded order, got %d", len(found.Orders))
+	}
+}
@@ -1,11 +1,16 @@
 package tests
 
 import (
+	"errors"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/shrek82/jorm/core"
+	"github.com/shrek82/jorm/dialect"
+	"github.com/shrek82/jorm/model"
 )
 
 type MigrationUser struct {
@@ -54,6 +59,247 @@ func TestAutoMigrate(t *testing.T) {
 	}
 }
 
+func TestAutoMigrateWithCallbacks(t *testing.T) {
+	dbFile := "migration_callback_test.db"
+	defer os.Remove(dbFile)
+
+	db, err := core.Open("sqlite3", dbFile, nil)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	var migrated []string
+	err = db.AutoMigrateWithCallbacks([]any{&MigrationUser{}, &MigrationAccount{}}, func(m *model.Model) error {
+		migrated = append(migrated, m.TableName)
+		if m.TableName != "migration_user" {
+			return nil
+		}
+		_, err := db.Exec("CREATE INDEX IF NOT EXISTS idx_migration_user_named ON migration_user (name) WHERE name <> ''")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("AutoMigrateWithCallbacks failed: %v", err)
+	}
+	if strings.Join(migrated, ",") != "migration_user,migration_account" {
+		t.Errorf("Expected the callback once per table in order, got %v", migrated)
+	}
+
+	var count int64
+	if err := db.Raw("SELECT COUNT(*) FROM sqlite_master WHERE type = 'index' AND name = ?", "idx_migration_user_named").Value(&count); err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if count != 1 {
+		t.Error("Expected the index created by the callback to exist")
+	}
+
+	err = db.AutoMigrateWithCallbacks([]any{&MigrationUser{}, &MigrationAccount{}}, func(m *model.Model) error {
+		return errors.New("seed failed")
+	})
+	if err == nil || !strings.Contains(err.Error(), "migration_user") || !strings.Contains(err.Error(), "seed failed") {
+		t.Errorf("Expected the callback error with the table name, got %v", err)
+	}
+}
+
+type AuditTimes struct {
+	CreatedAt time.Time `jorm:"auto_time"`
+	UpdatedAt time.Time `jorm:"auto_update"`
+}
+
+type OrderedColumnsRow struct {
+	AuditTimes
+	Name string `jorm:"size:50"`
+	ID   int64  `jorm:"pk;auto"`
+	Note string `jorm:"size:200"`
+}
+
+func TestOrderDDLColumns(t *testing.T) {
+	for _, c := range []struct {
+		enabled  bool
+		expected string
+	}{
+		{false, "created_at,updated_at,name,id,note"},
+		{true, "id,name,note,created_at,updated_at"},
+	} {
+		db, err := core.Open("sqlite3", ":memory:", &core.Options{MaxOpenConns: 1})
+		if err != nil {
+			t.Fatalf("Failed to open database: %v", err)
+		}
+		db.OrderDDLColumns(c.enabled)
+		if err := db.AutoMigrate(&OrderedColumnsRow{}); err != nil {
+			t.Fatalf("AutoMigrate failed: %v", err)
+		}
+
+		var ddl string
+		if err := db.Table("sqlite_master").Select("sql").Where("name = ?", "ordered_columns_row").Value(&ddl); err != nil {
+			t.Fatalf("Failed to read DDL: %v", err)
+		}
+		if c.enabled && !strings.HasPrefix(ddl, "CREATE TABLE `ordered_columns_row` (`id` integer PRIMARY KEY") {
+			t.Errorf("Expected the PK column first, got %s", ddl)
+		}
+		var info []struct{ Name string }
+		if err := db.Raw("SELECT name FROM pragma_table_info('ordered_columns_row') ORDER BY cid").Find(&info); err != nil {
+			t.Fatalf("Failed to read columns: %v", err)
+		}
+		var columns []string
+		for _, col := range info {
+			columns = append(columns, col.Name)
+		}
+		if got := strings.Join(columns, ","); got != c.expected {
+			t.Errorf("OrderDDLColumns(%v): expected columns %s, got %s", c.enabled, c.expected, got)
+		}
+
+		row := &OrderedColumnsRow{Name: "a", Note: "b"}
+		if _, err := db.Model(row).Insert(row); err != nil || row.ID == 0 {
+			t.Errorf("OrderDDLColumns(%v): insert failed: %v", c.enabled, err)
+		}
+		db.Close()
+	}
+}
+
+type IndexedTicket struct {
+	ID        int64      `jorm:"pk;auto"`
+	Email     string     `jorm:"size:100 index:idx_ticket_active;where:deleted_at IS NULL notnull"`
+	Status    string     `jorm:"size:20 index:idx_ticket_active"`
+	Code      string     `jorm:"size:20 unique;where:code <> ''"`
+	Priority  int        `jorm:"index"`
+	DeletedAt *time.Time `jorm:"column:deleted_at"`
+}
+
+func TestAutoMigrateIndexes(t *testing.T) {
+	dbFile := "migration_index_test.db"
+	defer os.Remove(dbFile)
+
+	db, err := core.Open("sqlite3", dbFile, nil)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	m, err := model.GetModel(&IndexedTicket{})
+	if err != nil {
+		t.Fatalf("GetModel failed: %v", err)
+	}
+	if f := m.FieldMap["email"]; !f.Indexed || f.IndexName != "idx_ticket_active" || f.IndexWhere != "deleted_at IS NULL" || !f.NotNull {
+		t.Errorf("Unexpected parsed index tag: %+v", f)
+	}
+
+	// Migrating twice must not recreate the indexes
+	for i := 0; i < 2; i++ {
+		if err := db.AutoMigrate(&IndexedTicket{}); err != nil {
+			t.Fatalf("AutoMigrate failed: %v", err)
+		}
+	}
+
+	indexSQL := func(name string) string {
+		var sql string
+		if err := db.Raw("SELECT sql FROM sqlite_master WHERE type = 'index' AND name = ?", name).Value(&sql); err != nil {
+			t.Fatalf("index %s not found: %v", name, err)
+		}
+		return sql
+	}
+	if sql := indexSQL("idx_ticket_active"); !strings.HasSuffix(sql, "(email, status) WHERE deleted_at IS NULL") {
+		t.Errorf("Expected a composite partial index, got %s", sql)
+	}
+	if sql := indexSQL("idx_indexed_ticket_code"); !strings.HasPrefix(sql, "CREATE UNIQUE INDEX") || !strings.HasSuffix(sql, "WHERE code <> ''") {
+		t.Errorf("Expected a partial unique index, got %s", sql)
+	}
+	if sql := indexSQL("idx_indexed_ticket_priority"); strings.Contains(sql, "WHERE") {
+		t.Errorf("Expected a plain index, got %s", sql)
+	}
+
+	// The partial unique index allows duplicates outside its predicate
+	for _, code := range []string{"", "", "A"} {
+		ticket := &IndexedTicket{Email: "a@example.com", Code: code}
+		if _, err := db.Model(ticket).Insert(ticket); err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+	}
+	dup := &IndexedTicket{Email: "b@example.com", Code: "A"}
+	if _, err := db.Model(dup).Insert(dup); err == nil {
+		t.Error("Expected a duplicate code to be rejected")
+	}
+}
+
+type MigrationAccount struct {
+	ID   int64  `jorm:"pk;auto"`
+	Name string `jorm:"column:name"`
+}
+
+func (MigrationAccount) TableName() string { return "migration_account" }
+
+type MigrationAccountV2 struct {
+	ID     int64  `jorm:"pk;auto"`
+	Name   string `jorm:"column:name"`
+	Status string `jorm:"column:status notnull default:'active'"`
+	Rank   int    `jorm:"column:rank notnull"`
+}
+
+func (MigrationAccountV2) TableName() string { return "migration_account" }
+
+func TestAutoMigrateAddNotNullColumn(t *testing.T) {
+	dbFile := "migration_notnull_test.db"
+	defer os.Remove(dbFile)
+
+	db, err := core.Open("sqlite3", dbFile, nil)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.AutoMigrate(&MigrationAccount{}); err != nil {
+		t.Fatalf("AutoMigrate V1 failed: %v", err)
+	}
+	for _, name := range []string{"a", "b"} {
+		if _, err := db.Model(&MigrationAccount{}).Insert(&MigrationAccount{Name: name}); err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+	}
+
+	// Status is NOT NULL with a default, Rank is NOT NULL without one and is added nullable
+	if err := db.AutoMigrate(&MigrationAccountV2{}); err != nil {
+		t.Fatalf("AutoMigrate V2 on populated table failed: %v", err)
+	}
+
+	var accounts []MigrationAccountV2
+	if err := db.Model(&MigrationAccountV2{}).Select("id", "name", "status").OrderBy("id").Find(&accounts); err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if len(accounts) != 2 || accounts[0].Status != "active" || accounts[1].Status != "active" {
+		t.Errorf("Expected existing rows to get the default status, got %+v", accounts)
+	}
+
+	if _, err := db.Exec("INSERT INTO migration_account (name, status) VALUES (?, NULL)", "c"); err == nil {
+		t.Error("Expected NOT NULL constraint on the added status column")
+	}
+
+	t.Run("DialectSQL", func(t *testing.T) {
+		m, err := model.GetModel(&MigrationAccountV2{})
+		if err != nil {
+			t.Fatalf("GetModel failed: %v", err)
+		}
+		status, rank := m.FieldMap["status"], m.FieldMap["rank"]
+		for name, expected := range map[string]string{
+			"sqlite3":   "ADD COLUMN `status` text DEFAULT 'active' NOT NULL",
+			"mysql":     "ADD COLUMN `status` varchar(255) NOT NULL DEFAULT 'active'",
+			"postgres":  `ADD COLUMN "status" varchar(255) DEFAULT 'active' NOT NULL`,
+			"sqlserver": "ADD [status] nvarchar(255) DEFAULT 'active' NOT NULL",
+			"oracle":    `ADD ("STATUS" varchar2(255) DEFAULT 'active' NOT NULL)`,
+		} {
+			d, _ := dialect.Get(name)
+			if addSQL, _ := d.AddColumnSQL("migration_account", status); !strings.Contains(addSQL, expected) {
+				t.Errorf("%s: expected %q in %s", name, expected, addSQL)
+			}
+			if name == "mysql" {
+				continue
+			}
+			if addSQL, _ := d.AddColumnSQL("migration_account", rank); strings.Contains(addSQL, "NOT NULL") {
+				t.Errorf("%s: NOT NULL column without default should be added nullable, got %s", name, addSQL)
+			}
+		}
+	})
+}
+
 func TestMigrator(t *testing.T) {
 	dbFile := "migrator_test.db"
 	defer os.Remove(dbFile)
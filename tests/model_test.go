@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -82,6 +83,43 @@ func TestGetModel(t *testing.T) {
 		}
 	})
 
+	t.Run("Columns", func(t *testing.T) {
+		m, err := model.GetModel(&EmbeddedUser{})
+		if err != nil {
+			t.Fatalf("Failed to get model: %v", err)
+		}
+		want := "id,user_name,email,age,created_at,extra_info"
+		if got := strings.Join(m.Columns(), ","); got != want {
+			t.Errorf("Expected columns %s, got %s", want, got)
+		}
+
+		type Customer struct {
+			ID      int64  `jorm:"pk;auto"`
+			Name    string `jorm:"size:100"`
+			OwnerID int64
+			Orders  []PreloadOrder `jorm:"fk:UserID;relation:has_many"`
+			Owner   *PreloadUser   `jorm:"fk:OwnerID;relation:belongs_to"`
+			Notes   map[string]string
+			Status  string
+		}
+		m, err = model.GetModel(&Customer{})
+		if err != nil {
+			t.Fatalf("Failed to get model: %v", err)
+		}
+		if got := strings.Join(m.Columns(), ","); got != "id,name,owner_id,status" {
+			t.Errorf("Expected relations to be excluded, got %s", got)
+		}
+
+		db, cleanup := setupTestDB(t)
+		defer cleanup()
+		if got := strings.Join(db.Model(&EmbeddedUser{}).ModelColumns(), ","); got != want {
+			t.Errorf("Expected query columns %s, got %s", want, got)
+		}
+		if cols := db.Table("user").ModelColumns(); cols != nil {
+			t.Errorf("Expected no columns for a table query, got %v", cols)
+		}
+	})
+
 	t.Run("InvalidModel", func(t *testing.T) {
 		_, err := model.GetModel(123)
 		if err == nil {
@@ -157,4 +195,73 @@ func TestModelValidation(t *testing.T) {
 			t.Errorf("Expected valid model to pass validation, got error: %v", err)
 		}
 	})
+
+	t.Run("ArrayField", func(t *testing.T) {
+		type ArrayModel struct {
+			ID     int64    `jorm:"pk;auto"`
+			Tags   []string `jorm:"type:array"`
+			Scores []int64  `jorm:"type:integer[]"`
+			Skip   []string
+		}
+		m, err := model.GetModel(&ArrayModel{})
+		if err != nil {
+			t.Fatalf("Failed to get model: %v", err)
+		}
+
+		tags, ok := m.FieldMap["tags"]
+		if !ok || !tags.IsArray || tags.SQLType != "" {
+			t.Errorf("Expected tags to be an array field with derived SQL type, got %+v", tags)
+		}
+		scores, ok := m.FieldMap["scores"]
+		if !ok || !scores.IsArray || scores.SQLType != "integer[]" {
+			t.Errorf("Expected scores to keep explicit array type, got %+v", scores)
+		}
+		if _, ok := m.FieldMap["skip"]; ok {
+			t.Error("Untagged slice should not be mapped as a column")
+		}
+	})
+}
+
+type LegacyAccount struct {
+	ID       int64 `jorm:"pk;auto"`
+	UserName string
+	Nickname string `jorm:"column:nick"`
+}
+
+func TestColumnCase(t *testing.T) {
+	t.Cleanup(func() { model.SetColumnCase(model.SnakeCase) })
+
+	cases := []struct {
+		mode     model.ColumnCase
+		id, user string
+	}{
+		{model.SnakeCase, "id", "user_name"},
+		{model.UpperSnakeCase, "ID", "USER_NAME"},
+		{model.CamelCase, "id", "userName"},
+		{model.FieldNameCase, "ID", "UserName"},
+	}
+	for _, c := range cases {
+		model.SetColumnCase(c.mode)
+		if model.GetColumnCase() != c.mode {
+			t.Fatalf("Expected column case %d, got %d", c.mode, model.GetColumnCase())
+		}
+
+		m, err := model.GetModel(&LegacyAccount{})
+		if err != nil {
+			t.Fatalf("Failed to get model: %v", err)
+		}
+		if m.PKField.Column != c.id {
+			t.Errorf("Mode %d: expected pk column %q, got %q", c.mode, c.id, m.PKField.Column)
+		}
+		if _, ok := m.FieldMap[c.user]; !ok {
+			t.Errorf("Mode %d: expected column %q, got fields %v", c.mode, c.user, m.FieldMap)
+		}
+		// Explicit column tags are never rewritten
+		if _, ok := m.FieldMap["nick"]; !ok {
+			t.Errorf("Mode %d: explicit column tag should be kept", c.mode)
+		}
+		if m.TableName != "legacy_account" {
+			t.Errorf("Mode %d: table name should not change, got %q", c.mode, m.TableName)
+		}
+	}
 }
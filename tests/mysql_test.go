@@ -1,6 +1,8 @@
 package tests
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -241,3 +243,48 @@ func TestMySQLIntegration(t *testing.T) {
 		}
 	})
 }
+
+func TestMySQLCallProc(t *testing.T) {
+	db, cleanup := setupMySQLTestDB(t)
+	defer cleanup()
+
+	for _, name := range []string{"alice", "bob"} {
+		if _, err := db.Model(&User{}).Insert(&User{Name: name, Email: name + "@example.com", Age: 30}); err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+	}
+	if _, err := db.Exec("DROP PROCEDURE IF EXISTS jorm_user_sets"); err != nil {
+		t.Fatalf("failed to drop procedure: %v", err)
+	}
+	_, err := db.Exec("CREATE PROCEDURE jorm_user_sets(IN min_age INT) BEGIN " +
+		"SELECT id, name FROM `user` WHERE age >= min_age ORDER BY id; " +
+		"SELECT COUNT(*) AS total FROM `user`; END")
+	if err != nil {
+		t.Fatalf("failed to create procedure: %v", err)
+	}
+	defer db.Exec("DROP PROCEDURE IF EXISTS jorm_user_sets")
+
+	res, err := db.CallProc(context.Background(), "jorm_user_sets", 18)
+	if err != nil {
+		t.Fatalf("CallProc failed: %v", err)
+	}
+	defer res.Close()
+
+	var users []User
+	if err := res.Scan(0, &users); err != nil {
+		t.Fatalf("Scan of first set failed: %v", err)
+	}
+	if len(users) != 2 || users[0].Name != "alice" || users[1].Name != "bob" {
+		t.Errorf("unexpected first set %+v", users)
+	}
+	var totals []struct{ Total int64 }
+	if err := res.Scan(1, &totals); err != nil {
+		t.Fatalf("Scan of second set failed: %v", err)
+	}
+	if len(totals) != 1 || totals[0].Total != 2 {
+		t.Errorf("unexpected second set %+v", totals)
+	}
+	if err := res.Scan(0, &users); !errors.Is(err, core.ErrInvalidQuery) {
+		t.Errorf("expected ErrInvalidQuery scanning a set again, got %v", err)
+	}
+}
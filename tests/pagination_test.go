@@ -93,3 +93,84 @@ func TestPaginate(t *testing.T) {
 		t.Errorf("Expected 2 filtered users on page 1, got %d", len(filteredUsers))
 	}
 }
+
+func TestFindInBatches(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	if err := db.AutoMigrate(&PaginationUser{}); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+	var userPtrs []*PaginationUser
+	for i := 1; i <= 25; i++ {
+		userPtrs = append(userPtrs, &PaginationUser{Name: fmt.Sprintf("User%d", i)})
+	}
+	if _, err := db.Model(&PaginationUser{}).BatchInsert(userPtrs); err != nil {
+		t.Fatalf("BatchInsert failed: %v", err)
+	}
+
+	seen := make(map[int64]int)
+	var sizes []int
+	var batch []PaginationUser
+	err := db.Model(&PaginationUser{}).Where("name <> ?", "User5").FindInBatches(&batch, 10, func(any) error {
+		sizes = append(sizes, len(batch))
+		for _, u := range batch {
+			seen[u.ID]++
+		}
+		// Deleting rows that were already processed must not make later batches skip rows
+		_, err := db.Model(&PaginationUser{}).Where("id = ?", batch[0].ID).Delete()
+		return err
+	})
+	if err != nil {
+		t.Fatalf("FindInBatches failed: %v", err)
+	}
+
+	if fmt.Sprint(sizes) != "[10 10 4]" {
+		t.Errorf("Expected batch sizes [10 10 4], got %v", sizes)
+	}
+	if len(seen) != 24 {
+		t.Errorf("Expected 24 distinct rows, got %d", len(seen))
+	}
+	for id, n := range seen {
+		if n != 1 {
+			t.Errorf("Row %d seen %d times", id, n)
+		}
+	}
+
+	t.Run("OrWhere", func(t *testing.T) {
+		var users []PaginationUser
+		batches, total := 0, 0
+		err := db.Model(&PaginationUser{}).Where("name = ?", "User2").OrWhere("name LIKE ?", "User1%").
+			FindInBatches(&users, 3, func(any) error {
+				batches++
+				total += len(users)
+				if batches > 10 {
+					return fmt.Errorf("FindInBatches does not advance")
+				}
+				return nil
+			})
+		if err != nil {
+			t.Fatalf("FindInBatches failed: %v", err)
+		}
+		// User1 and User11 were deleted above; User2, User10 and User12-User19 remain
+		if total != 10 {
+			t.Errorf("Expected 10 rows, got %d in %d batches", total, batches)
+		}
+	})
+
+	t.Run("StopOnError", func(t *testing.T) {
+		stop := fmt.Errorf("stop")
+		calls := 0
+		var users []*PaginationUser
+		err := db.Model(&PaginationUser{}).FindInBatches(&users, 5, func(any) error {
+			calls++
+			return stop
+		})
+		if err != stop {
+			t.Errorf("Expected callback error, got %v", err)
+		}
+		if calls != 1 {
+			t.Errorf("Expected 1 callback call, got %d", calls)
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -63,3 +64,84 @@ func TestFindWithPointerSlice(t *testing.T) {
 		t.Errorf("Expected User1, got %s", resultStructs[0].Name)
 	}
 }
+
+type PtrFieldUser struct {
+	ID       int64   `jorm:"pk;auto"`
+	Name     string  `jorm:"size:100"`
+	Nickname *string `jorm:"size:100"`
+	Score    *int
+}
+
+func TestUpdateWithPointerFields(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	if err := db.AutoMigrate(&PtrFieldUser{}); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+
+	nick, score := "nick", 10
+	user := &PtrFieldUser{Name: "Alice", Nickname: &nick, Score: &score}
+	if _, err := db.Model(user).Insert(user); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+
+	reload := func() PtrFieldUser {
+		t.Helper()
+		var got PtrFieldUser
+		if err := db.Model(&PtrFieldUser{}).Where("id = ?", user.ID).First(&got); err != nil {
+			t.Fatalf("First failed: %v", err)
+		}
+		return got
+	}
+
+	t.Run("NilSkips", func(t *testing.T) {
+		q := db.Model(&PtrFieldUser{}).Where("id = ?", user.ID)
+		if _, err := q.Update(&PtrFieldUser{Name: "Alicia"}); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		if strings.Contains(q.LastSQL, "nickname") || strings.Contains(q.LastSQL, "score") {
+			t.Errorf("nil pointer fields should be skipped: %s", q.LastSQL)
+		}
+
+		got := reload()
+		if got.Name != "Alicia" || got.Nickname == nil || *got.Nickname != "nick" || got.Score == nil || *got.Score != 10 {
+			t.Errorf("Unexpected row after update with nil pointers: %+v", got)
+		}
+	})
+
+	t.Run("PointerToZeroWrites", func(t *testing.T) {
+		empty, zero := "", 0
+		q := db.Model(&PtrFieldUser{}).Where("id = ?", user.ID)
+		if _, err := q.Update(&PtrFieldUser{Nickname: &empty, Score: &zero}); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		for _, arg := range q.LastArgs[:2] {
+			if arg != "" && arg != 0 {
+				t.Errorf("Expected dereferenced zero values as args, got %#v", q.LastArgs)
+			}
+		}
+
+		got := reload()
+		if got.Nickname == nil || *got.Nickname != "" {
+			t.Errorf("Expected nickname to be empty string, got %v", got.Nickname)
+		}
+		if got.Score == nil || *got.Score != 0 {
+			t.Errorf("Expected score to be 0, got %v", got.Score)
+		}
+	})
+
+	t.Run("InsertNilWritesNull", func(t *testing.T) {
+		bob := &PtrFieldUser{Name: "Bob"}
+		if _, err := db.Model(bob).Insert(bob); err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+		count, err := db.Model(&PtrFieldUser{}).Where("id = ? AND nickname IS NULL AND score IS NULL", bob.ID).Count()
+		if err != nil {
+			t.Fatalf("Count failed: %v", err)
+		}
+		if count != 1 {
+			t.Errorf("Expected nil pointers to be stored as NULL")
+		}
+	})
+}
@@ -216,4 +216,39 @@ func TestPostgresIntegration(t *testing.T) {
 			t.Fatalf("Expected 0 rows after rollback, got %d", rollbackCount)
 		}
 	})
+
+	t.Run("ArrayColumns", func(t *testing.T) {
+		db, cleanup := setupPostgresTestDB(t)
+		defer cleanup()
+
+		if _, err := db.Exec("DROP TABLE IF EXISTS pg_array_post"); err != nil {
+			t.Fatalf("Drop table failed: %v", err)
+		}
+		if err := db.AutoMigrate(&PGArrayPost{}); err != nil {
+			t.Fatalf("AutoMigrate failed: %v", err)
+		}
+
+		post := &PGArrayPost{Title: "arrays", Tags: []string{"go", "orm", "with,comma"}, Scores: []int64{1, 2, 3}}
+		if _, err := db.Model(post).Insert(post); err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+
+		var got PGArrayPost
+		if err := db.Model(&PGArrayPost{}).Where("id = ?", post.ID).First(&got); err != nil {
+			t.Fatalf("First failed: %v", err)
+		}
+		if len(got.Tags) != 3 || got.Tags[0] != "go" || got.Tags[2] != "with,comma" {
+			t.Fatalf("Unexpected tags: %v", got.Tags)
+		}
+		if len(got.Scores) != 3 || got.Scores[2] != 3 {
+			t.Fatalf("Unexpected scores: %v", got.Scores)
+		}
+	})
+}
+
+type PGArrayPost struct {
+	ID     int64    `jorm:"pk;auto"`
+	Title  string   `jorm:"size:100"`
+	Tags   []string `jorm:"type:array"`
+	Scores []int64  `jorm:"type:array"`
 }
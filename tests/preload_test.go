@@ -1,11 +1,16 @@
 package tests
 
 import (
+	"errors"
+	"fmt"
+	"runtime"
 	"testing"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/shrek82/jorm/core"
+	"github.com/shrek82/jorm/middleware"
+	"github.com/shrek82/jorm/model"
 )
 
 type PreloadUser struct {
@@ -301,6 +306,285 @@ func TestPreloadManyToMany(t *testing.T) {
 	}
 }
 
+func TestAutoMigrateJoinTable(t *testing.T) {
+	db, err := core.Open("sqlite3", ":memory:", &core.Options{MaxOpenConns: 1})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	// Only the two sides of the relation are migrated, the join table is derived from the tag
+	if err := db.AutoMigrate(&PreloadUser{}, &PreloadRole{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	exists, err := db.HasTable("preload_user_role")
+	if err != nil {
+		t.Fatalf("HasTable failed: %v", err)
+	}
+	if !exists {
+		t.Fatal("Expected join table preload_user_role to be created")
+	}
+
+	if _, err := db.Exec("INSERT INTO preload_user_role (user_id, role_id) VALUES (?, ?)", 1, 2); err != nil {
+		t.Fatalf("Failed to insert join row: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO preload_user_role (user_id, role_id) VALUES (?, ?)", 1, 2); err == nil {
+		t.Error("Expected duplicate join row to be rejected by the composite primary key")
+	}
+
+	// Migrating again must leave the existing join table alone
+	if err := db.AutoMigrate(&PreloadUser{}); err != nil {
+		t.Fatalf("Failed to re-migrate: %v", err)
+	}
+	count, err := db.Table("preload_user_role").Count()
+	if err != nil {
+		t.Fatalf("Failed to count join rows: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 join row after re-migrate, got %d", count)
+	}
+}
+
+func TestAssociation(t *testing.T) {
+	db := setupPreloadDB(t)
+	defer db.Close()
+	defer cleanupPreloadDB(db)
+
+	user := &PreloadUser{Name: "Erin", Email: "erin@example.com"}
+	userID, err := db.Model(user).Insert(user)
+	if err != nil {
+		t.Fatalf("Failed to insert user: %v", err)
+	}
+	user.ID = userID
+
+	admin := &PreloadRole{Name: "assoc-admin"}
+	editor := &PreloadRole{Name: "assoc-editor"}
+	for _, r := range []*PreloadRole{admin, editor} {
+		id, err := db.Model(r).Insert(r)
+		if err != nil {
+			t.Fatalf("Failed to insert role: %v", err)
+		}
+		r.ID = id
+	}
+
+	joinRows := func() []PreloadUserRole {
+		var rows []PreloadUserRole
+		if err := db.Model(&PreloadUserRole{}).Where("user_id = ?", userID).OrderBy("role_id").Find(&rows); err != nil {
+			t.Fatalf("Failed to query join rows: %v", err)
+		}
+		return rows
+	}
+
+	t.Run("ManyToManyAppend", func(t *testing.T) {
+		if err := db.Model(user).Association("Roles").Append(admin, editor); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+		rows := joinRows()
+		if len(rows) != 2 || rows[0].RoleID != admin.ID || rows[1].RoleID != editor.ID {
+			t.Fatalf("Unexpected join rows: %+v", rows)
+		}
+
+		// Appending an existing link is a no-op
+		if err := db.Model(user).Association("Roles").Append(admin); err != nil {
+			t.Fatalf("Append of existing role failed: %v", err)
+		}
+		if rows := joinRows(); len(rows) != 2 {
+			t.Errorf("Expected 2 join rows after re-append, got %d", len(rows))
+		}
+	})
+
+	t.Run("ManyToManyDelete", func(t *testing.T) {
+		if err := db.Model(user).Association("Roles").Delete(admin); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		rows := joinRows()
+		if len(rows) != 1 || rows[0].RoleID != editor.ID {
+			t.Fatalf("Unexpected join rows after delete: %+v", rows)
+		}
+
+		// The role itself is kept
+		var role PreloadRole
+		if err := db.Model(&PreloadRole{}).Where("id = ?", admin.ID).First(&role); err != nil {
+			t.Errorf("Role should still exist: %v", err)
+		}
+	})
+
+	t.Run("ManyToManyReplace", func(t *testing.T) {
+		viewer := &PreloadRole{Name: "assoc-viewer"}
+		if err := db.Model(user).Association("Roles").Replace(admin, viewer); err != nil {
+			t.Fatalf("Replace failed: %v", err)
+		}
+		if viewer.ID == 0 {
+			t.Fatal("Expected new role to be inserted")
+		}
+		rows := joinRows()
+		if len(rows) != 2 || rows[0].RoleID != admin.ID || rows[1].RoleID != viewer.ID {
+			t.Fatalf("Unexpected join rows after replace: %+v", rows)
+		}
+
+		if err := db.Model(user).Association("Roles").Replace(); err != nil {
+			t.Fatalf("Clearing replace failed: %v", err)
+		}
+		if rows := joinRows(); len(rows) != 0 {
+			t.Errorf("Expected no join rows after clear, got %+v", rows)
+		}
+	})
+
+	t.Run("HasMany", func(t *testing.T) {
+		order := &PreloadOrder{Amount: 10}
+		orderID, err := db.Model(order).Insert(order)
+		if err != nil {
+			t.Fatalf("Failed to insert order: %v", err)
+		}
+		order.ID = orderID
+
+		if err := db.Model(user).Association("Orders").Append(order); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+		count, err := db.Model(&PreloadOrder{}).Where("user_id = ?", userID).Count()
+		if err != nil {
+			t.Fatalf("Count failed: %v", err)
+		}
+		if count != 1 {
+			t.Fatalf("Expected 1 order for user, got %d", count)
+		}
+
+		if err := db.Model(user).Association("Orders").Delete(order); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		count, err = db.Model(&PreloadOrder{}).Where("user_id = ?", userID).Count()
+		if err != nil {
+			t.Fatalf("Count failed: %v", err)
+		}
+		if count != 0 {
+			t.Errorf("Expected no orders for user after delete, got %d", count)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		if err := db.Model(user).Association("Missing").Append(admin); !errors.Is(err, core.ErrRelationNotFound) {
+			t.Errorf("Expected ErrRelationNotFound, got %v", err)
+		}
+		if err := db.Model(&PreloadUser{}).Association("Roles").Append(admin); err == nil {
+			t.Error("Expected error for owner without primary key")
+		}
+		if err := db.Model(user).Association("Roles").Append(&PreloadOrder{ID: 1}); err == nil {
+			t.Error("Expected error for mismatched related type")
+		}
+	})
+}
+
+// AssocBase provides the primary key of the association models below through embedding.
+type AssocBase struct {
+	ID int64 `jorm:"pk;auto"`
+}
+
+type AssocAuthor struct {
+	AssocBase
+	Name  string      `jorm:"size:100"`
+	Posts []AssocPost `jorm:"fk:AuthorID;relation:has_many"`
+}
+
+type AssocPost struct {
+	AssocBase
+	AuthorID int64
+	Title    string `jorm:"size:100"`
+}
+
+func TestAssociationEmbeddedPK(t *testing.T) {
+	db, err := core.Open("sqlite3", ":memory:", &core.Options{MaxOpenConns: 1})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+	if err := db.AutoMigrate(&AssocAuthor{}, &AssocPost{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	author := &AssocAuthor{Name: "Ann"}
+	if _, err := db.Model(author).Insert(author); err != nil {
+		t.Fatalf("Failed to insert author: %v", err)
+	}
+	existing := &AssocPost{Title: "existing"}
+	if _, err := db.Model(existing).Insert(existing); err != nil {
+		t.Fatalf("Failed to insert post: %v", err)
+	}
+
+	fresh := &AssocPost{Title: "fresh"}
+	if err := db.Model(author).Association("Posts").Append(existing, fresh); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	if fresh.ID == 0 || fresh.AuthorID != author.ID {
+		t.Errorf("Expected the new post to be inserted for the author, got %+v", fresh)
+	}
+	count, err := db.Model(&AssocPost{}).Where("author_id = ?", author.ID).Count()
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 posts for the author, got %d", count)
+	}
+
+	if err := db.Model(author).Association("Posts").Delete(existing); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	count, err = db.Model(&AssocPost{}).Where("author_id = ?", author.ID).Count()
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 post for the author after delete, got %d", count)
+	}
+}
+
+func TestAssociationInvalidatesCache(t *testing.T) {
+	db, err := core.Open("sqlite3", ":memory:", &core.Options{MaxOpenConns: 1})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+	if err := db.AutoMigrate(&AssocAuthor{}, &AssocPost{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	db.Use(middleware.NewMemoryCache(time.Hour))
+
+	author := &AssocAuthor{Name: "Ann"}
+	if _, err := db.Model(author).Insert(author); err != nil {
+		t.Fatalf("Failed to insert author: %v", err)
+	}
+	post := &AssocPost{Title: "existing"}
+	if _, err := db.Model(post).Insert(post); err != nil {
+		t.Fatalf("Failed to insert post: %v", err)
+	}
+
+	posts := func() int {
+		t.Helper()
+		var got []AssocPost
+		if err := db.Model(&AssocPost{}).Where("author_id = ?", author.ID).Cache().Find(&got); err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		return len(got)
+	}
+	if n := posts(); n != 0 {
+		t.Fatalf("Expected no posts for the author, got %d", n)
+	}
+
+	if err := db.Model(author).Association("Posts").Append(post); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	if n := posts(); n != 1 {
+		t.Errorf("Expected Append to drop the cached posts, got %d posts", n)
+	}
+
+	if err := db.Model(author).Association("Posts").Delete(post); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if n := posts(); n != 0 {
+		t.Errorf("Expected Delete to drop the cached posts, got %d posts", n)
+	}
+}
+
 func TestPreloadNested(t *testing.T) {
 	db := setupPreloadDB(t)
 	defer db.Close()
@@ -663,6 +947,53 @@ func TestJoinsLeft(t *testing.T) {
 	}
 }
 
+func TestJoinsNestedStruct(t *testing.T) {
+	db := setupPreloadDB(t)
+	defer db.Close()
+	defer cleanupPreloadDB(db)
+
+	user := &PreloadUser{Name: "Liam", Email: "liam@example.com", Age: 31}
+	userID, err := db.Model(user).Insert(user)
+	if err != nil {
+		t.Fatalf("Failed to insert user: %v", err)
+	}
+	order := &PreloadOrder{UserID: userID, Amount: 99.5, Status: "paid"}
+	if _, err := db.Model(order).Insert(order); err != nil {
+		t.Fatalf("Failed to insert order: %v", err)
+	}
+
+	type OrderWithNestedUser struct {
+		PreloadOrder
+		User PreloadUser
+	}
+
+	// Label the joined columns "preload_user.<column>" so they are routed into User
+	columns := []string{"preload_order.*"}
+	for _, col := range []string{"id", "name", "email", "age", "created_at", "updated_at"} {
+		columns = append(columns, fmt.Sprintf(`preload_user.%s AS "preload_user.%s"`, col, col))
+	}
+	var results []OrderWithNestedUser
+	err = db.Model(&PreloadOrder{}).
+		Select(columns...).
+		Joins("INNER JOIN preload_user ON preload_user.id = preload_order.user_id").
+		Find(&results)
+	if err != nil {
+		t.Fatalf("Failed to find orders with joins: %v", err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	got := results[0]
+	if got.ID != order.ID || got.UserID != userID || got.Amount != 99.5 || got.Status != "paid" {
+		t.Errorf("Expected the order columns on the embedded order, got %+v", got.PreloadOrder)
+	}
+	u := got.User
+	if u.ID != userID || u.Name != "Liam" || u.Email != "liam@example.com" || u.Age != 31 || u.CreatedAt.IsZero() || u.UpdatedAt.IsZero() {
+		t.Errorf("Expected the nested user to be fully populated, got %+v", u)
+	}
+}
+
 func TestPreloadFirst(t *testing.T) {
 	db := setupPreloadDB(t)
 	defer db.Close()
@@ -756,3 +1087,305 @@ func TestPreloadPerformance(t *testing.T) {
 		}
 	}
 }
+
+func TestPreloadBatchSize(t *testing.T) {
+	db := setupPreloadDB(t)
+	defer db.Close()
+	defer cleanupPreloadDB(db)
+
+	const userCount = 3000
+	users := make([]*PreloadUser, userCount)
+	for i := range users {
+		users[i] = &PreloadUser{Name: fmt.Sprintf("User%d", i), Email: fmt.Sprintf("user%d@example.com", i)}
+	}
+	if _, err := db.Model(&PreloadUser{}).BatchInsert(users); err != nil {
+		t.Fatalf("Failed to insert users: %v", err)
+	}
+	var inserted []PreloadUser
+	if err := db.Model(&PreloadUser{}).Select("id").Find(&inserted); err != nil {
+		t.Fatalf("Failed to load user ids: %v", err)
+	}
+
+	ids := make([]int64, len(inserted))
+	orders := make([]*PreloadOrder, len(inserted))
+	for i, u := range inserted {
+		ids[i] = u.ID
+		orders[i] = &PreloadOrder{UserID: u.ID, Amount: 10, Status: "completed"}
+	}
+	if _, err := db.Model(&PreloadOrder{}).BatchInsert(orders); err != nil {
+		t.Fatalf("Failed to insert orders: %v", err)
+	}
+	role := &PreloadRole{Name: "member"}
+	roleID, err := db.Model(role).Insert(role)
+	if err != nil {
+		t.Fatalf("Failed to insert role: %v", err)
+	}
+	for _, id := range ids {
+		if _, err := db.Exec("INSERT INTO preload_user_role (user_id, role_id) VALUES (?, ?)", id, roleID); err != nil {
+			t.Fatalf("Failed to link role: %v", err)
+		}
+	}
+
+	for _, size := range []int{0, 7} {
+		db.PreloadBatchSize(size)
+
+		var loaded []PreloadUser
+		if err := db.Model(&PreloadUser{}).Preload("Orders").Preload("Roles").Find(&loaded); err != nil {
+			t.Fatalf("batch size %d: preload failed: %v", size, err)
+		}
+		if len(loaded) != userCount {
+			t.Fatalf("batch size %d: expected %d users, got %d", size, userCount, len(loaded))
+		}
+		for _, u := range loaded {
+			if len(u.Orders) != 1 || len(u.Roles) != 1 {
+				t.Fatalf("batch size %d: user %d has %d orders and %d roles", size, u.ID, len(u.Orders), len(u.Roles))
+			}
+		}
+
+		var withUsers []PreloadOrder
+		if err := db.Model(&PreloadOrder{}).Preload("User").Find(&withUsers); err != nil {
+			t.Fatalf("batch size %d: belongs-to preload failed: %v", size, err)
+		}
+		for _, o := range withUsers {
+			if o.User == nil || o.User.ID != o.UserID {
+				t.Fatalf("batch size %d: order %d missing its user", size, o.ID)
+			}
+		}
+	}
+}
+
+func TestPreloadStreamsRelatedRows(t *testing.T) {
+	db := setupPreloadDB(t)
+	defer db.Close()
+	defer cleanupPreloadDB(db)
+
+	const userCount, ordersPerUser = 50, 40
+	users := make([]*PreloadUser, userCount)
+	for i := range users {
+		users[i] = &PreloadUser{Name: fmt.Sprintf("User%d", i), Email: fmt.Sprintf("stream%d@example.com", i)}
+	}
+	if _, err := db.Model(&PreloadUser{}).BatchInsert(users); err != nil {
+		t.Fatalf("Failed to insert users: %v", err)
+	}
+	var inserted []PreloadUser
+	if err := db.Model(&PreloadUser{}).Select("id").OrderBy("id").Find(&inserted); err != nil {
+		t.Fatalf("Failed to load user ids: %v", err)
+	}
+
+	// Every user but the last gets orders, inserted interleaved across users
+	var orders []*PreloadOrder
+	for j := 0; j < ordersPerUser; j++ {
+		for _, u := range inserted[:userCount-1] {
+			orders = append(orders, &PreloadOrder{UserID: u.ID, Amount: float64(j), Status: "completed"})
+		}
+	}
+	if _, err := db.Model(&PreloadOrder{}).BatchInsert(orders); err != nil {
+		t.Fatalf("Failed to insert orders: %v", err)
+	}
+	profile := &PreloadProfile{UserID: inserted[0].ID, Bio: "first"}
+	if _, err := db.Model(profile).Insert(profile); err != nil {
+		t.Fatalf("Failed to insert profile: %v", err)
+	}
+
+	load := func() []PreloadUser {
+		var loaded []PreloadUser
+		err := db.Model(&PreloadUser{}).OrderBy("id").
+			PreloadWith("Orders", func(q *core.Query) { q.OrderBy("amount DESC") }).
+			Preload("Profile").
+			Find(&loaded)
+		if err != nil {
+			t.Fatalf("Preload failed: %v", err)
+		}
+		return loaded
+	}
+
+	db.PreloadBatchSize(16)
+	loaded := load()
+	if len(loaded) != userCount {
+		t.Fatalf("Expected %d users, got %d", userCount, len(loaded))
+	}
+	for i, u := range loaded[:userCount-1] {
+		if len(u.Orders) != ordersPerUser {
+			t.Fatalf("User %d: expected %d orders, got %d", u.ID, ordersPerUser, len(u.Orders))
+		}
+		for j, o := range u.Orders {
+			if o.UserID != u.ID || o.Amount != float64(ordersPerUser-1-j) {
+				t.Fatalf("User %d: order %d is %+v", u.ID, j, o)
+			}
+		}
+		if (u.Profile != nil) != (i == 0) {
+			t.Errorf("User %d: unexpected profile %v", u.ID, u.Profile)
+		}
+	}
+	if last := loaded[userCount-1]; last.Orders != nil || last.Profile != nil {
+		t.Errorf("User without related rows got %d orders and profile %v", len(last.Orders), last.Profile)
+	}
+
+	// Related rows are assigned as they are scanned, so beyond what plain queries of
+	// the same rows allocate, a preload must not buffer anything per related row
+	mallocs := func(fn func()) uint64 {
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		fn()
+		runtime.ReadMemStats(&after)
+		return after.Mallocs - before.Mallocs
+	}
+	db.PreloadBatchSize(0)
+	plain := mallocs(func() {
+		var us []PreloadUser
+		var os []PreloadOrder
+		var ps []PreloadProfile
+		db.Model(&PreloadUser{}).Find(&us)
+		db.Model(&PreloadOrder{}).Find(&os)
+		db.Model(&PreloadProfile{}).Find(&ps)
+	})
+	preload := mallocs(func() { load() })
+	if related := uint64(len(orders) + 1); preload > plain+2*related {
+		t.Errorf("Preload made %d allocations, plain queries %d, for %d related rows", preload, plain, related)
+	}
+}
+
+type OrderedAuthor struct {
+	ID         int64         `jorm:"pk;auto"`
+	Name       string        `jorm:"size:100"`
+	Posts      []OrderedPost `jorm:"fk:AuthorID;relation:has_many;order:rank DESC"`
+	PostsCount int           `jorm:"-"`
+}
+
+type OrderedPost struct {
+	ID       int64  `jorm:"pk;auto"`
+	AuthorID int64  `jorm:"fk:OrderedAuthor.ID"`
+	Title    string `jorm:"size:100"`
+	Rank     int    `jorm:"default:0"`
+}
+
+func TestPreloadOrderTag(t *testing.T) {
+	db := setupPreloadDB(t)
+	defer db.Close()
+
+	if tag := model.ParseTag("fk:AuthorID;relation:has_many;order:rank desc"); tag.Order != "rank DESC" {
+		t.Errorf("Expected order %q, got %q", "rank DESC", tag.Order)
+	}
+
+	if err := db.AutoMigrate(&OrderedAuthor{}, &OrderedPost{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	author := &OrderedAuthor{Name: "Ann"}
+	authorID, err := db.Model(author).Insert(author)
+	if err != nil {
+		t.Fatalf("Failed to insert author: %v", err)
+	}
+	for _, rank := range []int{2, 3, 1} {
+		post := &OrderedPost{AuthorID: authorID, Title: fmt.Sprintf("Post%d", rank), Rank: rank}
+		if _, err := db.Model(post).Insert(post); err != nil {
+			t.Fatalf("Failed to insert post: %v", err)
+		}
+	}
+
+	var authors []OrderedAuthor
+	if err := db.Model(&OrderedAuthor{}).Preload("Posts").Find(&authors); err != nil {
+		t.Fatalf("Failed to preload posts: %v", err)
+	}
+	if len(authors) != 1 || len(authors[0].Posts) != 3 {
+		t.Fatalf("Expected 1 author with 3 posts, got %+v", authors)
+	}
+	for i, want := range []int{3, 2, 1} {
+		if got := authors[0].Posts[i].Rank; got != want {
+			t.Errorf("Post %d: expected rank %d, got %d", i, want, got)
+		}
+	}
+}
+
+func TestPreloadCount(t *testing.T) {
+	db := setupPreloadDB(t)
+	defer db.Close()
+
+	if err := db.AutoMigrate(&OrderedAuthor{}, &OrderedPost{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	want := map[string]int{"Ann": 3, "Bob": 1, "Cid": 0}
+	for _, name := range []string{"Ann", "Bob", "Cid"} {
+		author := &OrderedAuthor{Name: name}
+		authorID, err := db.Model(author).Insert(author)
+		if err != nil {
+			t.Fatalf("Failed to insert author: %v", err)
+		}
+		for i := 0; i < want[name]; i++ {
+			post := &OrderedPost{AuthorID: authorID, Title: fmt.Sprintf("%s%d", name, i)}
+			if _, err := db.Model(post).Insert(post); err != nil {
+				t.Fatalf("Failed to insert post: %v", err)
+			}
+		}
+	}
+
+	var authors []OrderedAuthor
+	if err := db.Model(&OrderedAuthor{}).PreloadCount("Posts", "PostsCount").OrderBy("id").Find(&authors); err != nil {
+		t.Fatalf("PreloadCount failed: %v", err)
+	}
+	if len(authors) != 3 {
+		t.Fatalf("Expected 3 authors, got %d", len(authors))
+	}
+	for _, a := range authors {
+		if a.PostsCount != want[a.Name] {
+			t.Errorf("%s: expected %d posts, got %d", a.Name, want[a.Name], a.PostsCount)
+		}
+		if len(a.Posts) != 0 {
+			t.Errorf("%s: expected posts not to be loaded, got %d", a.Name, len(a.Posts))
+		}
+	}
+
+	var ann OrderedAuthor
+	err := db.Model(&OrderedAuthor{}).Where("name = ?", "Ann").
+		PreloadCount("Posts", "PostsCount").First(&ann)
+	if err != nil {
+		t.Fatalf("PreloadCount with First failed: %v", err)
+	}
+	if ann.PostsCount != 3 {
+		t.Errorf("Expected 3 posts for Ann, got %d", ann.PostsCount)
+	}
+
+	err = db.Model(&OrderedAuthor{}).PreloadCount("Posts", "Missing").Find(&authors)
+	if !errors.Is(err, core.ErrInvalidModel) {
+		t.Errorf("Expected ErrInvalidModel for a missing count field, got %v", err)
+	}
+}
+
+// CountAuthor has an embedded int64 primary key, which CountPost refers to with an int.
+type CountAuthor struct {
+	AssocBase
+	Name       string      `jorm:"size:100"`
+	Posts      []CountPost `jorm:"fk:AuthorID;relation:has_many"`
+	PostsCount int         `jorm:"-"`
+}
+
+type CountPost struct {
+	ID       int64 `jorm:"pk;auto"`
+	AuthorID int
+}
+
+func TestPreloadCountKeyTypes(t *testing.T) {
+	db := setupPreloadDB(t)
+	defer db.Close()
+
+	if err := db.AutoMigrate(&CountAuthor{}, &CountPost{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	author := &CountAuthor{Name: "Ann"}
+	if _, err := db.Model(author).Insert(author); err != nil {
+		t.Fatalf("Failed to insert author: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		post := &CountPost{AuthorID: int(author.ID)}
+		if _, err := db.Model(post).Insert(post); err != nil {
+			t.Fatalf("Failed to insert post: %v", err)
+		}
+	}
+
+	var authors []CountAuthor
+	if err := db.Model(&CountAuthor{}).PreloadCount("Posts", "PostsCount").Find(&authors); err != nil {
+		t.Fatalf("PreloadCount failed: %v", err)
+	}
+	if len(authors) != 1 || authors[0].PostsCount != 2 {
+		t.Errorf("Expected 1 author with 2 posts, got %+v", authors)
+	}
+}
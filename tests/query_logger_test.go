@@ -2,10 +2,13 @@ package tests
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/shrek82/jorm/core"
 	"github.com/shrek82/jorm/logger"
 )
 
@@ -29,3 +32,100 @@ func TestSQLLevel(t *testing.T) {
 		t.Errorf("Expected output for SQL at LevelDebug, got: %s", buf.String())
 	}
 }
+
+func TestQueryTagsLogged(t *testing.T) {
+	db := setupCacheDB(t, "query_tags_test.db")
+
+	buf := &bytes.Buffer{}
+	l := logger.NewStdLogger()
+	l.SetLevel(logger.LevelDebug)
+	l.SetFormat(logger.FormatJSON)
+	l.SetOutput(buf)
+	db.SetLogger(l)
+
+	ctx := core.WithQueryTags(context.Background(), map[string]any{"tenant": "acme"})
+	ctx = core.WithQueryTags(ctx, map[string]any{"trace_id": "t-1"})
+
+	var users []CacheUser
+	if err := db.Model(&CacheUser{}).WithContext(ctx).Find(&users); err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &data); err != nil {
+		t.Fatalf("Failed to unmarshal JSON output %q: %v", buf.String(), err)
+	}
+	if data["tenant"] != "acme" || data["trace_id"] != "t-1" {
+		t.Errorf("Expected query tags in SQL log, got %v", data)
+	}
+	if !strings.Contains(data["sql"].(string), "cache_user") {
+		t.Errorf("Unexpected SQL in log: %v", data["sql"])
+	}
+
+	// Tags also reach queries started from a DB bound to the context
+	buf.Reset()
+	if err := db.WithContext(ctx).Model(&CacheUser{}).Find(&users); err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	data = nil
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &data); err != nil {
+		t.Fatalf("Failed to unmarshal JSON output %q: %v", buf.String(), err)
+	}
+	if data["tenant"] != "acme" || data["trace_id"] != "t-1" {
+		t.Errorf("Expected query tags in SQL log of DB.WithContext, got %v", data)
+	}
+
+	// Queries without tagged context keep the plain logger
+	buf.Reset()
+	if err := db.Model(&CacheUser{}).Find(&users); err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "tenant") {
+		t.Errorf("Untagged query should not log tags: %s", buf.String())
+	}
+}
+
+func TestQueryWithLogger(t *testing.T) {
+	db := setupCacheDB(t, "query_with_logger_test.db")
+
+	newLogger := func(buf *bytes.Buffer) logger.Logger {
+		l := logger.NewStdLogger()
+		l.SetLevel(logger.LevelDebug)
+		l.SetOutput(buf)
+		return l
+	}
+	defaultBuf, auditBuf := &bytes.Buffer{}, &bytes.Buffer{}
+	db.SetLogger(newLogger(defaultBuf))
+	audit := newLogger(auditBuf)
+
+	var users []CacheUser
+	if err := db.Model(&CacheUser{}).WithLogger(audit).Where("name = ?", "audited").Find(&users); err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if !strings.Contains(auditBuf.String(), "cache_user") {
+		t.Errorf("Expected SQL in the query logger, got: %q", auditBuf.String())
+	}
+	if defaultBuf.Len() > 0 {
+		t.Errorf("Expected no output from the default logger, got: %q", defaultBuf.String())
+	}
+
+	// Errors of the query go to the overridden logger too
+	auditBuf.Reset()
+	if err := db.Model(&CacheUser{}).WithLogger(audit).Where("missing_column = ?", 1).Find(&users); err == nil {
+		t.Fatal("Expected an error for a missing column")
+	}
+	if !strings.Contains(auditBuf.String(), "missing_column") {
+		t.Errorf("Expected the error in the query logger, got: %q", auditBuf.String())
+	}
+	if defaultBuf.Len() > 0 {
+		t.Errorf("Expected no output from the default logger, got: %q", defaultBuf.String())
+	}
+
+	// Other queries keep the DB's logger
+	if err := db.Model(&CacheUser{}).Find(&users); err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if !strings.Contains(defaultBuf.String(), "cache_user") {
+		t.Errorf("Expected SQL in the default logger, got: %q", defaultBuf.String())
+	}
+}
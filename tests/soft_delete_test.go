@@ -0,0 +1,646 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/shrek82/jorm/core"
+	"github.com/shrek82/jorm/middleware"
+)
+
+type SoftDeleteNote struct {
+	ID        int64  `jorm:"pk auto"`
+	Title     string `jorm:"size:100"`
+	DeletedAt *time.Time
+}
+
+func setupSoftDeleteDB(t *testing.T) (*core.DB, func()) {
+	t.Helper()
+	dbFile := "soft_delete_test.db"
+	_ = os.Remove(dbFile)
+
+	db, err := core.Open("sqlite3", dbFile, &core.Options{
+		MaxOpenConns: 1,
+	})
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	if err := db.AutoMigrate(&SoftDeleteNote{}); err != nil {
+		db.Close()
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+
+	cleanup := func() {
+		db.Close()
+		_ = os.Remove(dbFile)
+	}
+	return db, cleanup
+}
+
+// seedSoftDeleteNotes inserts active notes a, b, c and soft-deletes b.
+func seedSoftDeleteNotes(t *testing.T, db *core.DB) []*SoftDeleteNote {
+	t.Helper()
+	notes := []*SoftDeleteNote{{Title: "a"}, {Title: "b"}, {Title: "c"}}
+	for _, n := range notes {
+		if _, err := db.Model(n).Insert(n); err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+	}
+	if _, err := db.Model(notes[1]).Delete(notes[1]); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	return notes
+}
+
+func noteTitles(notes []SoftDeleteNote) []string {
+	titles := make([]string, len(notes))
+	for i, n := range notes {
+		titles[i] = n.Title
+	}
+	return titles
+}
+
+func equalTitles(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSoftDeleteScopes(t *testing.T) {
+	db, cleanup := setupSoftDeleteDB(t)
+	defer cleanup()
+	notes := seedSoftDeleteNotes(t, db)
+
+	t.Run("DeleteSetsDeletedAt", func(t *testing.T) {
+		if notes[1].DeletedAt == nil {
+			t.Fatal("expected DeletedAt to be set on the deleted value")
+		}
+		count, err := db.Model(&SoftDeleteNote{}).Unscoped().Count()
+		if err != nil {
+			t.Fatalf("Count failed: %v", err)
+		}
+		if count != 3 {
+			t.Errorf("expected the row to be kept, got %d rows", count)
+		}
+	})
+
+	tests := []struct {
+		name  string
+		scope func(q *core.Query) *core.Query
+		want  []string
+	}{
+		{"Default", func(q *core.Query) *core.Query { return q }, []string{"a", "c"}},
+		{"Unscoped", (*core.Query).Unscoped, []string{"a", "b", "c"}},
+		{"WithTrashed", (*core.Query).WithTrashed, []string{"a", "b", "c"}},
+		{"OnlyTrashed", (*core.Query).OnlyTrashed, []string{"b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []SoftDeleteNote
+			if err := tt.scope(db.Model(&SoftDeleteNote{})).OrderBy("id").Find(&got); err != nil {
+				t.Fatalf("Find failed: %v", err)
+			}
+			if titles := noteTitles(got); !equalTitles(titles, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, titles)
+			}
+
+			count, err := tt.scope(db.Model(&SoftDeleteNote{})).Count()
+			if err != nil {
+				t.Fatalf("Count failed: %v", err)
+			}
+			if count != int64(len(tt.want)) {
+				t.Errorf("expected count %d, got %d", len(tt.want), count)
+			}
+		})
+	}
+
+	t.Run("ComposesWithWhere", func(t *testing.T) {
+		var got []SoftDeleteNote
+		err := db.Model(&SoftDeleteNote{}).Where("title IN (?, ?)", "a", "b").OrWhere("title = ?", "c").
+			OrderBy("id").Find(&got)
+		if err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		if titles := noteTitles(got); !equalTitles(titles, []string{"a", "c"}) {
+			t.Errorf("expected [a c], got %v", titles)
+		}
+
+		got = nil
+		err = db.Model(&SoftDeleteNote{}).OnlyTrashed().Where("title = ?", "a").Find(&got)
+		if err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		if len(got) != 0 {
+			t.Errorf("expected no trashed note titled a, got %v", noteTitles(got))
+		}
+	})
+
+	t.Run("FirstSkipsDeleted", func(t *testing.T) {
+		var n SoftDeleteNote
+		err := db.Model(&SoftDeleteNote{}).Where("title = ?", "b").First(&n)
+		if err == nil {
+			t.Fatal("expected deleted note to be hidden from First")
+		}
+		if err := db.Model(&SoftDeleteNote{}).Unscoped().Where("title = ?", "b").First(&n); err != nil {
+			t.Fatalf("Unscoped First failed: %v", err)
+		}
+	})
+
+	t.Run("UnscopedDeleteRemovesRow", func(t *testing.T) {
+		if _, err := db.Model(&SoftDeleteNote{}).Unscoped().Delete(notes[2]); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		count, err := db.Model(&SoftDeleteNote{}).Unscoped().Count()
+		if err != nil {
+			t.Fatalf("Count failed: %v", err)
+		}
+		if count != 2 {
+			t.Errorf("expected 2 rows after permanent delete, got %d", count)
+		}
+	})
+}
+
+func TestSoftDeleteRestore(t *testing.T) {
+	db, cleanup := setupSoftDeleteDB(t)
+	defer cleanup()
+	notes := seedSoftDeleteNotes(t, db)
+
+	t.Run("ByValue", func(t *testing.T) {
+		rows, err := db.Model(notes[1]).Restore(notes[1])
+		if err != nil {
+			t.Fatalf("Restore failed: %v", err)
+		}
+		if rows != 1 {
+			t.Errorf("expected 1 row restored, got %d", rows)
+		}
+		if notes[1].DeletedAt != nil {
+			t.Error("expected DeletedAt to be cleared on the restored value")
+		}
+
+		var n SoftDeleteNote
+		if err := db.Model(&SoftDeleteNote{}).Where("title = ?", "b").First(&n); err != nil {
+			t.Fatalf("restored note not found: %v", err)
+		}
+	})
+
+	t.Run("ByWhere", func(t *testing.T) {
+		for _, n := range notes {
+			if _, err := db.Model(n).Delete(n); err != nil {
+				t.Fatalf("Delete failed: %v", err)
+			}
+		}
+		rows, err := db.Model(&SoftDeleteNote{}).Where("title <> ?", "c").Restore()
+		if err != nil {
+			t.Fatalf("Restore failed: %v", err)
+		}
+		if rows != 2 {
+			t.Errorf("expected 2 rows restored, got %d", rows)
+		}
+
+		var got []SoftDeleteNote
+		if err := db.Model(&SoftDeleteNote{}).OrderBy("id").Find(&got); err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		if titles := noteTitles(got); !equalTitles(titles, []string{"a", "b"}) {
+			t.Errorf("expected [a b], got %v", titles)
+		}
+	})
+
+	t.Run("InvalidatesCache", func(t *testing.T) {
+		cacheDir := "./cache_restore"
+		os.RemoveAll(cacheDir)
+		defer os.RemoveAll(cacheDir)
+		db.Use(middleware.NewMemoryCache(time.Hour), middleware.NewFileCache(cacheDir, time.Hour))
+
+		find := func() []string {
+			t.Helper()
+			var got []SoftDeleteNote
+			if err := db.Model(&SoftDeleteNote{}).OrderBy("id").Cache().Find(&got); err != nil {
+				t.Fatalf("Find failed: %v", err)
+			}
+			return noteTitles(got)
+		}
+		if titles := find(); !equalTitles(titles, []string{"a", "b"}) {
+			t.Fatalf("expected [a b], got %v", titles)
+		}
+		if _, err := db.Model(notes[2]).Restore(notes[2]); err != nil {
+			t.Fatalf("Restore failed: %v", err)
+		}
+		// Both caches must drop the table's results, or the file cache serves them again
+		if titles := find(); !equalTitles(titles, []string{"a", "b", "c"}) {
+			t.Errorf("expected [a b c] after Restore, got %v", titles)
+		}
+	})
+
+	t.Run("WithoutDeletedAt", func(t *testing.T) {
+		_, err := db.Model(&User{}).Where("id = ?", 1).Restore()
+		if !errors.Is(err, core.ErrInvalidModel) {
+			t.Errorf("expected ErrInvalidModel, got %v", err)
+		}
+	})
+}
+
+type ArchivedNote struct {
+	ID        int64  `jorm:"pk auto"`
+	Title     string `jorm:"size:100"`
+	RemovedAt *time.Time
+}
+
+type FlaggedNote struct {
+	ID        int64  `jorm:"pk auto"`
+	Title     string `jorm:"size:100"`
+	IsDeleted bool   `jorm:"notnull default:0"`
+}
+
+type SoftDeleteBook struct {
+	ID            int64               `jorm:"pk auto"`
+	Title         string              `jorm:"size:100"`
+	Chapters      []SoftDeleteChapter `jorm:"fk:BookID;relation:has_many"`
+	ChaptersCount int                 `jorm:"-"`
+}
+
+type SoftDeleteChapter struct {
+	ID        int64  `jorm:"pk auto"`
+	BookID    int64  `jorm:"index"`
+	Title     string `jorm:"size:100"`
+	DeletedAt *time.Time
+}
+
+func TestSoftDeleteRelatedQueries(t *testing.T) {
+	db, cleanup := setupSoftDeleteDB(t)
+	defer cleanup()
+	if err := db.AutoMigrate(&SoftDeleteBook{}, &SoftDeleteChapter{}); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+	notes := seedSoftDeleteNotes(t, db)
+
+	book := &SoftDeleteBook{Title: "book"}
+	if _, err := db.Model(book).Insert(book); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+	chapters := []*SoftDeleteChapter{{BookID: book.ID, Title: "one"}, {BookID: book.ID, Title: "two"}}
+	for _, c := range chapters {
+		if _, err := db.Model(c).Insert(c); err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+	}
+	if _, err := db.Model(chapters[1]).Delete(chapters[1]); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	t.Run("WhereExists", func(t *testing.T) {
+		// b is soft-deleted, so the subquery finds no row
+		deleted := db.Model(&SoftDeleteNote{}).Select("1").Where("title = ?", notes[1].Title)
+		var got []SoftDeleteNote
+		if err := db.Model(&SoftDeleteNote{}).WhereExists(deleted).Find(&got); err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		if len(got) != 0 {
+			t.Errorf("expected the subquery to skip deleted rows, got %v", noteTitles(got))
+		}
+	})
+
+	t.Run("FromSubQuery", func(t *testing.T) {
+		var got []SoftDeleteNote
+		if err := db.FromSubQuery(db.Model(&SoftDeleteNote{}), "n").OrderBy("n.title").Find(&got); err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		if !equalTitles(noteTitles(got), []string{"a", "c"}) {
+			t.Errorf("expected the derived table to skip deleted rows, got %v", noteTitles(got))
+		}
+	})
+
+	t.Run("Preload", func(t *testing.T) {
+		var books []SoftDeleteBook
+		if err := db.Model(&SoftDeleteBook{}).Preload("Chapters").PreloadCount("Chapters", "ChaptersCount").Find(&books); err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		if len(books) != 1 || len(books[0].Chapters) != 1 || books[0].Chapters[0].Title != "one" {
+			t.Fatalf("expected only the active chapter, got %+v", books)
+		}
+		if books[0].ChaptersCount != 1 {
+			t.Errorf("expected PreloadCount to skip deleted chapters, got %d", books[0].ChaptersCount)
+		}
+
+		err := db.Model(&SoftDeleteBook{}).PreloadWith("Chapters", func(q *core.Query) { q.WithTrashed() }).Find(&books)
+		if err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		if len(books) != 1 || len(books[0].Chapters) != 2 {
+			t.Errorf("expected WithTrashed to load both chapters, got %+v", books)
+		}
+	})
+}
+
+// ValueDeletedNote has a non-pointer DeletedAt, which is a plain column: zero times are
+// filled on insert, so it could not mark active rows with NULL.
+type ValueDeletedNote struct {
+	ID        int64  `jorm:"pk auto"`
+	Title     string `jorm:"size:100"`
+	DeletedAt time.Time
+}
+
+func TestSoftDeleteValueTime(t *testing.T) {
+	db, cleanup := openSoftDeleteColumnDB(t, "deleted_at", &ValueDeletedNote{})
+	defer cleanup()
+
+	note := &ValueDeletedNote{Title: "a"}
+	if _, err := db.Model(note).Insert(note); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+	var got []ValueDeletedNote
+	if err := db.Model(&ValueDeletedNote{}).Find(&got); err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if len(got) != 1 {
+		t.Errorf("expected the inserted note to be found, got %d rows", len(got))
+	}
+
+	if _, err := db.Model(note).Delete(note); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	count, err := db.Model(&ValueDeletedNote{}).Unscoped().Count()
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("expected Delete to remove the row, %d left", count)
+	}
+}
+
+// openSoftDeleteColumnDB opens a database marking soft-deleted rows with column.
+func openSoftDeleteColumnDB(t *testing.T, column string, models ...any) (*core.DB, func()) {
+	t.Helper()
+	dbFile := "soft_delete_column_test.db"
+	_ = os.Remove(dbFile)
+
+	db, err := core.Open("sqlite3", dbFile, &core.Options{MaxOpenConns: 1, SoftDeleteColumn: column})
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	if err := db.AutoMigrate(models...); err != nil {
+		db.Close()
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+	return db, func() {
+		db.Close()
+		_ = os.Remove(dbFile)
+	}
+}
+
+func TestSoftDeleteColumn(t *testing.T) {
+	t.Run("Timestamp", func(t *testing.T) {
+		db, cleanup := openSoftDeleteColumnDB(t, "removed_at", &ArchivedNote{})
+		defer cleanup()
+
+		notes := []*ArchivedNote{{Title: "a"}, {Title: "b"}}
+		for _, n := range notes {
+			if _, err := db.Model(n).Insert(n); err != nil {
+				t.Fatalf("Insert failed: %v", err)
+			}
+		}
+		if _, err := db.Model(notes[1]).Delete(notes[1]); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if notes[1].RemovedAt == nil {
+			t.Error("expected RemovedAt to be set on the deleted value")
+		}
+
+		sqlStr, _ := db.Model(&ArchivedNote{}).GetSelectSQL()
+		if !strings.Contains(sqlStr, "`removed_at` IS NULL") {
+			t.Errorf("expected removed_at filter, got %s", sqlStr)
+		}
+		var got []ArchivedNote
+		if err := db.Model(&ArchivedNote{}).Find(&got); err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		if len(got) != 1 || got[0].Title != "a" {
+			t.Errorf("expected only a, got %+v", got)
+		}
+		if count, err := db.Model(&ArchivedNote{}).OnlyTrashed().Count(); err != nil || count != 1 {
+			t.Errorf("expected 1 trashed note, got %d (%v)", count, err)
+		}
+
+		if _, err := db.Model(notes[1]).Restore(notes[1]); err != nil {
+			t.Fatalf("Restore failed: %v", err)
+		}
+		if notes[1].RemovedAt != nil {
+			t.Error("expected RemovedAt to be cleared on the restored value")
+		}
+		if count, err := db.Model(&ArchivedNote{}).Count(); err != nil || count != 2 {
+			t.Errorf("expected 2 notes after restore, got %d (%v)", count, err)
+		}
+	})
+
+	t.Run("BoolFlag", func(t *testing.T) {
+		db, cleanup := openSoftDeleteColumnDB(t, "is_deleted", &FlaggedNote{}, &SoftDeleteNote{})
+		defer cleanup()
+
+		notes := []*FlaggedNote{{Title: "a"}, {Title: "b"}, {Title: "c"}}
+		for _, n := range notes {
+			if _, err := db.Model(n).Insert(n); err != nil {
+				t.Fatalf("Insert failed: %v", err)
+			}
+		}
+		if _, err := db.Model(notes[1]).Delete(notes[1]); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if !notes[1].IsDeleted {
+			t.Error("expected IsDeleted to be set on the deleted value")
+		}
+
+		sqlStr, args := db.Model(&FlaggedNote{}).GetSelectSQL()
+		if !strings.Contains(sqlStr, "`is_deleted` = ?") || len(args) != 1 || args[0] != false {
+			t.Errorf("expected is_deleted = false filter, got %s %v", sqlStr, args)
+		}
+		if count, err := db.Model(&FlaggedNote{}).Unscoped().Count(); err != nil || count != 3 {
+			t.Errorf("expected the row to be kept, got %d rows (%v)", count, err)
+		}
+
+		var got []FlaggedNote
+		if err := db.Model(&FlaggedNote{}).OrderBy("id").Find(&got); err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		if len(got) != 2 || got[0].Title != "a" || got[1].Title != "c" {
+			t.Errorf("expected [a c], got %+v", got)
+		}
+		got = nil
+		if err := db.Model(&FlaggedNote{}).OnlyTrashed().Find(&got); err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		if len(got) != 1 || got[0].Title != "b" || !got[0].IsDeleted {
+			t.Errorf("expected trashed b, got %+v", got)
+		}
+
+		rows, err := db.Model(notes[1]).Restore(notes[1])
+		if err != nil {
+			t.Fatalf("Restore failed: %v", err)
+		}
+		if rows != 1 || notes[1].IsDeleted {
+			t.Errorf("expected 1 row restored and IsDeleted cleared, got %d rows, %v", rows, notes[1].IsDeleted)
+		}
+		if count, err := db.Model(&FlaggedNote{}).Count(); err != nil || count != 3 {
+			t.Errorf("expected 3 notes after restore, got %d (%v)", count, err)
+		}
+
+		// Models without the configured column keep using DeletedAt
+		n := &SoftDeleteNote{Title: "x"}
+		if _, err := db.Model(n).Insert(n); err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+		if _, err := db.Model(n).Delete(n); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if n.DeletedAt == nil {
+			t.Error("expected DeletedAt to be set on a model without is_deleted")
+		}
+	})
+}
+
+func TestDeleteByIDs(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	var ids []any
+	for i := 1; i <= 4; i++ {
+		u := &User{Name: fmt.Sprintf("User%d", i), Email: fmt.Sprintf("user%d@example.com", i)}
+		id, err := db.Model(u).Insert(u)
+		if err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	rows, err := db.Model(&User{}).DeleteByIDs(ids[:3]...)
+	if err != nil {
+		t.Fatalf("DeleteByIDs failed: %v", err)
+	}
+	if rows != 3 {
+		t.Errorf("Expected 3 rows deleted, got %d", rows)
+	}
+	var remaining []User
+	if err := db.Model(&User{}).Find(&remaining); err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if len(remaining) != 1 || remaining[0].ID != ids[3] {
+		t.Errorf("Expected only user %v to remain, got %+v", ids[3], remaining)
+	}
+
+	if rows, err := db.Model(&User{}).DeleteByIDs(); err != nil || rows != 0 {
+		t.Errorf("Expected empty DeleteByIDs to be a no-op, got %d, %v", rows, err)
+	}
+	if _, err := db.Model(&User{}).WhereJSON("profile", "a", "~", 1).DeleteByIDs(ids[3]); !errors.Is(err, core.ErrInvalidQuery) {
+		t.Errorf("Expected the query's own error from DeleteByIDs, got %v", err)
+	}
+
+	t.Run("SoftDelete", func(t *testing.T) {
+		db, cleanup := setupSoftDeleteDB(t)
+		defer cleanup()
+		notes := seedSoftDeleteNotes(t, db)
+
+		rows, err := db.Model(&SoftDeleteNote{}).DeleteByIDs(notes[0].ID, notes[2].ID)
+		if err != nil {
+			t.Fatalf("DeleteByIDs failed: %v", err)
+		}
+		if rows != 2 {
+			t.Errorf("Expected 2 rows soft-deleted, got %d", rows)
+		}
+		count, err := db.Model(&SoftDeleteNote{}).OnlyTrashed().Count()
+		if err != nil {
+			t.Fatalf("Count failed: %v", err)
+		}
+		if count != 3 {
+			t.Errorf("Expected all 3 notes to be trashed and kept, got %d", count)
+		}
+	})
+}
+
+type TenantNote struct {
+	ID        int64 `jorm:"pk;auto"`
+	TenantID  int64
+	Title     string `jorm:"size:100"`
+	DeletedAt *time.Time
+}
+
+func (n *TenantNote) DefaultScope() string {
+	return "tenant_id = 1"
+}
+
+func TestDefaultScope(t *testing.T) {
+	db, cleanup := setupSoftDeleteDB(t)
+	defer cleanup()
+	if err := db.AutoMigrate(&TenantNote{}); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+	for _, n := range []*TenantNote{{TenantID: 1, Title: "a"}, {TenantID: 2, Title: "b"}, {TenantID: 1, Title: "c"}} {
+		if _, err := db.Model(n).Insert(n); err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+	}
+
+	findTitles := func(q *core.Query) []string {
+		t.Helper()
+		var got []TenantNote
+		if err := q.OrderBy("id").Find(&got); err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		titles := make([]string, len(got))
+		for i, n := range got {
+			titles[i] = n.Title
+		}
+		return titles
+	}
+
+	if titles := findTitles(db.Model(&TenantNote{})); !equalTitles(titles, []string{"a", "c"}) {
+		t.Errorf("Expected tenant 1 notes [a c], got %v", titles)
+	}
+	if titles := findTitles(db.Model(&TenantNote{}).Where("title = ?", "a").OrWhere("title = ?", "b")); !equalTitles(titles, []string{"a"}) {
+		t.Errorf("Expected OrWhere to stay within the scope, got %v", titles)
+	}
+	if titles := findTitles(db.Model(&TenantNote{}).Unscoped()); !equalTitles(titles, []string{"a", "b", "c"}) {
+		t.Errorf("Expected Unscoped to see every tenant, got %v", titles)
+	}
+
+	count, err := db.Model(&TenantNote{}).Count()
+	if err != nil || count != 2 {
+		t.Errorf("Expected Count 2, got %d, %v", count, err)
+	}
+
+	rows, err := db.Model(&TenantNote{}).Where("1 = 1").Update(map[string]any{"title": "x"})
+	if err != nil || rows != 2 {
+		t.Errorf("Expected Update to touch 2 rows, got %d, %v", rows, err)
+	}
+	var b TenantNote
+	if err := db.Model(&TenantNote{}).Unscoped().Where("tenant_id = ?", 2).First(&b); err != nil || b.Title != "b" {
+		t.Errorf("Expected tenant 2 note to be untouched, got %+v, %v", b, err)
+	}
+
+	// Soft delete composes with the default scope
+	rows, err = db.Model(&TenantNote{}).Where("1 = 1").Delete()
+	if err != nil || rows != 2 {
+		t.Errorf("Expected Delete to touch 2 rows, got %d, %v", rows, err)
+	}
+	if titles := findTitles(db.Model(&TenantNote{}).WithTrashed()); !equalTitles(titles, []string{"x", "x"}) {
+		t.Errorf("Expected WithTrashed to keep the default scope, got %v", titles)
+	}
+
+	// A struct update on a Table query picks up the scopes of the struct's model
+	rows, err = db.Table("tenant_note").Update(&TenantNote{Title: "y"})
+	if err != nil || rows != 0 {
+		t.Errorf("Expected struct Update to skip other tenants and deleted rows, got %d, %v", rows, err)
+	}
+	if err := db.Model(&TenantNote{}).Unscoped().Where("tenant_id = ?", 2).First(&b); err != nil || b.Title != "b" {
+		t.Errorf("Expected tenant 2 note to be untouched, got %+v, %v", b, err)
+	}
+}
@@ -0,0 +1,125 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/shrek82/jorm/core"
+)
+
+func TestClassifyError(t *testing.T) {
+	cases := []struct {
+		err  error
+		want error
+	}{
+		{errors.New("Error 1213: Deadlock found when trying to get lock"), core.ErrDeadlock},
+		{errors.New("pq: deadlock detected"), core.ErrDeadlock},
+		{errors.New("pq: could not serialize access due to concurrent update"), core.ErrSerializationFailure},
+		{errors.New("UNIQUE constraint failed: users.email"), core.ErrDuplicateKey},
+		{fmt.Errorf("wrapped: %w", core.ErrDeadlock), core.ErrDeadlock},
+		{errors.New("syntax error"), nil},
+		{nil, nil},
+	}
+	for _, c := range cases {
+		if got := core.ClassifyError(c.err); got != c.want {
+			t.Errorf("ClassifyError(%v) = %v, want %v", c.err, got, c.want)
+		}
+	}
+}
+
+func TestTransactionWithRetry(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	t.Run("RetryOnDeadlock", func(t *testing.T) {
+		attempts := 0
+		err := db.TransactionWithRetry(3, func(tx *core.Tx) error {
+			attempts++
+			if _, err := tx.Table("user").Where("id = ?", 0).Count(); err != nil {
+				return err
+			}
+			if attempts == 1 {
+				return fmt.Errorf("simulated: %w", core.ErrDeadlock)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("TransactionWithRetry failed: %v", err)
+		}
+		if attempts != 2 {
+			t.Errorf("Expected 2 attempts, got %d", attempts)
+		}
+	})
+
+	t.Run("NonRetryable", func(t *testing.T) {
+		attempts := 0
+		boom := errors.New("boom")
+		err := db.TransactionWithRetry(3, func(tx *core.Tx) error {
+			attempts++
+			return boom
+		})
+		if !errors.Is(err, boom) {
+			t.Errorf("Expected boom error, got %v", err)
+		}
+		if attempts != 1 {
+			t.Errorf("Expected 1 attempt for non-retryable error, got %d", attempts)
+		}
+	})
+
+	t.Run("Exhausted", func(t *testing.T) {
+		attempts := 0
+		err := db.TransactionWithRetry(2, func(tx *core.Tx) error {
+			attempts++
+			return core.ErrSerializationFailure
+		})
+		if !errors.Is(err, core.ErrSerializationFailure) {
+			t.Errorf("Expected serialization failure, got %v", err)
+		}
+		if attempts != 2 {
+			t.Errorf("Expected 2 attempts, got %d", attempts)
+		}
+	})
+}
+
+func TestExecBatch(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	err := db.ExecBatch(ctx, []string{
+		"CREATE TABLE batch_notes (id INTEGER PRIMARY KEY, body TEXT)",
+		"INSERT INTO batch_notes (id, body) VALUES (1, 'first')",
+	})
+	if err != nil {
+		t.Fatalf("ExecBatch failed: %v", err)
+	}
+	var body string
+	if err := db.Table("batch_notes").Select("body").Where("id = ?", 1).Value(&body); err != nil || body != "first" {
+		t.Fatalf("Expected the batch to be applied, got %q (%v)", body, err)
+	}
+
+	err = db.ExecBatch(ctx, []string{
+		"CREATE TABLE batch_partial (id INTEGER PRIMARY KEY)",
+		"INSERT INTO batch_notes (id, body) VALUES (2, 'second')",
+		"INSERT INTO batch_notes (id, body) VALUES (1, 'duplicate')",
+	})
+	if err == nil || !strings.Contains(err.Error(), "statement 3") {
+		t.Fatalf("Expected the third statement to fail, got %v", err)
+	}
+	if ok, _ := db.HasTable("batch_partial"); ok {
+		t.Error("Expected the table created by the failed batch to be rolled back")
+	}
+	if n, _ := db.Table("batch_notes").Count(); n != 1 {
+		t.Errorf("Expected the failed batch to insert nothing, got %d rows", n)
+	}
+
+	for _, statements := range [][]string{nil, {"SELECT 1", "  "}} {
+		if err := db.ExecBatch(ctx, statements); !errors.Is(err, core.ErrInvalidQuery) {
+			t.Errorf("Expected ErrInvalidQuery for %q, got %v", statements, err)
+		}
+	}
+}
@@ -568,3 +568,146 @@ func TestValidator_Check_ManualError(t *testing.T) {
 		}
 	})
 }
+
+type TagSignup struct {
+	Name     string `jorm_validate:"required,min_len=3"`
+	Email    string `jorm_validate:"required,email"`
+	Role     string `jorm_validate:"in=admin user"`
+	Age      int    `jorm_validate:"range=18 100"`
+	Referral string `jorm_validate:"optional,min_len=6"`
+	Seats    int    `jorm_validate:"even"`
+}
+
+func TestTagValidation(t *testing.T) {
+	jorm.RegisterRule("even", func(args []string) jorm.Rule {
+		if len(args) > 0 {
+			return nil
+		}
+		return jorm.RuleFunc(func(value any) error {
+			if n, ok := value.(int); !ok || n%2 != 0 {
+				return errors.New("must be even")
+			}
+			return nil
+		})
+	})
+
+	valid := &TagSignup{Name: "Ann", Email: "ann@example.com", Role: "user", Age: 30, Seats: 4}
+	if err := jorm.ValidateTags(valid); err != nil {
+		t.Errorf("Expected a valid struct, got %v", err)
+	}
+
+	invalid := &TagSignup{Name: "Al", Email: "nope", Role: "guest", Age: 12, Referral: "abc", Seats: 3}
+	err := jorm.ValidateTags(invalid)
+	var ve jorm.ValidationErrors
+	if !errors.As(err, &ve) {
+		t.Fatalf("Expected ValidationErrors, got %v", err)
+	}
+	for _, field := range []string{"Name", "Email", "Role", "Age", "Referral", "Seats"} {
+		if len(ve[field]) != 1 {
+			t.Errorf("Expected one error for %s, got %v", field, ve[field])
+		}
+	}
+	if msg := ve["Seats"][0].Error(); msg != "must be even" {
+		t.Errorf("Expected the custom rule's error, got %q", msg)
+	}
+
+	t.Run("InsertWithValidator", func(t *testing.T) {
+		dbName := "validator_tags_test.db"
+		os.Remove(dbName)
+		db, err := jorm.Open("sqlite3", dbName, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			db.Close()
+			os.Remove(dbName)
+		}()
+		if err := db.AutoMigrate(&TagSignup{}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Model(invalid).InsertWithValidator(invalid, jorm.ValidateTags); err == nil {
+			t.Error("Expected the insert to be rejected")
+		}
+		if _, err := db.Model(valid).InsertWithValidator(valid, jorm.ValidateTags); err != nil {
+			t.Errorf("Expected the insert to succeed, got %v", err)
+		}
+	})
+
+	t.Run("InvalidTags", func(t *testing.T) {
+		type Unknown struct {
+			Name string `jorm_validate:"required,shouty"`
+		}
+		if err := jorm.ValidateTags(&Unknown{}); err == nil || !strings.Contains(err.Error(), "shouty") {
+			t.Errorf("Expected an unknown rule error, got %v", err)
+		}
+		type BadArgs struct {
+			Name string `jorm_validate:"min_len=many"`
+		}
+		if err := jorm.ValidateTags(&BadArgs{}); err == nil || !strings.Contains(err.Error(), "min_len") {
+			t.Errorf("Expected an invalid arguments error, got %v", err)
+		}
+	})
+}
+
+type TenantMember struct {
+	ID       int64  `jorm:"pk;auto"`
+	TenantID int64  `jorm:"notnull"`
+	Email    string `jorm:"size:100"`
+}
+
+func TestUniqueInScope(t *testing.T) {
+	dbName := "validator_unique_test.db"
+	os.Remove(dbName)
+	db, err := jorm.Open("sqlite3", dbName, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		db.Close()
+		os.Remove(dbName)
+	}()
+	if err := db.AutoMigrate(&TenantMember{}); err != nil {
+		t.Fatal(err)
+	}
+
+	rules := jorm.Rules{
+		"Email": {db.UniqueInScope("tenant_member", "email", "tenant_id")},
+	}
+	insert := func(tenant int64, email string) (*TenantMember, error) {
+		m := &TenantMember{TenantID: tenant, Email: email}
+		_, err := db.Model(m).InsertWithValidator(m, rules.Validate)
+		return m, err
+	}
+
+	first, err := insert(1, "ann@example.com")
+	if err != nil {
+		t.Fatalf("Expected the first insert to succeed, got %v", err)
+	}
+	if _, err := insert(2, "ann@example.com"); err != nil {
+		t.Errorf("Expected the same email under another tenant to be allowed, got %v", err)
+	}
+	_, err = insert(1, "ann@example.com")
+	var ve jorm.ValidationErrors
+	if !errors.As(err, &ve) || len(ve["Email"]) != 1 {
+		t.Errorf("Expected a duplicate email within a tenant to be rejected, got %v", err)
+	}
+
+	t.Run("ExcludesSelf", func(t *testing.T) {
+		if err := rules.Validate(first); err != nil {
+			t.Errorf("Expected a record not to conflict with itself, got %v", err)
+		}
+	})
+
+	t.Run("UniqueIn", func(t *testing.T) {
+		rule := db.UniqueIn("tenant_member", "email")
+		if err := jorm.Check("ann@example.com", rule); err == nil {
+			t.Error("Expected an existing email to be rejected")
+		}
+		if err := jorm.Check("bob@example.com", rule); err != nil {
+			t.Errorf("Expected a new email to pass, got %v", err)
+		}
+		if err := jorm.Check("ann@example.com", db.UniqueInScope("tenant_member", "email", "tenant_id")); err == nil {
+			t.Error("Expected a scoped rule to require a struct")
+		}
+	})
+}
@@ -508,3 +508,29 @@ func (r *noHTMLRule) Optional() Rule              { nr := *r; nr.SetOptional();
 func (r *noHTMLRule) When(fn func(any) bool) Rule { nr := *r; nr.SetWhen(fn); return &nr }
 
 var NoHTML Rule = &noHTMLRule{}
+
+// --- Func ---
+
+type funcRule struct {
+	BaseRule
+	fn func(any) error
+}
+
+func (r *funcRule) Validate(v any) error {
+	if !r.ShouldValidate(v) {
+		return nil
+	}
+	if err := r.fn(v); err != nil {
+		return r.FormatError(err)
+	}
+	return nil
+}
+
+func (r *funcRule) Msg(msg string) Rule         { nr := *r; nr.SetMsg(msg); return &nr }
+func (r *funcRule) Optional() Rule              { nr := *r; nr.SetOptional(); return &nr }
+func (r *funcRule) When(fn func(any) bool) Rule { nr := *r; nr.SetWhen(fn); return &nr }
+
+// Func turns fn into a Rule, e.g. for a custom rule passed to Register.
+func Func(fn func(value any) error) Rule {
+	return &funcRule{fn: fn}
+}
@@ -0,0 +1,202 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// TagName is the struct tag holding a field's validation rules, e.g.
+//
+//	Email string `jorm_validate:"required,email,max_len=100"`
+//
+// Rules are separated by commas; a rule's arguments follow "=" and are separated by
+// spaces, e.g. "range=18 100" or "in=admin user". The "optional" keyword skips all
+// rules of the field while it holds its zero value.
+const TagName = "jorm_validate"
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]func(args []string) Rule{}
+
+	// tagRules caches the Rules parsed from the tags of each struct type
+	tagRules sync.Map // reflect.Type -> tagRulesEntry
+)
+
+type tagRulesEntry struct {
+	rules Rules
+	err   error
+}
+
+func init() {
+	for name, rule := range map[string]Rule{
+		"required": Required, "email": Email, "mobile": Mobile, "url": URL, "ip": IP,
+		"json": JSON, "uuid": UUID, "numeric": Numeric, "alpha": Alpha,
+		"alphanumeric": AlphaNumeric, "nohtml": NoHTML,
+	} {
+		rule := rule
+		registry[name] = func(args []string) Rule {
+			if len(args) > 0 {
+				return nil
+			}
+			return rule
+		}
+	}
+	registry["min_len"] = intArg(MinLen)
+	registry["max_len"] = intArg(MaxLen)
+	registry["range"] = func(args []string) Rule {
+		if len(args) != 2 {
+			return nil
+		}
+		min, err1 := strconv.ParseFloat(args[0], 64)
+		max, err2 := strconv.ParseFloat(args[1], 64)
+		if err1 != nil || err2 != nil {
+			return nil
+		}
+		return Range(min, max)
+	}
+	registry["in"] = func(args []string) Rule {
+		if len(args) == 0 {
+			return nil
+		}
+		values := make([]any, len(args))
+		for i, a := range args {
+			values[i] = a
+		}
+		return In(values...)
+	}
+	registry["contains"] = stringArg(Contains)
+	registry["excludes"] = stringArg(Excludes)
+	registry["regexp"] = stringArg(Regexp)
+	registry["datetime"] = stringArg(Datetime)
+}
+
+// intArg adapts a rule constructor taking one integer to a Register factory.
+func intArg(fn func(int) Rule) func(args []string) Rule {
+	return func(args []string) Rule {
+		if len(args) != 1 {
+			return nil
+		}
+		n, err := strconv.Atoi(args[0])
+		if err != nil {
+			return nil
+		}
+		return fn(n)
+	}
+}
+
+// stringArg adapts a rule constructor taking one string to a Register factory. The
+// arguments are joined back, so the string may contain spaces, e.g. a datetime layout.
+func stringArg(fn func(string) Rule) func(args []string) Rule {
+	return func(args []string) Rule {
+		if len(args) == 0 {
+			return nil
+		}
+		return fn(strings.Join(args, " "))
+	}
+}
+
+// Register makes a rule available to struct tags under name. The factory receives the
+// rule's arguments from the tag and returns nil if they are invalid. Registering an
+// existing name, including a built-in one, replaces it. Rules should be registered
+// before the structs using them are first validated, as parsed tags are cached.
+func Register(name string, factory func(args []string) Rule) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = factory
+}
+
+// ParseTag parses a jorm_validate tag value into rules, see TagName.
+func ParseTag(tag string) ([]Rule, error) {
+	var rules []Rule
+	optional := false
+	for _, part := range strings.Split(tag, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if part == "optional" {
+			optional = true
+			continue
+		}
+		name, argStr, _ := strings.Cut(part, "=")
+		name = strings.TrimSpace(name)
+
+		registryMu.RLock()
+		factory, ok := registry[name]
+		registryMu.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("validator: unknown rule %q", name)
+		}
+		rule := factory(strings.Fields(argStr))
+		if rule == nil {
+			return nil, fmt.Errorf("validator: invalid arguments %q for rule %q", argStr, name)
+		}
+		rules = append(rules, rule)
+	}
+	if optional {
+		for i, r := range rules {
+			rules[i] = r.Optional()
+		}
+	}
+	return rules, nil
+}
+
+// TagRules returns the Rules declared by the jorm_validate tags of value's struct type,
+// keyed by field name. Tags are parsed once per type.
+func TagRules(value any) (Rules, error) {
+	t := reflect.TypeOf(value)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("validator: value must be a struct or pointer to struct")
+	}
+	if e, ok := tagRules.Load(t); ok {
+		entry := e.(tagRulesEntry)
+		return entry.rules, entry.err
+	}
+	rules := make(Rules)
+	err := collectTagRules(t, rules)
+	tagRules.Store(t, tagRulesEntry{rules: rules, err: err})
+	return rules, err
+}
+
+// collectTagRules adds the tag rules of t's fields to rules, descending into embedded structs.
+func collectTagRules(t reflect.Type, rules Rules) error {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		tag, ok := sf.Tag.Lookup(TagName)
+		if !ok {
+			if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+				if err := collectTagRules(sf.Type, rules); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		fieldRules, err := ParseTag(tag)
+		if err != nil {
+			return fmt.Errorf("%w (field %s)", err, sf.Name)
+		}
+		if len(fieldRules) > 0 {
+			rules[sf.Name] = fieldRules
+		}
+	}
+	return nil
+}
+
+// Tags is a Validator checking value against the rules in its jorm_validate struct tags.
+// It can be passed to Query.InsertWithValidator and Query.UpdateWithValidator.
+func Tags(value any) error {
+	rules, err := TagRules(value)
+	if err != nil {
+		return err
+	}
+	return rules.Validate(value)
+}
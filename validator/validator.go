@@ -74,6 +74,13 @@ type Rule interface {
 	When(fn func(value any) bool) Rule
 }
 
+// FieldRule is a Rule that also needs the struct holding the field, e.g. to read the
+// values of sibling fields. Rules.Validate calls ValidateField instead of Validate for it.
+type FieldRule interface {
+	Rule
+	ValidateField(value any, parent reflect.Value) error
+}
+
 // BaseRule provides common functionality for all rules.
 type BaseRule struct {
 	msg      string
@@ -161,7 +168,13 @@ func (r Rules) Validate(value any) error {
 
 		val := field.Interface()
 		for _, rule := range rules {
-			if err := rule.Validate(val); err != nil {
+			var err error
+			if fr, ok := rule.(FieldRule); ok {
+				err = fr.ValidateField(val, rv)
+			} else {
+				err = rule.Validate(val)
+			}
+			if err != nil {
 				errors[fieldName] = append(errors[fieldName], err)
 			}
 		}